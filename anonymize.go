@@ -0,0 +1,78 @@
+package golog
+
+import "net"
+
+// IPAnonymizer zeroes the trailing bits of the IP addresses held in
+// the designated fields, for GDPR data minimization. Its Process
+// method is a Processor:
+//
+//	golog.AddProcessor(golog.IPAnonymizer{Fields: []string{"client_ip"}}.Process)
+type IPAnonymizer struct {
+	// Fields are the keys of the fields holding IP addresses, either
+	// as a string, optionally with a port, or as a net.IP.
+	Fields []string
+	// IPv4Bits is the number of trailing bits zeroed in IPv4
+	// addresses. Defaults to 8, the last octet.
+	IPv4Bits int
+	// IPv6Bits is the number of trailing bits zeroed in IPv6
+	// addresses. Defaults to 80, keeping the /48 prefix.
+	IPv6Bits int
+}
+
+// Process anonymizes the IP addresses of the entry. Values
+// that are not IP addresses are left untouched.
+func (a IPAnonymizer) Process(e *Entry) {
+	for _, key := range a.Fields {
+		switch v := e.Fields[key].(type) {
+		case string:
+			e.Fields[key] = a.anonymizeString(v)
+		case net.IP:
+			if ip := a.anonymize(v); ip != nil {
+				e.Fields[key] = ip
+			}
+		}
+	}
+}
+
+func (a IPAnonymizer) anonymizeString(s string) string {
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		host, port = s, ""
+	}
+	ip := a.anonymize(net.ParseIP(host))
+	if ip == nil {
+		return s
+	}
+	if port != "" {
+		return net.JoinHostPort(ip.String(), port)
+	}
+	return ip.String()
+}
+
+func (a IPAnonymizer) anonymize(ip net.IP) net.IP {
+	if ip4 := ip.To4(); ip4 != nil {
+		bits := a.IPv4Bits
+		if bits == 0 {
+			bits = 8
+		}
+		return ip4.Mask(net.CIDRMask(32-clampBits(bits, 32), 32))
+	}
+	if ip16 := ip.To16(); ip16 != nil {
+		bits := a.IPv6Bits
+		if bits == 0 {
+			bits = 80
+		}
+		return ip16.Mask(net.CIDRMask(128-clampBits(bits, 128), 128))
+	}
+	return nil
+}
+
+func clampBits(bits, max int) int {
+	if bits < 0 {
+		return 0
+	}
+	if bits > max {
+		return max
+	}
+	return bits
+}
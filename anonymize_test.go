@@ -0,0 +1,58 @@
+package golog
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPAnonymizer(t *testing.T) {
+	cases := []struct {
+		name  string
+		a     IPAnonymizer
+		input interface{}
+		want  interface{}
+	}{
+		{name: "ipv4 last octet", input: "203.0.113.42", want: "203.0.113.0"},
+		{name: "ipv4 with port", input: "203.0.113.42:8080", want: "203.0.113.0:8080"},
+		{name: "ipv4 two octets", a: IPAnonymizer{IPv4Bits: 16}, input: "203.0.113.42", want: "203.0.0.0"},
+		{name: "ipv6 keeps /48", input: "2001:db8:abcd:12:1:2:3:4", want: "2001:db8:abcd::"},
+		{name: "ipv6 with port", input: "[2001:db8:abcd:12::1]:443", want: "[2001:db8:abcd::]:443"},
+		{name: "net.IP value", input: net.ParseIP("198.51.100.7"), want: net.ParseIP("198.51.100.0").To4()},
+		{name: "not an ip", input: "unknown", want: "unknown"},
+		{name: "not a string", input: 42, want: 42},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.a.Fields = []string{"client_ip"}
+			e := &Entry{Fields: Fields{"client_ip": c.input, "other_ip": "203.0.113.42"}}
+			c.a.Process(e)
+			assert.Equal(t, c.want, e.Fields["client_ip"])
+			assert.Equal(t, "203.0.113.42", e.Fields["other_ip"])
+		})
+	}
+}
+
+func TestAddProcessor(t *testing.T) {
+	defer ResetProcessors()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	AddProcessor(IPAnonymizer{Fields: []string{"client_ip"}}.Process)
+
+	var out bytes.Buffer
+	parent := newStdLogger(InfoLevel, &out, 0).WithFields(Fields{"client_ip": "203.0.113.42"})
+	parent.Print("request")
+	assert.Equal(t, "INFO: request client_ip=203.0.113.0\n", out.String())
+
+	t.Run("the logger fields are not modified", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.42", parent.(*stdLogger).fields["client_ip"])
+	})
+	t.Run("processors run in order", func(t *testing.T) {
+		AddProcessor(func(e *Entry) { e.Message += " processed" })
+		out.Reset()
+		parent.Print("request")
+		assert.Equal(t, "INFO: request processed client_ip=203.0.113.0\n", out.String())
+	})
+}
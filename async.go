@@ -0,0 +1,269 @@
+package golog
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// Backpressure is the policy of an AsyncWriter when its queue is full.
+type Backpressure int
+
+const (
+	// Block makes the writes wait for room in the queue.
+	Block Backpressure = iota
+	// DropNewest discards the entry being written.
+	DropNewest
+	// DropOldest discards the oldest pending entry.
+	DropOldest
+)
+
+// defaultAsyncQueueSize is the queue size of an AsyncWriter
+// created with a non-positive size.
+const defaultAsyncQueueSize = 1024
+
+// AsyncWriter is a sink that queues the entries in a bounded channel
+// and writes them to the underlying writer on a background goroutine,
+// so a slow sink doesn't add latency to the logging calls. The entries
+// discarded by the backpressure policy are counted by Drops, and the
+// write errors are reported to the diagnostic handler.
+//
+// The entries of ErrorLevel, by default, are never discarded by the
+// DropNewest policy, which discards the oldest entry instead, and
+// flush the underlying writer once written, so they reach the sink
+// with minimum latency even behind a BufferedWriter. The entries of
+// Force are never discarded: they wait for room in the queue, and
+// while some are queued the DropOldest policy waits for room too
+// rather than discarding them.
+//
+//	w := golog.NewAsyncWriter(conn, 4096, golog.DropOldest)
+//	defer w.Close()
+//	golog.SetOutput(w)
+//
+// Call Close, or Sync, on shutdown so the pending entries
+// are not lost.
+type AsyncWriter struct {
+	w      io.Writer
+	policy Backpressure
+	queue  chan asyncEntry
+	done   chan struct{}
+
+	mu         sync.RWMutex // protects the fields below and the sends on queue
+	closed     bool
+	flushLevel Level
+
+	pendingMu sync.Mutex
+	pending   int
+	drained   *sync.Cond
+
+	// forced is the number of forced entries queued. It is only
+	// incremented with evictMu held, which the DropOldest policy
+	// holds to discard the oldest entry once none is queued.
+	forced  int32
+	evictMu sync.Mutex
+}
+
+// asyncEntry is an entry queued by an AsyncWriter.
+type asyncEntry struct {
+	b []byte
+	// leveled tells the entries written by WriteLevel.
+	leveled bool
+	lvl     Level
+	// urgent tells the entries at or above the flush level.
+	urgent bool
+	// forced tells the entries of Force, which wait for room
+	// in the queue whatever the policy.
+	forced bool
+}
+
+// NewAsyncWriter returns an AsyncWriter writing to w with a queue of
+// size entries, 1024 when size is not positive, applying policy when
+// the queue is full.
+func NewAsyncWriter(w io.Writer, size int, policy Backpressure) *AsyncWriter {
+	if size <= 0 {
+		size = defaultAsyncQueueSize
+	}
+	aw := &AsyncWriter{
+		w:          w,
+		policy:     policy,
+		queue:      make(chan asyncEntry, size),
+		done:       make(chan struct{}),
+		flushLevel: ErrorLevel,
+	}
+	aw.drained = sync.NewCond(&aw.pendingMu)
+	go aw.run()
+	registerSink(aw)
+	return aw
+}
+
+// SetFlushLevel sets the level from which the entries are kept
+// from the DropNewest policy and flush the underlying writer.
+// DisabledLevel handles all the entries alike.
+func (w *AsyncWriter) SetFlushLevel(lvl Level) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushLevel = lvl
+}
+
+// Write queues a copy of p. It only fails after Close.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	return w.write(asyncEntry{b: p})
+}
+
+// WriteLevel queues a copy of the entry p of lvl. It only fails
+// after Close.
+func (w *AsyncWriter) WriteLevel(lvl Level, p []byte) (int, error) {
+	return w.write(asyncEntry{b: p, leveled: true, lvl: lvl})
+}
+
+// writeForced queues a copy of the forced entry p of lvl,
+// waiting for room in the queue.
+func (w *AsyncWriter) writeForced(lvl Level, p []byte) (int, error) {
+	return w.write(asyncEntry{b: p, leveled: true, lvl: lvl, forced: true})
+}
+
+func (w *AsyncWriter) write(e asyncEntry) (int, error) {
+	n := len(e.b)
+	b := make([]byte, n)
+	copy(b, e.b)
+	e.b = b
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	w.addPending(1)
+	e.urgent = e.leveled && e.lvl < DisabledLevel && e.lvl >= w.flushLevel
+	policy := w.policy
+	if policy == DropNewest && e.urgent {
+		policy = DropOldest
+	}
+	if e.forced {
+		w.evictMu.Lock()
+		atomic.AddInt32(&w.forced, 1)
+		w.evictMu.Unlock()
+		policy = Block
+	}
+	switch policy {
+	case DropNewest:
+		select {
+		case w.queue <- e:
+		default:
+			w.drop()
+		}
+	case DropOldest:
+		for {
+			select {
+			case w.queue <- e:
+				return n, nil
+			default:
+			}
+			if !w.dropOldest() {
+				w.queue <- e
+				return n, nil
+			}
+		}
+	default:
+		w.queue <- e
+	}
+	return n, nil
+}
+
+// dropOldest discards the oldest queued entry, if any. It discards
+// nothing and returns false when forced entries are queued.
+func (w *AsyncWriter) dropOldest() bool {
+	w.evictMu.Lock()
+	defer w.evictMu.Unlock()
+	if atomic.LoadInt32(&w.forced) > 0 {
+		return false
+	}
+	select {
+	case <-w.queue:
+		w.drop()
+	default:
+	}
+	return true
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	for e := range w.queue {
+		if e.forced {
+			atomic.AddInt32(&w.forced, -1)
+		}
+		if err := w.writeEntry(e); err != nil {
+			countDrop()
+			reportf("golog: async writer: %v", err)
+		}
+		w.addPending(-1)
+	}
+}
+
+// writeEntry writes e to the underlying writer, and flushes it
+// after the urgent entries unless it is a LevelWriter handling
+// the level itself.
+func (w *AsyncWriter) writeEntry(e asyncEntry) error {
+	if !e.leveled {
+		_, err := w.w.Write(e.b)
+		return err
+	}
+	if lw, ok := w.w.(LevelWriter); ok {
+		_, err := lw.WriteLevel(e.lvl, e.b)
+		return err
+	}
+	if _, err := w.w.Write(e.b); err != nil {
+		return err
+	}
+	if f, ok := w.w.(flusher); ok && e.urgent {
+		return f.Flush()
+	}
+	return nil
+}
+
+func (w *AsyncWriter) drop() {
+	countDrop()
+	w.addPending(-1)
+}
+
+func (w *AsyncWriter) addPending(n int) {
+	w.pendingMu.Lock()
+	w.pending += n
+	if w.pending == 0 {
+		w.drained.Broadcast()
+	}
+	w.pendingMu.Unlock()
+}
+
+// Flush waits until every queued entry is written, then
+// flushes the underlying writer when it has a Flush method.
+func (w *AsyncWriter) Flush() error {
+	w.pendingMu.Lock()
+	for w.pending > 0 {
+		w.drained.Wait()
+	}
+	w.pendingMu.Unlock()
+	if f, ok := w.w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Close stops accepting entries and waits for the pending ones
+// to be written. The underlying writer is not closed.
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+	<-w.done
+	unregisterSink(w)
+	if f, ok := w.w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
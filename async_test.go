@@ -0,0 +1,135 @@
+package golog
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gateWriter blocks its first write until the gate is opened.
+type gateWriter struct {
+	started chan struct{}
+	gate    chan struct{}
+	once    sync.Once
+	out     syncBuffer
+}
+
+func newGateWriter() *gateWriter {
+	return &gateWriter{started: make(chan struct{}), gate: make(chan struct{})}
+}
+
+func (w *gateWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() {
+		close(w.started)
+		<-w.gate
+	})
+	return w.out.Write(p)
+}
+
+func TestAsyncWriter(t *testing.T) {
+	// fill writes "1\n" to "5\n" while the first
+	// one is blocked in the underlying writer.
+	fill := func(policy Backpressure) (*AsyncWriter, *gateWriter) {
+		gw := newGateWriter()
+		w := NewAsyncWriter(gw, 2, policy)
+		w.Write([]byte("1\n"))
+		<-gw.started
+		for _, s := range []string{"2\n", "3\n", "4\n", "5\n"} {
+			if policy == Block && s == "4\n" {
+				break
+			}
+			w.Write([]byte(s))
+		}
+		return w, gw
+	}
+
+	t.Run("block waits for room", func(t *testing.T) {
+		w, gw := fill(Block)
+		written := make(chan struct{})
+		go func() {
+			w.Write([]byte("4\n"))
+			close(written)
+		}()
+		select {
+		case <-written:
+			t.Fatal("write did not block")
+		case <-time.After(20 * time.Millisecond):
+		}
+		close(gw.gate)
+		<-written
+		require.NoError(t, w.Flush())
+		assert.Equal(t, "1\n2\n3\n4\n", gw.out.String())
+	})
+	t.Run("drop newest keeps the queued entries", func(t *testing.T) {
+		before := Drops()
+		w, gw := fill(DropNewest)
+		close(gw.gate)
+		require.NoError(t, w.Flush())
+		assert.Equal(t, "1\n2\n3\n", gw.out.String())
+		assert.Equal(t, uint64(2), Drops()-before)
+	})
+	t.Run("drop newest keeps the errors", func(t *testing.T) {
+		w, gw := fill(DropNewest)
+		w.WriteLevel(InfoLevel, []byte("6\n"))
+		w.WriteLevel(ErrorLevel, []byte("E\n"))
+		close(gw.gate)
+		require.NoError(t, w.Flush())
+		assert.Equal(t, "1\n3\nE\n", gw.out.String())
+	})
+	t.Run("errors flush the underlying writer", func(t *testing.T) {
+		defer SetLevel(InfoLevel)
+		SetLevel(InfoLevel)
+		cw := &countingWriter{}
+		bw := NewBufferedWriter(cw, 0, time.Hour)
+		defer bw.Close()
+		w := NewAsyncWriter(struct {
+			flusher
+			io.Writer
+		}{bw, bw}, 0, Block)
+		defer w.Close()
+		l := newStdLogger(InfoLevel, w, 0)
+		l.Info("one")
+		l.Error("two")
+		assert.Eventually(t, func() bool {
+			return len(cw.Writes()) == 1
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"INFO: one\nERROR: two\n"}, cw.Writes())
+	})
+	t.Run("drop oldest keeps the latest entries", func(t *testing.T) {
+		before := Drops()
+		w, gw := fill(DropOldest)
+		close(gw.gate)
+		require.NoError(t, w.Flush())
+		assert.Equal(t, "1\n4\n5\n", gw.out.String())
+		assert.Equal(t, uint64(2), Drops()-before)
+	})
+	t.Run("close drains the queue", func(t *testing.T) {
+		var out syncBuffer
+		w := NewAsyncWriter(&out, 0, Block)
+		l := newStdLogger(InfoLevel, w, 0)
+		SetLevel(InfoLevel)
+		for i := 0; i < 100; i++ {
+			l.Print("hello")
+		}
+		require.NoError(t, w.Close())
+		assert.Equal(t, 100, len(out.String())/len("INFO: hello\n"))
+
+		_, err := w.Write([]byte("late\n"))
+		assert.Equal(t, ErrWriterClosed, err)
+		assert.NoError(t, w.Close())
+	})
+	t.Run("write errors are reported", func(t *testing.T) {
+		var errs []error
+		SetDiagnosticHandler(func(err error) { errs = append(errs, err) })
+		defer SetDiagnosticHandler(nil)
+		w := NewAsyncWriter(failingWriter{}, 0, Block)
+		w.Write([]byte("lost\n"))
+		require.NoError(t, w.Close())
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "golog: async writer:")
+	})
+}
@@ -0,0 +1,214 @@
+package golog
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"time"
+)
+
+// flusher is implemented by the sinks buffering the entries.
+type flusher interface {
+	Flush() error
+}
+
+// LevelWriter is implemented by the sinks handling the entries
+// depending on their level, e.g. flushing the errors at once.
+// The loggers write their entries with WriteLevel when their
+// output implements it.
+type LevelWriter interface {
+	io.Writer
+	// WriteLevel writes the entry p of lvl.
+	WriteLevel(lvl Level, p []byte) (int, error)
+}
+
+// writeLevel writes the entry p of lvl to w, with
+// WriteLevel when w is a LevelWriter.
+func writeLevel(w io.Writer, lvl Level, p []byte) (int, error) {
+	if lw, ok := w.(LevelWriter); ok {
+		return lw.WriteLevel(lvl, p)
+	}
+	return w.Write(p)
+}
+
+var (
+	sinksMu sync.Mutex
+	// sinks are the buffering sinks flushed by Sync.
+	sinks = make(map[flusher]struct{})
+)
+
+func registerSink(f flusher) {
+	sinksMu.Lock()
+	sinks[f] = struct{}{}
+	sinksMu.Unlock()
+}
+
+func unregisterSink(f flusher) {
+	sinksMu.Lock()
+	delete(sinks, f)
+	sinksMu.Unlock()
+}
+
+// Sync flushes the open BufferedWriter and AsyncWriter sinks, and
+// the outputs of the package level loggers that have a Flush method,
+// so no entry is lost on a graceful shutdown. It returns the first
+// error.
+func Sync() error {
+	sinksMu.Lock()
+	fs := make([]flusher, 0, len(sinks)+len(packageLoggers()))
+	for f := range sinks {
+		fs = append(fs, f)
+	}
+	sinksMu.Unlock()
+	for _, l := range packageLoggers() {
+		if f, ok := LevelOutput(l.level).(flusher); ok {
+			fs = append(fs, f)
+		}
+	}
+
+	var err error
+	seen := make(map[flusher]bool, len(fs))
+	for _, f := range fs {
+		if seen[f] {
+			continue
+		}
+		seen[f] = true
+		if ferr := f.Flush(); ferr != nil && err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+// defaultBufferSize is the size of a BufferedWriter
+// created with a non-positive size.
+const defaultBufferSize = 64 << 10
+
+// BufferedWriter is a sink that buffers the entries in memory and
+// writes them to the underlying writer when the buffer is full and
+// every flush interval, so high-volume logging doesn't cost one
+// system call per entry. The entries are never split between two
+// writes, unless one is larger than the buffer. The entries of
+// ErrorLevel, by default, are flushed at once, so they are not
+// delayed by the batching. Write errors of the periodic flushes
+// are reported to the diagnostic handler.
+//
+//	w := golog.NewBufferedWriter(os.Stdout, 0, time.Second)
+//	defer w.Close()
+//	golog.SetOutput(w)
+//
+// Call Close, or Sync, on shutdown so the buffered entries are
+// not lost.
+type BufferedWriter struct {
+	mu         sync.Mutex
+	buf        *bufio.Writer
+	flushLevel Level
+	closed     bool
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// NewBufferedWriter returns a BufferedWriter writing to w with a
+// buffer of size bytes, 64KiB when size is not positive, flushed
+// every flushInterval. A non-positive flushInterval disables the
+// periodic flushes.
+func NewBufferedWriter(w io.Writer, size int, flushInterval time.Duration) *BufferedWriter {
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	bw := &BufferedWriter{
+		buf:        bufio.NewWriterSize(w, size),
+		flushLevel: ErrorLevel,
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	if flushInterval > 0 {
+		go bw.run(flushInterval)
+	} else {
+		close(bw.done)
+	}
+	registerSink(bw)
+	return bw
+}
+
+func (w *BufferedWriter) run(interval time.Duration) {
+	defer close(w.done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := w.Flush(); err != nil && err != ErrWriterClosed {
+				reportf("golog: buffered writer: %v", err)
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// SetFlushLevel makes the entries of lvl and above flush the
+// buffer at once. DisabledLevel batches all the entries.
+func (w *BufferedWriter) SetFlushLevel(lvl Level) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushLevel = lvl
+}
+
+// Write buffers p, flushing the buffer first when p doesn't fit.
+func (w *BufferedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.write(p)
+}
+
+// WriteLevel buffers the entry p of lvl, and flushes the
+// buffer when lvl is at or above the flush level.
+func (w *BufferedWriter) WriteLevel(lvl Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n, err := w.write(p)
+	if err == nil && lvl < DisabledLevel && lvl >= w.flushLevel {
+		err = w.buf.Flush()
+	}
+	return n, err
+}
+
+func (w *BufferedWriter) write(p []byte) (int, error) {
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	if len(p) > w.buf.Available() && w.buf.Buffered() > 0 {
+		if err := w.buf.Flush(); err != nil {
+			return 0, err
+		}
+	}
+	return w.buf.Write(p)
+}
+
+// Flush writes the buffered entries to the underlying writer.
+func (w *BufferedWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+	return w.buf.Flush()
+}
+
+// Close stops the periodic flushes and flushes the buffered
+// entries. The underlying writer is not closed.
+func (w *BufferedWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.buf.Flush()
+	w.mu.Unlock()
+	close(w.stop)
+	<-w.done
+	unregisterSink(w)
+	return err
+}
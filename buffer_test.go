@@ -0,0 +1,109 @@
+package golog
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingWriter records the writes it receives.
+type countingWriter struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func (w *countingWriter) Writes() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.writes...)
+}
+
+func TestBufferedWriter(t *testing.T) {
+	t.Run("buffers until flushed", func(t *testing.T) {
+		cw := &countingWriter{}
+		w := NewBufferedWriter(cw, 0, 0)
+		defer w.Close()
+		w.Write([]byte("one\n"))
+		w.Write([]byte("two\n"))
+		assert.Empty(t, cw.Writes())
+		require.NoError(t, w.Flush())
+		assert.Equal(t, []string{"one\ntwo\n"}, cw.Writes())
+	})
+	t.Run("entries are not split", func(t *testing.T) {
+		cw := &countingWriter{}
+		w := NewBufferedWriter(cw, 8, 0)
+		defer w.Close()
+		w.Write([]byte("one\n"))
+		w.Write([]byte("three\n"))
+		w.Write([]byte("a long entry\n"))
+		require.NoError(t, w.Flush())
+		assert.Equal(t, []string{"one\n", "three\n", "a long entry\n"}, cw.Writes())
+	})
+	t.Run("flushes periodically", func(t *testing.T) {
+		cw := &countingWriter{}
+		w := NewBufferedWriter(cw, 0, 10*time.Millisecond)
+		defer w.Close()
+		w.Write([]byte("one\n"))
+		assert.Eventually(t, func() bool { return len(cw.Writes()) == 1 }, time.Second, 5*time.Millisecond)
+	})
+	t.Run("close flushes", func(t *testing.T) {
+		cw := &countingWriter{}
+		w := NewBufferedWriter(cw, 0, time.Hour)
+		w.Write([]byte("one\n"))
+		require.NoError(t, w.Close())
+		assert.Equal(t, []string{"one\n"}, cw.Writes())
+
+		_, err := w.Write([]byte("late\n"))
+		assert.Equal(t, ErrWriterClosed, err)
+		assert.NoError(t, w.Close())
+	})
+	t.Run("errors are flushed at once", func(t *testing.T) {
+		defer SetLevel(InfoLevel)
+		SetLevel(InfoLevel)
+		cw := &countingWriter{}
+		w := NewBufferedWriter(cw, 0, time.Hour)
+		defer w.Close()
+		l := newStdLogger(InfoLevel, w, 0)
+		l.Info("one")
+		assert.Empty(t, cw.Writes())
+		l.Error("two")
+		assert.Equal(t, []string{"INFO: one\nERROR: two\n"}, cw.Writes())
+
+		w.SetFlushLevel(DisabledLevel)
+		l.Error("three")
+		assert.Len(t, cw.Writes(), 1)
+	})
+}
+
+func TestSync(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+
+	output, sink := &countingWriter{}, &countingWriter{}
+	bw := NewBufferedWriter(output, 0, 0)
+	defer bw.Close()
+	SetOutput(bw)
+	inner := NewBufferedWriter(sink, 0, 0)
+	defer inner.Close()
+	aw := NewAsyncWriter(inner, 0, Block)
+	defer aw.Close()
+
+	Info("hello")
+	aw.Write([]byte("queued\n"))
+	require.NoError(t, Sync())
+	assert.True(t, strings.HasSuffix(strings.Join(output.Writes(), ""), "hello\n"))
+	assert.Equal(t, []string{"queued\n"}, sink.Writes())
+}
@@ -0,0 +1,176 @@
+package golog
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BundleKey is the field key holding the debug entries
+// bundled with an error by SetContextBundling.
+const BundleKey = "context"
+
+// maxBundles is the number of values whose entries are kept
+// by the context bundling. The least recently logged value is
+// forgotten first.
+const maxBundles = 1024
+
+// SetContextBundling keeps the last size Debug and Trace entries of
+// every value of the field key, e.g. a request ID, and attaches them
+// to the next Error entry with the same value as the BundleKey field,
+// one "15:04:05.000 DEBUG message fields" element per entry from the
+// oldest, so the error comes with the context leading to it:
+//
+//	golog.SetContextBundling("request_id", 20)
+//
+// The key is looked up in the fields of the logger, of its context
+// and, for Debugw and Tracew, of the call. The entries are kept even
+// when their level is disabled, in which case they are not written
+// otherwise, so their message is built although it may never be
+// written. A non-positive size or an empty key disables the bundling.
+func SetContextBundling(key string, size int) {
+	var b *bundler
+	if key != "" && size > 0 {
+		b = &bundler{
+			key:    key,
+			size:   size,
+			values: make(map[string]*list.Element),
+			lru:    list.New(),
+		}
+	}
+	updateState(func(s *globalState) {
+		s.bundle = b
+	})
+}
+
+// bundler keeps the last debug entries per value of its key.
+type bundler struct {
+	key  string
+	size int
+
+	mu     sync.Mutex
+	values map[string]*list.Element
+	lru    *list.List // of *bundle, the most recently logged first
+}
+
+// bundle holds the last entries of a value in a ring.
+type bundle struct {
+	value   string
+	entries []bundledEntry
+	next    int
+}
+
+type bundledEntry struct {
+	t      time.Time
+	lvl    Level
+	msg    string
+	fields Fields
+}
+
+// keeps reports whether the entries of lvl with any of fields
+// are kept by b.
+func (b *bundler) keeps(lvl Level, fields ...Fields) bool {
+	if lvl >= InfoLevel {
+		return false
+	}
+	for _, f := range fields {
+		if _, ok := f[b.key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bundlesFields reports whether the entries of lvl with the fields
+// of a call are kept by the context bundling.
+func bundlesFields(lvl Level, fields []Field) bool {
+	b := getState().bundle
+	if b == nil || lvl >= InfoLevel {
+		return false
+	}
+	for _, f := range fields {
+		if f.Key == b.key && f.kind != skipKind {
+			return true
+		}
+	}
+	return false
+}
+
+// add keeps e when it is a debug entry with the key of b,
+// and reports whether it did.
+func (b *bundler) add(e *Entry) bool {
+	v, ok := e.Fields[b.key]
+	if !ok || e.Level >= InfoLevel {
+		return false
+	}
+	value := bundleValue(v)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var bd *bundle
+	if el, ok := b.values[value]; ok {
+		b.lru.MoveToFront(el)
+		bd = el.Value.(*bundle)
+	} else {
+		if b.lru.Len() >= maxBundles {
+			oldest := b.lru.Remove(b.lru.Back()).(*bundle)
+			delete(b.values, oldest.value)
+		}
+		bd = &bundle{value: value}
+		b.values[value] = b.lru.PushFront(bd)
+	}
+	be := bundledEntry{t: e.Time, lvl: e.Level, msg: e.Message, fields: e.Fields}
+	if len(bd.entries) < b.size {
+		bd.entries = append(bd.entries, be)
+	} else {
+		bd.entries[bd.next] = be
+		bd.next = (bd.next + 1) % b.size
+	}
+	return true
+}
+
+// take removes and returns the entries kept for the value of the
+// key of the error entry e, formatted from the oldest.
+func (b *bundler) take(e *Entry) []string {
+	v, ok := e.Fields[b.key]
+	if !ok || e.Level < ErrorLevel {
+		return nil
+	}
+	value := bundleValue(v)
+	b.mu.Lock()
+	el, ok := b.values[value]
+	if ok {
+		b.lru.Remove(el)
+		delete(b.values, value)
+	}
+	b.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	bd := el.Value.(*bundle)
+	lines := make([]string, 0, len(bd.entries))
+	for i := range bd.entries {
+		be := bd.entries[(bd.next+i)%len(bd.entries)]
+		line := appendTime(nil, be.t, layoutClockMilli)
+		line = append(line, ' ')
+		line = append(line, strings.ToUpper(be.lvl.name())...)
+		line = append(line, ' ')
+		line = append(line, be.msg...)
+		for _, k := range be.fields.sortedKeys() {
+			if k != b.key {
+				line = appendTextField(line, k, be.fields[k])
+			}
+		}
+		lines = append(lines, string(line))
+	}
+	return lines
+}
+
+// bundleValue returns the value v of the key in text form.
+func bundleValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
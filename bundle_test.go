@@ -0,0 +1,104 @@
+package golog
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetContextBundling(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	SetContextBundling("request_id", 2)
+	defer SetContextBundling("", 0)
+
+	decode := func(t *testing.T, out string) []map[string]interface{} {
+		var entries []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			var m map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &m), line)
+			entries = append(entries, m)
+		}
+		return entries
+	}
+
+	t.Run("bundles the last entries with the error", func(t *testing.T) {
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0)
+		l.SetFormatter(&JSONFormatter{})
+		a := l.WithFields(Fields{"request_id": "a"})
+		b := l.WithFields(Fields{"request_id": "b"})
+		a.Debug("one")
+		a.Debugw("two", Int("n", 2))
+		b.Debug("other")
+		l.Debug("unrelated")
+		a.Trace("three")
+		a.Info("info")
+		a.Error("failed")
+		a.Error("failed again")
+
+		entries := decode(t, out.String())
+		require.Len(t, entries, 3)
+		assert.Equal(t, "info", entries[0]["msg"])
+		assert.Nil(t, entries[0][BundleKey])
+		assert.Equal(t, "failed", entries[1]["msg"])
+		bundled := entries[1][BundleKey].([]interface{})
+		require.Len(t, bundled, 2)
+		assert.Regexp(t, `^\d\d:\d\d:\d\d\.\d{3} DEBUG two n=2$`, bundled[0])
+		assert.Regexp(t, `^\d\d:\d\d:\d\d\.\d{3} TRACE three$`, bundled[1])
+		assert.Nil(t, entries[2][BundleKey], "the bundle is taken by the first error")
+	})
+
+	t.Run("call and context fields", func(t *testing.T) {
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0)
+		l.SetFormatter(&JSONFormatter{})
+		l.Debugw("call", String("request_id", "c"))
+		ctx := WithContext(ContextWithFields(context.Background(), Fields{"request_id": "c"}), l)
+		FromContext(ctx).Debug("context")
+		FromContext(ctx).Error("failed")
+
+		entries := decode(t, out.String())
+		require.Len(t, entries, 1)
+		bundled := entries[0][BundleKey].([]interface{})
+		require.Len(t, bundled, 2)
+		assert.Contains(t, bundled[0], "DEBUG call")
+		assert.Contains(t, bundled[1], "DEBUG context")
+	})
+
+	t.Run("enabled debug entries are written too", func(t *testing.T) {
+		SetLevel(DebugLevel)
+		defer SetLevel(InfoLevel)
+		var out syncBuffer
+		l := newStdLogger(DebugLevel, &out, 0).WithFields(Fields{"request_id": "d"})
+		l.Debug("step")
+		l.Error("failed")
+		assert.Regexp(t, `^DEBUG: step request_id=d\nERROR: failed context="\d\d:\d\d:\d\d\.\d{3} DEBUG step" request_id=d\n$`, out.String())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		SetContextBundling("request_id", 0)
+		defer SetContextBundling("request_id", 2)
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0).WithFields(Fields{"request_id": "e"})
+		l.Debug("step")
+		l.Error("failed")
+		assert.Equal(t, "ERROR: failed request_id=e\n", out.String())
+	})
+}
+
+func TestBundler_Evicts(t *testing.T) {
+	b := &bundler{key: "id", size: 1, values: make(map[string]*list.Element), lru: list.New()}
+	for i := 0; i <= maxBundles; i++ {
+		assert.True(t, b.add(&Entry{Level: DebugLevel, Message: "m", Fields: Fields{"id": i}}))
+	}
+	assert.Equal(t, maxBundles, b.lru.Len())
+	assert.Nil(t, b.take(&Entry{Level: ErrorLevel, Fields: Fields{"id": 0}}), "the oldest value is forgotten")
+	assert.Len(t, b.take(&Entry{Level: ErrorLevel, Fields: Fields{"id": maxBundles}}), 1)
+	assert.False(t, b.add(&Entry{Level: InfoLevel, Fields: Fields{"id": 1}}))
+}
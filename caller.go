@@ -0,0 +1,143 @@
+package golog
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CallerFormat selects how the formatters report the call site of
+// the entries. The flags are or'ed together, e.g.
+// CallerModulePath|CallerFunction.
+type CallerFormat int
+
+const (
+	// CallerShortFile reports the file base name and line: file.go:12.
+	CallerShortFile CallerFormat = 1 << iota
+	// CallerFullPath reports the full path of the file and the line:
+	// /home/user/src/project/pkg/file.go:12.
+	CallerFullPath
+	// CallerModulePath reports the path of the file relative to the
+	// root of its module and the line: pkg/file.go:12. The file is
+	// reported by its full path when the module is unknown.
+	CallerModulePath
+	// CallerFunction reports the name of the function, qualified by
+	// its package name: pkg.(*Server).Handle.
+	CallerFunction
+)
+
+// appendCaller appends the call site of f as selected by format.
+// CallerModulePath wins over CallerFullPath, which wins over
+// CallerShortFile. Without any of them, the file is not reported.
+func appendCaller(b []byte, f *runtime.Frame, format CallerFormat) []byte {
+	file, line, fn := "???", 0, "???"
+	if f != nil {
+		file, line = f.File, f.Line
+		if f.Function != "" {
+			fn = f.Function
+		}
+	}
+	withFile := true
+	switch {
+	case f == nil:
+	case format&CallerModulePath != 0:
+		file = moduleRelativePath(f)
+	case format&CallerFullPath != 0:
+	case format&CallerShortFile != 0:
+		file = filepath.Base(file)
+	default:
+		withFile = false
+	}
+	if withFile {
+		b = append(b, file...)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(line), 10)
+	}
+	if format&CallerFunction != 0 {
+		if withFile {
+			b = append(b, ' ')
+		}
+		b = append(b, functionName(fn)...)
+	}
+	return b
+}
+
+// functionName returns the function fn, qualified by
+// the name of its package instead of its import path.
+func functionName(fn string) string {
+	return fn[strings.LastIndexByte(fn, '/')+1:]
+}
+
+// modulePaths caches the module relative paths per file.
+var modulePaths sync.Map
+
+// moduleRelativePath returns the path of the file of f relative to
+// the root of its module. The module is found from the import path
+// of the package of the function, or else from the go.mod file in
+// the parent directories of the file.
+func moduleRelativePath(f *runtime.Frame) string {
+	if p, ok := modulePaths.Load(f.File); ok {
+		return p.(string)
+	}
+	p := importRelativePath(f)
+	if p == "" {
+		p = goModRelativePath(f.File)
+	}
+	if p == "" {
+		p = f.File
+	}
+	modulePaths.Store(f.File, p)
+	return p
+}
+
+// importRelativePath returns the path of the file of f relative to
+// the root of the module providing the package of the function, or
+// "" when the module is unknown. The package main has no import path
+// telling its directory.
+func importRelativePath(f *runtime.Frame) string {
+	if strings.HasPrefix(f.Function, "main.") {
+		return ""
+	}
+	pkg := packagePath(f.Function)
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	mod := ""
+	if within(pkg, bi.Main.Path) {
+		mod = bi.Main.Path
+	}
+	for _, m := range bi.Deps {
+		if within(pkg, m.Path) && len(m.Path) > len(mod) {
+			mod = m.Path
+		}
+	}
+	if mod == "" {
+		return ""
+	}
+	return path.Join(strings.TrimPrefix(pkg[len(mod):], "/"), filepath.Base(f.File))
+}
+
+// goModRelativePath returns the path of file relative to the closest
+// parent directory holding a go.mod file, or "" when there is none.
+func goModRelativePath(file string) string {
+	for dir := filepath.Dir(file); ; {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			rel, err := filepath.Rel(dir, file)
+			if err != nil {
+				return ""
+			}
+			return filepath.ToSlash(rel)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
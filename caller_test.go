@@ -0,0 +1,81 @@
+package golog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCallerFormat(t *testing.T) {
+	frame := &runtime.Frame{
+		File:     "/src/project/internal/api/server.go",
+		Line:     12,
+		Function: "example.com/project/internal/api.(*Server).Handle",
+	}
+	e := &Entry{Level: InfoLevel, Message: "hello", Caller: frame}
+
+	tests := []struct {
+		format CallerFormat
+		want   string
+	}{
+		{CallerShortFile, "INFO: server.go:12: hello\n"},
+		{CallerFullPath, "INFO: /src/project/internal/api/server.go:12: hello\n"},
+		{CallerShortFile | CallerFullPath, "INFO: /src/project/internal/api/server.go:12: hello\n"},
+		{CallerFunction, "INFO: api.(*Server).Handle: hello\n"},
+		{CallerShortFile | CallerFunction, "INFO: server.go:12 api.(*Server).Handle: hello\n"},
+	}
+	for _, tt := range tests {
+		b, err := (&TextFormatter{Caller: tt.format}).Format(e)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, string(b))
+	}
+
+	t.Run("unknown caller", func(t *testing.T) {
+		b, _ := (&TextFormatter{Caller: CallerModulePath | CallerFunction}).Format(&Entry{Level: InfoLevel, Message: "hello"})
+		assert.Equal(t, "INFO: ???:0 ???: hello\n", string(b))
+	})
+
+	t.Run("module path", func(t *testing.T) {
+		_, file, line, _ := runtime.Caller(0)
+		pc, _, _, _ := runtime.Caller(0)
+		frame := &runtime.Frame{File: file, Line: line, Function: runtime.FuncForPC(pc).Name()}
+		b, _ := (&TextFormatter{Caller: CallerModulePath}).Format(&Entry{Level: InfoLevel, Message: "hello", Caller: frame})
+		assert.Regexp(t, `^INFO: caller_test\.go:\d+: hello\n$`, string(b))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		f := &JSONFormatter{Caller: CallerFunction}
+		b, err := f.Format(&Entry{Level: InfoLevel, Message: "hello", Caller: frame, Fields: Fields{"function": "x"}})
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `"caller":"server.go:12","function":"api.(*Server).Handle","msg":"hello","fields.function":"x"}`)
+
+		b, _ = (&JSONFormatter{}).Format(&Entry{Level: InfoLevel, Message: "hello", Caller: frame, Fields: Fields{"function": "x"}})
+		assert.Contains(t, string(b), `"caller":"server.go:12","msg":"hello","function":"x"}`)
+	})
+
+	t.Run("reported by the loggers", func(t *testing.T) {
+		defer SetLevel(InfoLevel)
+		SetLevel(InfoLevel)
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0)
+		l.SetFormatter(&TextFormatter{Caller: CallerShortFile | CallerFunction})
+		l.Print("hello")
+		assert.Regexp(t, `^INFO: caller_test\.go:\d+ golog\.TestCallerFormat\.func\d+: hello\n$`, out.String())
+	})
+}
+
+func TestGoModRelativePath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "cmd", "tool"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/tool\n"), 0644))
+
+	assert.Equal(t, "cmd/tool/main.go", goModRelativePath(filepath.Join(dir, "cmd", "tool", "main.go")))
+	assert.Equal(t, "", goModRelativePath("/nonexistent/main.go"))
+}
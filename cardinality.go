@@ -0,0 +1,100 @@
+package golog
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CardinalityOverflow replaces the values of the fields
+// blocked by a CardinalityGuard.
+const CardinalityOverflow = "OVERFLOW"
+
+// CardinalityGuard tracks the distinct values of the fields that
+// become labels in the log backends, e.g. Loki or Datadog, and
+// catches the label explosions caused by unbounded values such as
+// user ids. When a key exceeds MaxValues, it is reported once to
+// the diagnostic handler and to OnExceeded and, with Block, its new
+// values are replaced by CardinalityOverflow. Its Process method is
+// a Processor:
+//
+//	guard := &golog.CardinalityGuard{Keys: []string{"route", "tenant"}, Block: true}
+//	golog.AddProcessor(guard.Process)
+//
+// The fields must be set before the first call to Process.
+type CardinalityGuard struct {
+	// Keys are the keys of the label-like fields.
+	Keys []string
+	// MaxValues is the number of distinct values allowed per
+	// key. Defaults to 100.
+	MaxValues int
+	// Block replaces the values exceeding MaxValues.
+	Block bool
+	// OnExceeded is called, without holding any lock, the
+	// first time key exceeds MaxValues.
+	OnExceeded func(key string, max int)
+
+	mu       sync.Mutex
+	values   map[string]map[string]struct{}
+	exceeded map[string]bool
+}
+
+// Process checks the label-like fields of the entry.
+func (g *CardinalityGuard) Process(e *Entry) {
+	for _, key := range g.Keys {
+		v, ok := e.Fields[key]
+		if !ok {
+			continue
+		}
+		known, first := g.track(key, fmt.Sprint(v))
+		if first {
+			reportf("golog: field %q exceeded %d distinct values", key, g.max())
+			if g.OnExceeded != nil {
+				g.OnExceeded(key, g.max())
+			}
+		}
+		if !known && g.Block {
+			e.Fields[key] = CardinalityOverflow
+		}
+	}
+}
+
+// track records the value s of key. It reports whether s is within
+// the allowed values and whether key exceeded them for the first time.
+func (g *CardinalityGuard) track(key, s string) (known, first bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.values == nil {
+		g.values = make(map[string]map[string]struct{})
+		g.exceeded = make(map[string]bool)
+	}
+	set := g.values[key]
+	if set == nil {
+		set = make(map[string]struct{})
+		g.values[key] = set
+	}
+	if _, ok := set[s]; ok {
+		return true, false
+	}
+	if len(set) < g.max() {
+		set[s] = struct{}{}
+		return true, false
+	}
+	first = !g.exceeded[key]
+	g.exceeded[key] = true
+	return false, first
+}
+
+func (g *CardinalityGuard) max() int {
+	if g.MaxValues <= 0 {
+		return 100
+	}
+	return g.MaxValues
+}
+
+// Cardinality returns the number of distinct values of key seen
+// by the guard, capped at MaxValues, and whether key exceeded them.
+func (g *CardinalityGuard) Cardinality(key string) (n int, exceeded bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.values[key]), g.exceeded[key]
+}
@@ -0,0 +1,52 @@
+package golog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalityGuard(t *testing.T) {
+	var errs []error
+	SetDiagnosticHandler(func(err error) { errs = append(errs, err) })
+	defer SetDiagnosticHandler(nil)
+
+	process := func(g *CardinalityGuard, tenant string) *Entry {
+		e := &Entry{Fields: Fields{"tenant": tenant, "user": tenant}}
+		g.Process(e)
+		return e
+	}
+
+	t.Run("warns once and keeps the values", func(t *testing.T) {
+		errs = nil
+		var exceeded []string
+		g := &CardinalityGuard{Keys: []string{"tenant"}, MaxValues: 2, OnExceeded: func(key string, max int) {
+			exceeded = append(exceeded, fmt.Sprintf("%s/%d", key, max))
+		}}
+		for _, tenant := range []string{"a", "b", "a", "c", "d"} {
+			assert.Equal(t, tenant, process(g, tenant).Fields["tenant"])
+		}
+		assert.Equal(t, []string{"tenant/2"}, exceeded)
+		require.Len(t, errs, 1)
+		assert.EqualError(t, errs[0], `golog: field "tenant" exceeded 2 distinct values`)
+
+		n, over := g.Cardinality("tenant")
+		assert.Equal(t, 2, n)
+		assert.True(t, over)
+		n, over = g.Cardinality("user")
+		assert.Zero(t, n)
+		assert.False(t, over)
+	})
+	t.Run("blocks the new values", func(t *testing.T) {
+		g := &CardinalityGuard{Keys: []string{"tenant"}, MaxValues: 2, Block: true}
+		var got []interface{}
+		for _, tenant := range []string{"a", "b", "c", "a"} {
+			e := process(g, tenant)
+			got = append(got, e.Fields["tenant"])
+			assert.Equal(t, tenant, e.Fields["user"])
+		}
+		assert.Equal(t, []interface{}{"a", "b", CardinalityOverflow, "a"}, got)
+	})
+}
@@ -0,0 +1,214 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// ChecksumMode selects how entries are checksummed.
+type ChecksumMode int
+
+const (
+	// ChecksumCRC32 appends the CRC-32 of every entry, detecting
+	// truncated and corrupted entries.
+	ChecksumCRC32 ChecksumMode = iota
+	// ChecksumChain appends a SHA-256 hash chained with the hash of
+	// the previous entry, so removed, reordered or tampered entries
+	// are detected too. Use it for audit files.
+	ChecksumChain
+)
+
+const (
+	crc32Marker = " #crc32="
+	chainMarker = " #chain="
+	// sealPrefix starts the last line written by ChecksumWriter.Close,
+	// followed by the number of entries.
+	sealPrefix = "#seal entries="
+)
+
+// ErrChecksumUnsealed is returned by the verification of a log which
+// does not end with the seal written by ChecksumWriter.Close, because
+// it was truncated or is still written to.
+var ErrChecksumUnsealed = errors.New("golog: checksums not sealed, the log may be truncated")
+
+// ChecksumError reports the first entry that failed verification.
+type ChecksumError struct {
+	Line int
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("golog: checksum mismatch at line %d", e.Line)
+}
+
+// ChecksumWriter appends a checksum to every entry written to the
+// underlying writer, and seals the log on Close with the number of
+// entries, so the entries removed from its end are detected too. A
+// chain always starts at the first entry written, so use one file per
+// ChecksumWriter in ChecksumChain mode.
+type ChecksumWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	mode   ChecksumMode
+	prev   [sha256.Size]byte
+	n      int
+	closed bool
+}
+
+// NewChecksumWriter returns a ChecksumWriter writing to w.
+func NewChecksumWriter(w io.Writer, mode ChecksumMode) *ChecksumWriter {
+	return &ChecksumWriter{w: w, mode: mode}
+}
+
+// Write writes every line of p followed by its checksum. The chain
+// only advances when the lines are written.
+func (w *ChecksumWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	var buf []byte
+	prev, n := w.prev, w.n
+	for _, line := range bytes.SplitAfter(p, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		line = bytes.TrimSuffix(line, []byte("\n"))
+		buf = append(buf, line...)
+		buf, prev = appendChecksum(buf, w.mode, prev, line)
+		buf = append(buf, '\n')
+		n++
+	}
+	if _, err := w.w.Write(buf); err != nil {
+		return 0, err
+	}
+	w.prev, w.n = prev, n
+	return len(p), nil
+}
+
+// Close writes the seal of the log, the number of entries with its
+// checksum. Writes after Close fail with ErrWriterClosed. The
+// underlying writer is not closed.
+func (w *ChecksumWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	seal := []byte(sealPrefix + strconv.Itoa(w.n))
+	buf, _ := appendChecksum(append([]byte(nil), seal...), w.mode, w.prev, seal)
+	_, err := w.w.Write(append(buf, '\n'))
+	return err
+}
+
+// appendChecksum appends the checksum of line to dst, and returns
+// it with the hash of the chain once line is appended to prev.
+func appendChecksum(dst []byte, mode ChecksumMode, prev [sha256.Size]byte, line []byte) ([]byte, [sha256.Size]byte) {
+	if mode == ChecksumChain {
+		prev = chainHash(prev, line)
+		dst = append(dst, chainMarker...)
+		return append(dst, hex.EncodeToString(prev[:])...), prev
+	}
+	dst = append(dst, crc32Marker...)
+	return append(dst, fmt.Sprintf("%08x", crc32.ChecksumIEEE(line))...), prev
+}
+
+func chainHash(prev [sha256.Size]byte, line []byte) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write(prev[:])
+	h.Write(line)
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
+
+// ChecksumVerifier verifies the lines written by a ChecksumWriter
+// one at a time, for the readers of the logs, e.g. the reader
+// subpackage. See VerifyChecksums.
+type ChecksumVerifier struct {
+	mode   ChecksumMode
+	prev   [sha256.Size]byte
+	line   int
+	n      int
+	sealed bool
+}
+
+// NewChecksumVerifier returns a ChecksumVerifier of the lines
+// checksummed with mode.
+func NewChecksumVerifier(mode ChecksumMode) *ChecksumVerifier {
+	return &ChecksumVerifier{mode: mode}
+}
+
+// Verify verifies the next line, without its newline, and returns its
+// entry without the checksum, or nil for the seal. The error is a
+// *ChecksumError when the line does not match its checksum, or follows
+// the seal.
+func (v *ChecksumVerifier) Verify(line []byte) ([]byte, error) {
+	v.line++
+	marker := crc32Marker
+	if v.mode == ChecksumChain {
+		marker = chainMarker
+	}
+	i := bytes.LastIndex(line, []byte(marker))
+	if v.sealed || i < 0 {
+		return nil, &ChecksumError{Line: v.line}
+	}
+	entry, sum := line[:i], string(line[i+len(marker):])
+	var want []byte
+	want, v.prev = appendChecksum(nil, v.mode, v.prev, entry)
+	if sum != string(want[len(marker):]) {
+		return nil, &ChecksumError{Line: v.line}
+	}
+	if bytes.HasPrefix(entry, []byte(sealPrefix)) {
+		n, err := strconv.Atoi(string(entry[len(sealPrefix):]))
+		if err != nil || n != v.n {
+			return nil, &ChecksumError{Line: v.line}
+		}
+		v.sealed = true
+		return nil, nil
+	}
+	v.n++
+	return entry, nil
+}
+
+// Entries returns the number of entries verified.
+func (v *ChecksumVerifier) Entries() int {
+	return v.n
+}
+
+// Close returns ErrChecksumUnsealed unless the last line
+// verified was the seal.
+func (v *ChecksumVerifier) Close() error {
+	if !v.sealed {
+		return ErrChecksumUnsealed
+	}
+	return nil
+}
+
+// VerifyChecksums reads the log written by a ChecksumWriter from r and
+// returns the number of verified entries. The returned error is a
+// *ChecksumError when an entry does not match its checksum, and
+// ErrChecksumUnsealed when the log does not end with its seal.
+func VerifyChecksums(r io.Reader, mode ChecksumMode) (int, error) {
+	v := NewChecksumVerifier(mode)
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxFrameSize)
+	for s.Scan() {
+		if _, err := v.Verify(s.Bytes()); err != nil {
+			return v.Entries(), err
+		}
+	}
+	if err := s.Err(); err != nil {
+		return v.Entries(), err
+	}
+	return v.Entries(), v.Close()
+}
@@ -0,0 +1,117 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyWriter fails the writes while fail is set.
+type flakyWriter struct {
+	bytes.Buffer
+	fail bool
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.fail {
+		return 0, errors.New("disk full")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestChecksumWriter(t *testing.T) {
+	write := func(mode ChecksumMode, lines ...string) string {
+		var out bytes.Buffer
+		w := NewChecksumWriter(&out, mode)
+		l := newStdLogger(InfoLevel, w, 0)
+		SetLevel(InfoLevel)
+		for _, line := range lines {
+			l.Println(line)
+		}
+		require.NoError(t, w.Close())
+		return out.String()
+	}
+
+	for _, mode := range []ChecksumMode{ChecksumCRC32, ChecksumChain} {
+		t.Run("valid entries are verified", func(t *testing.T) {
+			out := write(mode, "first", "second", "third")
+			n, err := VerifyChecksums(strings.NewReader(out), mode)
+			assert.NoError(t, err)
+			assert.Equal(t, 3, n)
+		})
+		t.Run("tampered entry is detected", func(t *testing.T) {
+			out := write(mode, "first", "second", "third")
+			out = strings.Replace(out, "second", "Second", 1)
+			n, err := VerifyChecksums(strings.NewReader(out), mode)
+			assert.Equal(t, &ChecksumError{Line: 2}, err)
+			assert.Equal(t, 1, n)
+		})
+		t.Run("truncated entry is detected", func(t *testing.T) {
+			out := write(mode, "first", "second")
+			lines := strings.SplitAfter(out, "\n")
+			_, err := VerifyChecksums(strings.NewReader(lines[0]+lines[1][:len(lines[1])-5]), mode)
+			assert.Equal(t, &ChecksumError{Line: 2}, err)
+		})
+		t.Run("truncated log is detected", func(t *testing.T) {
+			out := write(mode, "first", "second", "third")
+			lines := strings.SplitAfter(out, "\n")
+			n, err := VerifyChecksums(strings.NewReader(strings.Join(lines[:3], "")), mode)
+			assert.Equal(t, ErrChecksumUnsealed, err)
+			assert.Equal(t, 3, n)
+			n, err = VerifyChecksums(strings.NewReader(strings.Join(lines[:2], "")), mode)
+			assert.Equal(t, ErrChecksumUnsealed, err)
+			assert.Equal(t, 2, n)
+		})
+		t.Run("entries after the seal are detected", func(t *testing.T) {
+			out := write(mode, "first")
+			_, err := VerifyChecksums(strings.NewReader(out+write(mode, "appended")), mode)
+			assert.Equal(t, &ChecksumError{Line: 3}, err)
+		})
+	}
+	t.Run("removed entry is detected", func(t *testing.T) {
+		out := write(ChecksumChain, "first", "second", "third")
+		lines := strings.SplitAfter(out, "\n")
+		out = lines[0] + lines[2] + lines[3]
+		_, err := VerifyChecksums(strings.NewReader(out), ChecksumChain)
+		assert.Equal(t, &ChecksumError{Line: 2}, err)
+
+		// The CRC-32 detects it with the number of entries of the seal.
+		out = write(ChecksumCRC32, "first", "second", "third")
+		lines = strings.SplitAfter(out, "\n")
+		_, err = VerifyChecksums(strings.NewReader(lines[0]+lines[2]+lines[3]), ChecksumCRC32)
+		assert.Equal(t, &ChecksumError{Line: 3}, err)
+	})
+	t.Run("every line of a write is checksummed", func(t *testing.T) {
+		var out bytes.Buffer
+		w := NewChecksumWriter(&out, ChecksumCRC32)
+		n, err := w.Write([]byte("a\nb\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 4, n)
+		require.NoError(t, w.Close())
+		got, err := VerifyChecksums(&out, ChecksumCRC32)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, got)
+		_, err = w.Write([]byte("c\n"))
+		assert.Equal(t, ErrWriterClosed, err)
+	})
+	t.Run("failed writes keep the chain", func(t *testing.T) {
+		out := &flakyWriter{}
+		w := NewChecksumWriter(out, ChecksumChain)
+		_, err := w.Write([]byte("first\n"))
+		require.NoError(t, err)
+		out.fail = true
+		_, err = w.Write([]byte("lost\n"))
+		assert.Error(t, err)
+		out.fail = false
+		_, err = w.Write([]byte("second\n"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		n, err := VerifyChecksums(&out.Buffer, ChecksumChain)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, n)
+	})
+}
@@ -0,0 +1,97 @@
+package golog
+
+import (
+	"io"
+	"log"
+	"sync"
+)
+
+// SetVerbosityFromFlags sets the global level from the usual flags
+// of command line tools: debug selects DebugLevel, verbose selects
+// TraceLevel, quiet selects ErrorLevel and InfoLevel is used when no
+// flag is set. The most verbose flag wins when several are set.
+func SetVerbosityFromFlags(quiet, verbose, debug bool) {
+	switch {
+	case debug:
+		SetLevel(DebugLevel)
+	case verbose:
+		SetLevel(TraceLevel)
+	case quiet:
+		SetLevel(ErrorLevel)
+	default:
+		SetLevel(InfoLevel)
+	}
+}
+
+// CLIFormatter formats an entry as its bare message and fields,
+// without the level prefix nor the time, for the messages shown
+// to the users of command line tools.
+type CLIFormatter struct{}
+
+// Format implements the Formatter interface.
+func (f *CLIFormatter) Format(e *Entry) ([]byte, error) {
+	b := make([]byte, 0, len(e.Message)+1)
+	b = append(b, e.Message...)
+	if len(e.Fields) > 0 {
+		b = appendTextFields(b, e.Fields)
+	}
+	return append(b, '\n'), nil
+}
+
+// SetCLIMode makes the package level loggers write the bare messages
+// to console with the CLIFormatter while keeping the full entries,
+// with the level, time and call site, in logFile:
+//
+//	f, _ := os.OpenFile("app.log", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+//	golog.SetCLIMode(os.Stderr, f)
+//
+// A nil logFile stops the copy to the previous one.
+func SetCLIMode(console, logFile io.Writer) {
+	cliHookOnce.Do(func() {
+		for _, l := range packageLoggers() {
+			l.AddHook(cliFile)
+		}
+	})
+	cliFile.setWriter(logFile)
+	for _, l := range packageLoggers() {
+		l.SetFormatter(&CLIFormatter{})
+		l.SetOutput(console)
+	}
+}
+
+var (
+	// cliFile copies the entries of the package level
+	// loggers to the log file of the CLI mode.
+	cliFile     = &WriterHook{Formatter: &TextFormatter{Flags: log.LstdFlags | log.Lshortfile}}
+	cliHookOnce sync.Once
+)
+
+// WriterHook is a Hook writing every entry, formatted with
+// Formatter, to Writer. Nothing is written while Writer is nil.
+type WriterHook struct {
+	Writer    io.Writer
+	Formatter Formatter
+
+	mu sync.Mutex
+}
+
+// Fire implements the Hook interface.
+func (h *WriterHook) Fire(e *Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.Writer == nil {
+		return nil
+	}
+	b, err := safeFormat(h.Formatter, e)
+	if err != nil {
+		return err
+	}
+	_, err = h.Writer.Write(b)
+	return err
+}
+
+func (h *WriterHook) setWriter(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Writer = w
+}
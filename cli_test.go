@@ -0,0 +1,57 @@
+package golog
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetVerbosityFromFlags(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	cases := []struct {
+		quiet, verbose, debug bool
+		want                  Level
+	}{
+		{false, false, false, InfoLevel},
+		{true, false, false, ErrorLevel},
+		{false, true, false, TraceLevel},
+		{false, false, true, DebugLevel},
+		{true, true, false, TraceLevel},
+		{true, true, true, DebugLevel},
+	}
+	for _, c := range cases {
+		SetVerbosityFromFlags(c.quiet, c.verbose, c.debug)
+		assert.Equal(t, c.want, getState().currentLevel)
+	}
+}
+
+func TestSetCLIMode(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer func() {
+		cliFile.setWriter(nil)
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	console, file := &syncBuffer{}, &syncBuffer{}
+	SetCLIMode(console, file)
+
+	Info("Downloading 3 files")
+	Errorf("cannot open %s", "a.txt")
+	Debug("hidden")
+	assert.Equal(t, "Downloading 3 files\ncannot open a.txt\n", console.String())
+	assert.Regexp(t, regexp.MustCompile(`^INFO: \d{4}/\d\d/\d\d \d\d:\d\d:\d\d cli_test.go:\d+: Downloading 3 files
+ERROR: \d{4}/\d\d/\d\d \d\d:\d\d:\d\d cli_test.go:\d+: cannot open a.txt
+$`), file.String())
+
+	t.Run("a nil log file stops the copy", func(t *testing.T) {
+		SetCLIMode(console, nil)
+		Info("not copied")
+		assert.NotContains(t, file.String(), "not copied")
+		assert.Contains(t, console.String(), "not copied")
+	})
+}
@@ -0,0 +1,44 @@
+package golog
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock tells the time of the entries of a logger.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now implements the Clock interface.
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// clockValue holds the Clock of a logger. The zero value
+// uses the package clock.
+type clockValue struct {
+	v atomic.Value // clockHolder
+}
+
+// clockHolder wraps the clock so atomic.Value always
+// stores the same concrete type.
+type clockHolder struct {
+	c Clock
+}
+
+func (cv *clockValue) set(c Clock) {
+	cv.v.Store(clockHolder{c})
+}
+
+// now returns the time of the clock, or of the package
+// clock when none is set.
+func (cv *clockValue) now() time.Time {
+	if h, ok := cv.v.Load().(clockHolder); ok && h.c != nil {
+		return h.c.Now()
+	}
+	return now()
+}
@@ -0,0 +1,43 @@
+package golog
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetClock(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var out1, out2 bytes.Buffer
+	l1 := newStdLogger(InfoLevel, &out1, log.LstdFlags|log.LUTC)
+	l2 := newStdLogger(InfoLevel, &out2, log.LstdFlags|log.LUTC)
+	t1 := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	t2 := time.Date(2021, 6, 15, 12, 30, 0, 0, time.UTC)
+	l1.SetClock(ClockFunc(func() time.Time { return t1 }))
+	l2.SetClock(ClockFunc(func() time.Time { return t2 }))
+
+	l1.WithFields(Fields{"component": "a"}).Print("tick")
+	l2.Print("tock")
+	assert.Equal(t, "INFO: 2020/01/01 10:00:00 tick component=a\n", out1.String())
+	assert.Equal(t, "INFO: 2021/06/15 12:30:00 tock\n", out2.String())
+
+	t.Run("nil restores the system clock", func(t *testing.T) {
+		out1.Reset()
+		l1.SetClock(nil)
+		l1.Print("tick")
+		assert.NotContains(t, out1.String(), "2020/01/01")
+	})
+	t.Run("logrus", func(t *testing.T) {
+		var out bytes.Buffer
+		l := NewLogrusLogger(InfoLevel)
+		l.SetOutput(&out)
+		l.SetFormatter(&JSONFormatter{})
+		l.SetClock(ClockFunc(func() time.Time { return t1 }))
+		l.Print("tick")
+		assert.Contains(t, out.String(), `"time":"2020-01-01T10:00:00Z"`)
+	})
+}
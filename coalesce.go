@@ -0,0 +1,234 @@
+package golog
+
+import (
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The defaults of NewCoalescingWriter.
+const (
+	defaultCoalesceWindow = time.Millisecond
+	// defaultCoalesceShardSize is the number of bytes held by
+	// a shard before the entries are written at once.
+	defaultCoalesceShardSize = 32 << 10
+)
+
+// CoalescingWriter is a sink that coalesces the entries written by many
+// goroutines into fewer writes to the underlying writer, usually
+// os.Stdout, for the chatty services whose top system call is write(2).
+// The entries are appended to one of several mutex-protected shards, so
+// the goroutines rarely wait for each other, and all the shards are
+// written in a single write every window, or as soon as one of them
+// holds 32KiB. The entries are never split, and the entries of a
+// goroutine keep their order. The entries of ErrorLevel, by default,
+// are written at once with the pending ones.
+//
+//	w := golog.NewCoalescingWriter(os.Stdout, 0, 0)
+//	defer w.Close()
+//	golog.SetOutput(w)
+//
+// Unlike a BufferedWriter, the entries are only delayed by the window,
+// a millisecond by default. Call Close, or Sync, on shutdown so the
+// pending entries are not lost.
+type CoalescingWriter struct {
+	w          io.Writer
+	shards     []coalesceShard
+	next       uint32
+	seq        uint64
+	flushLevel int32
+
+	flushMu sync.Mutex // serializes the flushes, protects the fields below
+	taken   []coalesceBatch
+	heads   []int
+	out     []byte
+	closed  bool // also written with all the shard locks held
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// coalesceShard holds the pending entries of some goroutines.
+type coalesceShard struct {
+	mu sync.Mutex
+	coalesceBatch
+}
+
+// coalesceBatch is a sequence of entries.
+type coalesceBatch struct {
+	buf []byte
+	// ends are the sequence numbers of the entries of buf, in
+	// order, and the offsets of their ends.
+	ends []coalescedEntry
+}
+
+type coalescedEntry struct {
+	seq uint64
+	end int
+}
+
+// NewCoalescingWriter returns a CoalescingWriter writing to w with
+// shards shards, GOMAXPROCS when shards is not positive, every window,
+// one millisecond when window is not positive.
+func NewCoalescingWriter(w io.Writer, shards int, window time.Duration) *CoalescingWriter {
+	if shards <= 0 {
+		shards = runtime.GOMAXPROCS(0)
+	}
+	if window <= 0 {
+		window = defaultCoalesceWindow
+	}
+	cw := &CoalescingWriter{
+		w:          w,
+		shards:     make([]coalesceShard, shards),
+		taken:      make([]coalesceBatch, shards),
+		heads:      make([]int, shards),
+		flushLevel: int32(ErrorLevel),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go cw.run(window)
+	registerSink(cw)
+	return cw
+}
+
+func (w *CoalescingWriter) run(window time.Duration) {
+	defer close(w.done)
+	t := time.NewTicker(window)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			if err := w.Flush(); err != nil && err != ErrWriterClosed {
+				reportf("golog: coalescing writer: %v", err)
+			}
+		case <-w.stop:
+			return
+		}
+	}
+}
+
+// SetFlushLevel makes the entries of lvl and above be written at
+// once. DisabledLevel coalesces all the entries.
+func (w *CoalescingWriter) SetFlushLevel(lvl Level) {
+	atomic.StoreInt32(&w.flushLevel, int32(lvl))
+}
+
+// Write appends a copy of p to a shard, and writes the pending
+// entries when the shard is full.
+func (w *CoalescingWriter) Write(p []byte) (int, error) {
+	s := &w.shards[atomic.AddUint32(&w.next, 1)%uint32(len(w.shards))]
+	s.mu.Lock()
+	if w.closed {
+		s.mu.Unlock()
+		return 0, ErrWriterClosed
+	}
+	// The sequence number is taken with the lock held, so the
+	// entries of a shard are in order.
+	seq := atomic.AddUint64(&w.seq, 1)
+	s.buf = append(s.buf, p...)
+	s.ends = append(s.ends, coalescedEntry{seq: seq, end: len(s.buf)})
+	full := len(s.buf) >= defaultCoalesceShardSize
+	s.mu.Unlock()
+	if full {
+		if err := w.Flush(); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// WriteLevel appends the entry p of lvl to a shard, and writes the
+// pending entries when lvl is at or above the flush level.
+func (w *CoalescingWriter) WriteLevel(lvl Level, p []byte) (int, error) {
+	n, err := w.Write(p)
+	if err == nil && lvl < DisabledLevel && int32(lvl) >= atomic.LoadInt32(&w.flushLevel) {
+		err = w.Flush()
+	}
+	return n, err
+}
+
+// Flush writes the pending entries of all the shards in a single
+// write, in the order they were written.
+func (w *CoalescingWriter) Flush() error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+	return w.flush()
+}
+
+// flush writes the pending entries. It must be called with
+// flushMu held.
+func (w *CoalescingWriter) flush() error {
+	// The shards are taken all at once, so an entry written after
+	// one taken is never written before it.
+	for i := range w.shards {
+		w.shards[i].mu.Lock()
+	}
+	n := 0
+	for i := range w.shards {
+		s, t := &w.shards[i], &w.taken[i]
+		s.buf, t.buf = t.buf[:0], s.buf
+		s.ends, t.ends = t.ends[:0], s.ends
+		n += len(t.buf)
+	}
+	for i := range w.shards {
+		w.shards[i].mu.Unlock()
+	}
+	if n == 0 {
+		return nil
+	}
+	// Merge the shards by sequence number.
+	out := w.out[:0]
+	heads := w.heads
+	for i := range heads {
+		heads[i] = 0
+	}
+	for {
+		min := -1
+		for i, t := range w.taken {
+			if heads[i] < len(t.ends) && (min < 0 || t.ends[heads[i]].seq < w.taken[min].ends[heads[min]].seq) {
+				min = i
+			}
+		}
+		if min < 0 {
+			break
+		}
+		t := &w.taken[min]
+		start := 0
+		if heads[min] > 0 {
+			start = t.ends[heads[min]-1].end
+		}
+		out = append(out, t.buf[start:t.ends[heads[min]].end]...)
+		heads[min]++
+	}
+	w.out = out
+	_, err := w.w.Write(out)
+	return err
+}
+
+// Close stops the periodic writes and writes the pending entries.
+// The underlying writer is not closed.
+func (w *CoalescingWriter) Close() error {
+	w.flushMu.Lock()
+	if w.closed {
+		w.flushMu.Unlock()
+		return nil
+	}
+	for i := range w.shards {
+		w.shards[i].mu.Lock()
+	}
+	w.closed = true
+	for i := range w.shards {
+		w.shards[i].mu.Unlock()
+	}
+	err := w.flush()
+	w.flushMu.Unlock()
+
+	close(w.stop)
+	<-w.done
+	unregisterSink(w)
+	return err
+}
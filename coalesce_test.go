@@ -0,0 +1,102 @@
+package golog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callWriter records the writes made to it.
+type callWriter struct {
+	mu    sync.Mutex
+	calls int
+	out   strings.Builder
+}
+
+func (w *callWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.calls++
+	return w.out.Write(p)
+}
+
+func (w *callWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.out.String()
+}
+
+func TestCoalescingWriter(t *testing.T) {
+	t.Run("coalesces the writes of many goroutines", func(t *testing.T) {
+		cw := &callWriter{}
+		w := NewCoalescingWriter(cw, 4, time.Hour)
+		const goroutines, lines = 16, 200
+		var wg sync.WaitGroup
+		for g := 0; g < goroutines; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < lines; i++ {
+					w.Write([]byte(fmt.Sprintf("g%d %d\n", g, i)))
+				}
+			}(g)
+		}
+		wg.Wait()
+		require.NoError(t, w.Close())
+
+		got := strings.Split(strings.TrimSuffix(cw.String(), "\n"), "\n")
+		require.Len(t, got, goroutines*lines)
+		next := make(map[int]int)
+		for _, line := range got {
+			var g, i int
+			_, err := fmt.Sscanf(line, "g%d %d", &g, &i)
+			require.NoError(t, err, line)
+			assert.Equal(t, next[g], i, "order of g%d", g)
+			next[g] = i + 1
+		}
+		assert.Less(t, cw.calls, goroutines*lines/10)
+	})
+
+	t.Run("writes every window", func(t *testing.T) {
+		cw := &callWriter{}
+		w := NewCoalescingWriter(cw, 0, time.Millisecond)
+		defer w.Close()
+		w.Write([]byte("first\n"))
+		w.Write([]byte("second\n"))
+		assert.Eventually(t, func() bool { return cw.String() == "first\nsecond\n" }, time.Second, time.Millisecond)
+	})
+
+	t.Run("errors are written at once", func(t *testing.T) {
+		cw := &callWriter{}
+		w := NewCoalescingWriter(cw, 2, time.Hour)
+		defer w.Close()
+		w.WriteLevel(InfoLevel, []byte("INFO: pending\n"))
+		assert.Empty(t, cw.String())
+		w.WriteLevel(ErrorLevel, []byte("ERROR: failed\n"))
+		assert.Equal(t, "INFO: pending\nERROR: failed\n", cw.String())
+		assert.Equal(t, 1, cw.calls)
+
+		w.SetFlushLevel(DisabledLevel)
+		w.WriteLevel(ErrorLevel, []byte("ERROR: batched\n"))
+		assert.Equal(t, 1, cw.calls)
+		require.NoError(t, Sync())
+		assert.Equal(t, 2, cw.calls)
+	})
+
+	t.Run("close", func(t *testing.T) {
+		cw := &callWriter{}
+		w := NewCoalescingWriter(cw, 2, time.Hour)
+		w.Write([]byte("last\n"))
+		require.NoError(t, w.Close())
+		assert.Equal(t, "last\n", cw.String())
+		_, err := w.Write([]byte("late\n"))
+		assert.Equal(t, ErrWriterClosed, err)
+		assert.Equal(t, ErrWriterClosed, w.Flush())
+		assert.NoError(t, w.Close())
+	})
+}
@@ -0,0 +1,25 @@
+package golog
+
+import "os/exec"
+
+// CommandLogger sets the stdout and stderr of cmd to writers logging
+// every line of the child process as an Info, respectively Error,
+// entry with the fields command, set to name, and stream. It must be
+// called before cmd is started. The returned function logs the last
+// lines that don't end with a newline and must be called once cmd
+// has exited:
+//
+//	cmd := exec.Command("git", "fetch")
+//	flush := golog.CommandLogger(cmd, "git")
+//	err := cmd.Run()
+//	flush()
+func CommandLogger(cmd *exec.Cmd, name string) (flush func()) {
+	stdout := InfoLogger.WithFields(Fields{"command": name, "stream": "stdout"}).Writer()
+	stderr := ErrorLogger.WithFields(Fields{"command": name, "stream": "stderr"}).Writer()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return func() {
+		stdout.Close()
+		stderr.Close()
+	}
+}
@@ -0,0 +1,37 @@
+package golog
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandLogger(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	defer SetOutput(os.Stdout)
+	defer func() {
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetFormatter(&TextFormatter{})
+
+	cmd := exec.Command(sh, "-c", `echo fetching; echo "no remote" >&2; printf done`)
+	flush := CommandLogger(cmd, "git")
+	require.NoError(t, cmd.Run())
+	flush()
+
+	assert.Contains(t, out.String(), "INFO: fetching command=git stream=stdout\n")
+	assert.Contains(t, out.String(), "ERROR: no remote command=git stream=stderr\n")
+	assert.Contains(t, out.String(), "INFO: done command=git stream=stdout\n")
+}
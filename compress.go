@@ -0,0 +1,82 @@
+package golog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Compression is the compression of the connections of the
+// network sinks. zstd is not supported: it has no implementation
+// in the standard library.
+type Compression int
+
+const (
+	// CompressionNone writes the frames as is.
+	CompressionNone Compression = iota
+	// CompressionGzip compresses the frames of a connection as one
+	// gzip stream, flushed after every write, so the entries of a
+	// connection share the compression history and even short ones
+	// shrink.
+	CompressionGzip
+)
+
+// frameStream is set in the header announcing a compressed stream.
+// The frames are at most maxFrameSize, so no frame has it: the sinks
+// write the header first on the connections they compress, with the
+// Compression in the other bits, and the receivers accept compressed
+// and plain connections alike, so each sink chooses its compression.
+const frameStream = 1 << 31
+
+// compressor compresses the frames written to a connection.
+type compressor interface {
+	io.WriteCloser
+	// Flush writes the frames written so far to the connection.
+	Flush() error
+}
+
+// newCompressor writes the header announcing the compression c to w
+// and returns the compressor of the frames written to w, or nil when
+// c is CompressionNone.
+func newCompressor(w io.Writer, c Compression) (compressor, error) {
+	switch c {
+	case CompressionNone:
+		return nil, nil
+	case CompressionGzip:
+	default:
+		return nil, fmt.Errorf("golog: unsupported compression %d", c)
+	}
+	var hdr [4]byte
+	binary.BigEndian.PutUint32(hdr[:], frameStream|uint32(c))
+	if _, err := w.Write(hdr[:]); err != nil {
+		return nil, err
+	}
+	// Below BestCompression, the compressor of compress/flate does
+	// not match the data written before a Flush, so the entries
+	// flushed one by one would grow.
+	return gzip.NewWriterLevel(w, gzip.BestCompression)
+}
+
+// frameReader returns the reader of the frames of the connection
+// read by r, which decompresses them when the connection starts
+// with the header of a compressed stream.
+func frameReader(r *bufio.Reader) (io.Reader, error) {
+	hdr, err := r.Peek(4)
+	if err != nil {
+		// The connection has no frame: readFrame reports it.
+		return r, nil
+	}
+	h := binary.BigEndian.Uint32(hdr)
+	if h&frameStream == 0 {
+		return r, nil
+	}
+	r.Discard(4)
+	switch c := Compression(h &^ frameStream); c {
+	case CompressionGzip:
+		return gzip.NewReader(r)
+	default:
+		return nil, fmt.Errorf("golog: unsupported compression %d", c)
+	}
+}
@@ -0,0 +1,89 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression(t *testing.T) {
+	entry := func(i int) []byte {
+		return []byte(fmt.Sprintf("INFO: 2020/01/01 00:00:%02d request served status=200 path=/api/v1/users/%d\n", i%60, i))
+	}
+
+	t.Run("the entries shrink", func(t *testing.T) {
+		var plain, compressed bytes.Buffer
+		zw, err := newCompressor(&compressed, CompressionGzip)
+		require.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			require.NoError(t, writeFrame(&plain, entry(i)))
+			// Every entry is flushed, as written by a SocketWriter.
+			require.NoError(t, writeFrame(zw, entry(i)))
+			require.NoError(t, zw.Flush())
+		}
+		assert.True(t, compressed.Len() < plain.Len()/2, "%d bytes compressed to %d", plain.Len(), compressed.Len())
+
+		require.NoError(t, zw.Close())
+		r, err := frameReader(bufio.NewReader(&compressed))
+		require.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			p, err := readFrame(r)
+			require.NoError(t, err)
+			assert.Equal(t, string(entry(i)), string(p))
+		}
+	})
+	t.Run("plain connections", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw, err := newCompressor(&buf, CompressionNone)
+		require.NoError(t, err)
+		assert.Nil(t, zw)
+		require.NoError(t, writeFrame(&buf, entry(1)))
+		r, err := frameReader(bufio.NewReader(&buf))
+		require.NoError(t, err)
+		p, err := readFrame(r)
+		require.NoError(t, err)
+		assert.Equal(t, string(entry(1)), string(p))
+	})
+	t.Run("unsupported compression", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := newCompressor(&buf, Compression(7))
+		assert.EqualError(t, err, "golog: unsupported compression 7")
+		_, err = frameReader(bufio.NewReader(bytes.NewReader([]byte{0x80, 0, 0, 7})))
+		assert.EqualError(t, err, "golog: unsupported compression 7")
+	})
+
+	t.Run("socket writer", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "golog")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		addr := filepath.Join(dir, "forwarder.sock")
+		ln, err := net.Listen("unix", addr)
+		require.NoError(t, err)
+		sink := &syncBuffer{}
+		f := NewForwarder(sink)
+		go f.Serve(ln)
+		defer f.Close()
+
+		compressed, plain := NewUnixSocketWriter(addr), NewUnixSocketWriter(addr)
+		compressed.Compression = CompressionGzip
+		_, err = compressed.Write([]byte("compressed\n"))
+		require.NoError(t, err)
+		_, err = plain.Write([]byte("plain\n"))
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool {
+			return strings.Contains(sink.String(), "compressed\n") && strings.Contains(sink.String(), "plain\n")
+		}, time.Second, 10*time.Millisecond)
+		require.NoError(t, compressed.Close())
+		require.NoError(t, plain.Close())
+	})
+}
@@ -0,0 +1,289 @@
+package golog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the declarative configuration of the package level
+// loggers read by LoadConfig, e.g. in YAML:
+//
+//	level: info
+//	format: json
+//	output: /var/log/app.log
+//	outputs:
+//	  error: stderr
+//	loggers:
+//	  github.com/acme/mono/db: debug
+//	mute:
+//	  - level: debug
+//	    start: "09:00"
+//	    end: "18:00"
+//	    weekdays: [mon, tue, wed, thu, fri]
+//	    location: Europe/Paris
+//
+// The empty settings leave the configuration untouched, except
+// Loggers and Mute which replace all the named logger levels and
+// all the mute rules.
+type Config struct {
+	// Level is the global level, see ParseLevel.
+	Level string `json:"level" yaml:"level"`
+	// Loggers are the levels of the named loggers.
+	// See SetLoggerLevel.
+	Loggers map[string]string `json:"loggers" yaml:"loggers"`
+	// Format is "text", "json" or "console".
+	Format string `json:"format" yaml:"format"`
+	// Output is "stdout", "stderr" or the path of a file
+	// to which the entries are appended.
+	Output string `json:"output" yaml:"output"`
+	// Outputs override Output per level name.
+	Outputs map[string]string `json:"outputs" yaml:"outputs"`
+	// Mute are the mute rules. See SetMuteRules.
+	Mute []MuteRuleConfig `json:"mute" yaml:"mute"`
+}
+
+// MuteRuleConfig is a MuteRule in a Config.
+type MuteRuleConfig struct {
+	// Level is the highest level muted, see ParseLevel.
+	Level string `json:"level" yaml:"level"`
+	Match string `json:"match" yaml:"match"`
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end" yaml:"end"`
+	// Weekdays are the names of the days, e.g. "monday" or "mon".
+	Weekdays []string `json:"weekdays" yaml:"weekdays"`
+	// Location is the name of the time zone, e.g. "Europe/Paris".
+	// Defaults to the local time zone.
+	Location string `json:"location" yaml:"location"`
+}
+
+// rule returns the MuteRule of c.
+func (c MuteRuleConfig) rule() (MuteRule, error) {
+	lvl, err := ParseLevel(c.Level)
+	if err != nil {
+		return MuteRule{}, err
+	}
+	r := MuteRule{Level: lvl, Match: c.Match, Start: c.Start, End: c.End}
+	for _, name := range c.Weekdays {
+		d, err := parseWeekday(name)
+		if err != nil {
+			return MuteRule{}, err
+		}
+		r.Weekdays = append(r.Weekdays, d)
+	}
+	if c.Location != "" {
+		if r.Location, err = loadLocation(c.Location); err != nil {
+			return MuteRule{}, err
+		}
+	}
+	return r, nil
+}
+
+// parseWeekday parses the full or three letters name of a day.
+func parseWeekday(s string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if name := d.String(); strings.EqualFold(s, name) || strings.EqualFold(s, name[:3]) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", s)
+}
+
+var (
+	configMu sync.Mutex
+	// configFiles are the files opened by the last applied
+	// configuration, closed when it is replaced.
+	configFiles []*os.File
+)
+
+// LoadConfig reads the configuration from the YAML or JSON file at
+// path, depending on its extension, and applies it. Nothing is changed
+// when the file or one of its settings is invalid.
+func LoadConfig(path string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("golog: config: %v", err)
+	}
+	var c Config
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(b, &c)
+	} else {
+		err = yaml.UnmarshalStrict(b, &c)
+	}
+	if err != nil {
+		return fmt.Errorf("golog: config: %s: %v", path, err)
+	}
+	return c.Apply()
+}
+
+// Apply applies the configuration. Nothing is changed when
+// one of its settings is invalid.
+func (c Config) Apply() error {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	var apply []func()
+	var opened []*os.File
+	fail := func(err error) error {
+		for _, f := range opened {
+			f.Close()
+		}
+		return fmt.Errorf("golog: config: %v", err)
+	}
+
+	if c.Level != "" {
+		lvl, err := ParseLevel(c.Level)
+		if err != nil {
+			return fail(err)
+		}
+		apply = append(apply, func() { SetLevel(lvl) })
+	}
+	if c.Loggers != nil {
+		levels := make(map[string]Level, len(c.Loggers))
+		for name, s := range c.Loggers {
+			lvl, err := ParseLevel(s)
+			if err != nil {
+				return fail(fmt.Errorf("logger %s: %v", name, err))
+			}
+			levels[name] = lvl
+		}
+		apply = append(apply, func() {
+			updateState(func(s *globalState) { s.loggerLevels = levels })
+			levelsChanged()
+		})
+	}
+
+	open := func(s string) (io.Writer, error) {
+		switch strings.ToLower(s) {
+		case "stdout":
+			return os.Stdout, nil
+		case "stderr":
+			return os.Stderr, nil
+		}
+		f, err := os.OpenFile(s, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		opened = append(opened, f)
+		return f, nil
+	}
+	if c.Output != "" {
+		w, err := open(c.Output)
+		if err != nil {
+			return fail(err)
+		}
+		apply = append(apply, func() { SetOutput(w) })
+	}
+	for name, s := range c.Outputs {
+		lvl, err := ParseLevel(name)
+		if err != nil {
+			return fail(err)
+		}
+		w, err := open(s)
+		if err != nil {
+			return fail(err)
+		}
+		apply = append(apply, func() { SetLevelOutput(lvl, w) })
+	}
+
+	if c.Format != "" {
+		var f func(w io.Writer) Formatter
+		switch strings.ToLower(c.Format) {
+		case "text":
+		case "json":
+			f = func(io.Writer) Formatter { return &JSONFormatter{} }
+		case "console":
+			f = func(w io.Writer) Formatter { return NewConsoleFormatter(w) }
+		default:
+			return fail(fmt.Errorf("unknown format %q", c.Format))
+		}
+		// The formatters are set after the outputs, so the console
+		// formatter colors the entries written to a terminal only.
+		apply = append(apply, func() {
+			for _, l := range packageLoggers() {
+				if f == nil {
+					l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+				} else {
+					l.SetFormatter(f(LevelOutput(l.level)))
+				}
+			}
+		})
+	}
+	if c.Mute != nil {
+		rules := make([]MuteRule, len(c.Mute))
+		for i, rc := range c.Mute {
+			r, err := rc.rule()
+			if err != nil {
+				return fail(fmt.Errorf("mute rule %d: %v", i, err))
+			}
+			rules[i] = r
+		}
+		compiled, err := compileMuteRules(rules)
+		if err != nil {
+			return fail(err)
+		}
+		apply = append(apply, func() {
+			updateState(func(s *globalState) { s.muteRules = compiled })
+		})
+	}
+
+	for _, fn := range apply {
+		fn()
+	}
+	if c.Output != "" || len(c.Outputs) > 0 {
+		for _, f := range configFiles {
+			f.Close()
+		}
+		configFiles = opened
+	}
+	return nil
+}
+
+// WatchConfig loads the configuration file at path, then polls it
+// every interval and applies it again when it changes, so the logging
+// can be reconfigured without a restart. The errors of the reloads are
+// reported to the diagnostic handler and keep the current configuration.
+// stop stops watching. The interval must be positive.
+func WatchConfig(path string, interval time.Duration) (stop func(), err error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("golog: config: invalid watch interval %v", interval)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("golog: config: %v", err)
+	}
+	if err := LoadConfig(path); err != nil {
+		return nil, err
+	}
+	done := make(chan struct{})
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		mod, size := fi.ModTime(), fi.Size()
+		for {
+			select {
+			case <-t.C:
+				fi, err := os.Stat(path)
+				if err != nil || (fi.ModTime().Equal(mod) && fi.Size() == size) {
+					continue
+				}
+				mod, size = fi.ModTime(), fi.Size()
+				if err := LoadConfig(path); err != nil {
+					reportError(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }, nil
+}
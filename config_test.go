@@ -0,0 +1,141 @@
+package golog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	defer SetOutput(os.Stdout)
+	defer func() {
+		for name := range LoggerLevels() {
+			ResetLoggerLevel(name)
+		}
+	}()
+	defer func() {
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	appLog, errLog := filepath.Join(dir, "app.log"), filepath.Join(dir, "error.log")
+
+	t.Run("yaml", func(t *testing.T) {
+		path := write("golog.yaml", `
+level: warning
+format: json
+output: `+appLog+`
+outputs:
+  error: `+errLog+`
+loggers:
+  db: debug
+`)
+		require.NoError(t, LoadConfig(path))
+		assert.Equal(t, WarningLevel, GetLevel())
+		assert.Equal(t, map[string]Level{"db": DebugLevel}, LoggerLevels())
+
+		Info("not written")
+		Warning("written")
+		Error("failed")
+		b, err := ioutil.ReadFile(appLog)
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "not written")
+		assert.Contains(t, string(b), `"msg":"written"`)
+		b, err = ioutil.ReadFile(errLog)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `"msg":"failed"`)
+	})
+	t.Run("json", func(t *testing.T) {
+		path := write("golog.json", `{"level": "error", "loggers": {}}`)
+		require.NoError(t, LoadConfig(path))
+		assert.Equal(t, ErrorLevel, GetLevel())
+		assert.Empty(t, LoggerLevels())
+	})
+	t.Run("mute rules", func(t *testing.T) {
+		defer SetMuteRules()
+		path := write("mute.yaml", `
+mute:
+  - level: debug
+    match: heartbeat
+    start: "09:00"
+    end: "18:00"
+    weekdays: [mon, Tuesday]
+    location: Europe/Paris
+`)
+		require.NoError(t, LoadConfig(path))
+		paris, err := time.LoadLocation("Europe/Paris")
+		require.NoError(t, err)
+		assert.Equal(t, []MuteRule{{
+			Level:    DebugLevel,
+			Match:    "heartbeat",
+			Start:    "09:00",
+			End:      "18:00",
+			Weekdays: []time.Weekday{time.Monday, time.Tuesday},
+			Location: paris,
+		}}, MuteRules())
+
+		assert.Error(t, LoadConfig(write("bad_mute.yaml", "mute: [{level: debug, start: '9am', end: '18:00'}]\n")))
+		assert.Error(t, LoadConfig(write("bad_day.yaml", "mute: [{level: debug, start: '09:00', end: '18:00', weekdays: [someday]}]\n")))
+		assert.Len(t, MuteRules(), 1)
+		require.NoError(t, LoadConfig(write("no_mute.yaml", "mute: []\n")))
+		assert.Empty(t, MuteRules())
+	})
+	t.Run("console format", func(t *testing.T) {
+		require.NoError(t, LoadConfig(write("console.yaml", "format: console\noutput: "+appLog+"\n")))
+		f, ok := InfoLogger.out.formatter.(*ConsoleFormatter)
+		require.True(t, ok)
+		assert.True(t, f.DisableColors, "the colors of a file")
+	})
+	t.Run("invalid settings change nothing", func(t *testing.T) {
+		SetLevel(ErrorLevel)
+		assert.Error(t, LoadConfig(write("bad.yaml", "level: debug\nformat: xml\n")))
+		assert.Error(t, LoadConfig(write("unknown.yaml", "levle: debug\n")))
+		assert.Error(t, LoadConfig(write("logger.yaml", "level: debug\nloggers: {db: loud}\n")))
+		assert.Error(t, LoadConfig(filepath.Join(dir, "missing.yaml")))
+		assert.Equal(t, ErrorLevel, GetLevel())
+	})
+}
+
+func TestWatchConfig(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "golog.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("level: warning\n"), 0644))
+
+	var errs syncBuffer
+	SetDiagnosticHandler(func(err error) { errs.Write([]byte(err.Error() + "\n")) })
+	defer SetDiagnosticHandler(nil)
+
+	stop, err := WatchConfig(path, 5*time.Millisecond)
+	require.NoError(t, err)
+	defer stop()
+	assert.Equal(t, WarningLevel, GetLevel())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("level: loud\n"), 0644))
+	assert.Eventually(t, func() bool { return errs.String() != "" }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, WarningLevel, GetLevel())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("level: debug\n"), 0644))
+	assert.Eventually(t, func() bool { return GetLevel() == DebugLevel }, time.Second, 5*time.Millisecond)
+
+	_, err = WatchConfig(filepath.Join(dir, "missing.yaml"), time.Second)
+	assert.Error(t, err)
+	_, err = WatchConfig(path, 0)
+	assert.EqualError(t, err, "golog: config: invalid watch interval 0s")
+}
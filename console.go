@@ -0,0 +1,98 @@
+package golog
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ANSI escape sequences of the ConsoleFormatter.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+	ansiDim    = "\x1b[2m"
+)
+
+// consoleMessageWidth is the column the fields are aligned on.
+const consoleMessageWidth = 40
+
+// ConsoleFormatter formats an entry for humans reading a terminal:
+// the time, a level token colorized by severity, the message and
+// the fields aligned in columns:
+//
+//	15:04:05.000 WARN  disk almost full                         used=93
+//
+// The Stacktrace fields are rendered as indented blocks below the line.
+type ConsoleFormatter struct {
+	// TimeFormat is the layout of the time.
+	// Defaults to "15:04:05.000".
+	TimeFormat string
+	// DisableColors disables the ANSI colors.
+	DisableColors bool
+}
+
+// NewConsoleFormatter returns a ConsoleFormatter for the entries
+// written to w. The colors are disabled when w is not a terminal or
+// when the NO_COLOR environment variable is set.
+func NewConsoleFormatter(w io.Writer) *ConsoleFormatter {
+	_, noColor := os.LookupEnv("NO_COLOR")
+	return &ConsoleFormatter{DisableColors: noColor || !isTerminal(w)}
+}
+
+// Format implements the Formatter interface.
+func (f *ConsoleFormatter) Format(e *Entry) ([]byte, error) {
+	return f.appendFormat(make([]byte, 0, 64+len(e.Message)), e)
+}
+
+// appendFormat appends the formatted entry e to b.
+func (f *ConsoleFormatter) appendFormat(b []byte, e *Entry) ([]byte, error) {
+	layout := f.TimeFormat
+	if layout == "" {
+		layout = "15:04:05.000"
+	}
+	b = appendTime(b, e.Time, layout)
+	b = append(b, ' ')
+
+	token, color := consoleLevel(e.Level)
+	if !f.DisableColors {
+		b = append(b, color...)
+	}
+	b = append(b, token...)
+	if !f.DisableColors {
+		b = append(b, ansiReset...)
+	}
+	b = append(b, ' ')
+	if pad := 5 - len(token); pad > 0 {
+		b = append(b, strings.Repeat(" ", pad)...)
+	}
+
+	b = append(b, e.Message...)
+	fields, stacks := splitStacktraces(e.Fields)
+	if len(fields) > 0 {
+		if pad := consoleMessageWidth - len(e.Message); pad > 0 {
+			b = append(b, strings.Repeat(" ", pad)...)
+		}
+		b = appendTextFields(b, fields)
+	}
+	b = append(b, '\n')
+	return appendStacktraces(b, stacks), nil
+}
+
+// consoleLevel returns the token and the color of lvl.
+func consoleLevel(lvl Level) (token, color string) {
+	switch lvl {
+	case DebugLevel:
+		return "DEBUG", ansiDim
+	case TraceLevel:
+		return "TRACE", ansiDim
+	case InfoLevel:
+		return "INFO", ansiCyan
+	case WarningLevel:
+		return "WARN", ansiYellow
+	case ErrorLevel:
+		return "ERROR", ansiRed
+	}
+	return strings.ToUpper(lvl.name()), ""
+}
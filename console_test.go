@@ -0,0 +1,43 @@
+package golog
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleFormatter(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 15, 4, 5, 678000000, time.UTC)
+	f := &ConsoleFormatter{}
+
+	b, err := f.Format(&Entry{Time: ts, Level: ErrorLevel, Message: "failed", Fields: Fields{"code": 500}})
+	assert.NoError(t, err)
+	assert.Equal(t, "15:04:05.678 \x1b[31mERROR\x1b[0m failed                                   code=500\n", string(b))
+
+	b, _ = f.Format(&Entry{Time: ts, Level: WarningLevel, Message: "slow"})
+	assert.Equal(t, "15:04:05.678 \x1b[33mWARN\x1b[0m  slow\n", string(b))
+	b, _ = f.Format(&Entry{Time: ts, Level: TraceLevel, Message: "enter"})
+	assert.Equal(t, "15:04:05.678 \x1b[2mTRACE\x1b[0m enter\n", string(b))
+
+	t.Run("without colors", func(t *testing.T) {
+		f := &ConsoleFormatter{DisableColors: true, TimeFormat: time.Kitchen}
+		b, _ := f.Format(&Entry{Time: ts, Level: InfoLevel, Message: "a message longer than the column width of forty", Fields: Fields{"k": "v"}})
+		assert.Equal(t, "3:04PM INFO  a message longer than the column width of forty k=v\n", string(b))
+	})
+}
+
+func TestNewConsoleFormatter(t *testing.T) {
+	assert.True(t, NewConsoleFormatter(&syncBuffer{}).DisableColors)
+
+	restore := isTerminal
+	defer func() { isTerminal = restore }()
+	isTerminal = func(io.Writer) bool { return true }
+	os.Unsetenv("NO_COLOR")
+	assert.False(t, NewConsoleFormatter(os.Stdout).DisableColors)
+	os.Setenv("NO_COLOR", "")
+	defer os.Unsetenv("NO_COLOR")
+	assert.True(t, NewConsoleFormatter(os.Stdout).DisableColors)
+}
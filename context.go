@@ -0,0 +1,94 @@
+package golog
+
+import "context"
+
+type (
+	loggerKey struct{}
+	fieldsKey struct{}
+)
+
+// WithContext returns a copy of ctx that carries the logger.
+func WithContext(ctx context.Context, logger FieldLogger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// ContextExtractor returns the fields of the values carried by a
+// context, e.g. the IDs of its trace span.
+type ContextExtractor func(ctx context.Context) Fields
+
+// AddContextExtractor registers x to add the fields it extracts to
+// the fields of every context, returned by ContextFields and attached
+// by FromContext. The fields carried by the context win over them.
+func AddContextExtractor(x ContextExtractor) {
+	updateState(func(s *globalState) {
+		extractors := make([]ContextExtractor, len(s.contextExtractors), len(s.contextExtractors)+1)
+		copy(extractors, s.contextExtractors)
+		s.contextExtractors = append(extractors, x)
+	})
+}
+
+// ResetContextExtractors removes all the registered context extractors.
+func ResetContextExtractors() {
+	updateState(func(s *globalState) {
+		s.contextExtractors = nil
+	})
+}
+
+// ContextWithFields returns a copy of ctx that carries fields,
+// merged with the fields already carried by ctx.
+func ContextWithFields(ctx context.Context, fields Fields) context.Context {
+	parent, _ := ctx.Value(fieldsKey{}).(Fields)
+	merged := make(Fields, len(parent)+len(fields))
+	for k, v := range parent {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// ContextFields returns the fields carried by ctx, merged with the
+// fields of the context extractors. The returned Fields must not be
+// modified.
+func ContextFields(ctx context.Context) Fields {
+	fields, _ := ctx.Value(fieldsKey{}).(Fields)
+	extractors := getState().contextExtractors
+	if len(extractors) == 0 {
+		return fields
+	}
+	var merged Fields
+	for _, x := range extractors {
+		for k, v := range x(ctx) {
+			if merged == nil {
+				merged = make(Fields, len(fields)+2)
+			}
+			merged[k] = v
+		}
+	}
+	if merged == nil {
+		return fields
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return merged
+}
+
+// FromContext returns the logger carried by ctx, or InfoLogger when
+// there is none, with the fields carried by ctx attached. The fields
+// of the context are kept apart from the fields of the logger, so
+// they override each other according to the field precedence.
+func FromContext(ctx context.Context) FieldLogger {
+	logger, ok := ctx.Value(loggerKey{}).(FieldLogger)
+	if !ok {
+		logger = InfoLogger
+	}
+	if fields := ContextFields(ctx); len(fields) > 0 {
+		if cl, ok := logger.(contextLogger); ok {
+			return cl.withContextFields(fields)
+		}
+		return logger.WithFields(fields)
+	}
+	return logger
+}
@@ -0,0 +1,54 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+
+	t.Run("default logger", func(t *testing.T) {
+		assert.Equal(t, Logger(InfoLogger), FromContext(context.Background()))
+	})
+	t.Run("logger and fields are carried", func(t *testing.T) {
+		var out bytes.Buffer
+		l := newStdLogger(WarningLevel, &out, 0)
+		ctx := WithContext(context.Background(), l)
+		ctx = ContextWithFields(ctx, Fields{"request_id": "abc", "user_id": 1})
+		ctx = ContextWithFields(ctx, Fields{"user_id": 2})
+
+		FromContext(ctx).Print("Hello World")
+		assert.Equal(t, "WARNING: Hello World request_id=abc user_id=2\n", out.String())
+	})
+	t.Run("parent fields are not modified", func(t *testing.T) {
+		parent := ContextWithFields(context.Background(), Fields{"a": 1})
+		ContextWithFields(parent, Fields{"a": 2, "b": 3})
+		assert.Equal(t, Fields{"a": 1}, ContextFields(parent))
+	})
+	t.Run("context extractors", func(t *testing.T) {
+		type spanKey struct{}
+		defer ResetContextExtractors()
+		AddContextExtractor(func(ctx context.Context) Fields {
+			if span, ok := ctx.Value(spanKey{}).(string); ok {
+				return Fields{"span_id": span, "request_id": "from span"}
+			}
+			return nil
+		})
+		var out bytes.Buffer
+		l := newStdLogger(WarningLevel, &out, 0)
+		ctx := WithContext(context.Background(), l)
+		FromContext(ctx).Print("no span")
+		ctx = ContextWithFields(ctx, Fields{"request_id": "abc"})
+		FromContext(context.WithValue(ctx, spanKey{}, "1")).Print("first span")
+		FromContext(context.WithValue(ctx, spanKey{}, "2")).Print("second span")
+		assert.Equal(t, "WARNING: no span\n"+
+			"WARNING: first span request_id=abc span_id=1\n"+
+			"WARNING: second span request_id=abc span_id=2\n", out.String())
+		assert.Equal(t, Fields{"request_id": "abc"}, ContextFields(ctx))
+	})
+}
@@ -0,0 +1,53 @@
+package golog
+
+// The stages of the pipeline below are shared by the backends, the
+// stdLogger and the Logrus adapter, so the bundling, the sampling, the
+// processors and the hooks behave the same whatever encodes and
+// writes the entries:
+//
+//	level gates -> mute rules -> admit -> process -> encode and write -> escalate
+//
+// The backends only differ by how they encode and write the entries
+// kept by admit.
+
+// admit bundles, samples and counts the entry e of a backend whose
+// samplers and counters are samplers and counters, and reports whether
+// e is kept. The samplers of the package are consulted first. The
+// Forced entries are not sampled, and the fatal ones are counted as
+// ErrorLevel. The fields of e must be resolved.
+func admit(st *globalState, e *Entry, samplers []Sampler, counters *levelCounters) bool {
+	if b := st.bundle; b != nil {
+		// The disabled entries only reach admit to be bundled.
+		b.add(e)
+		if !st.enabled(e.Level, e.Fields) {
+			return false
+		}
+		if lines := b.take(e); len(lines) > 0 {
+			addCallField(e, BundleKey, lines)
+		}
+	}
+	if !e.Forced && (!sample(st.samplers, e) || !sample(samplers, e)) {
+		return false
+	}
+	lvl := e.Level
+	if e.fatal {
+		lvl = ErrorLevel
+	}
+	countEntry(counters, lvl)
+	return true
+}
+
+// process runs the processors and the hooks of the package, then the
+// hooks of the backend, on the entry e kept by admit. The Fields of e
+// are copied first, so they are owned by the entry.
+func process(st *globalState, e *Entry, hooks []Hook) {
+	hooked := len(st.hooks)+len(hooks) > 0
+	if !hooked && len(st.processors) == 0 {
+		return
+	}
+	e.shared = e.shared || hooked
+	e.Fields = copyFields(e.Fields)
+	runProcessors(st.processors, e)
+	fireHooks(st.hooks, e)
+	fireHooks(hooks, e)
+}
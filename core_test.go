@@ -0,0 +1,97 @@
+package golog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorePipeline(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	defer ResetSamplers()
+	defer ResetProcessors()
+	defer ResetHooks()
+	AddSampler(dropErrors{})
+	AddProcessor(func(e *Entry) {
+		if _, ok := e.Fields["password"]; ok {
+			e.Fields["password"] = "[REDACTED]"
+		}
+	})
+	var fired []string
+	AddHook(HookFunc(func(e *Entry) error {
+		fired = append(fired, e.Message)
+		return nil
+	}))
+
+	out := &syncBuffer{}
+	backends := map[string]func() FieldLogger{
+		"std": func() FieldLogger { return newStdLogger(InfoLevel, out, 0) },
+		"logrus": func() FieldLogger {
+			l := NewLogrusLogger(InfoLevel)
+			l.SetOutput(out)
+			l.SetFormatter(&TextFormatter{})
+			return l
+		},
+	}
+	for name, newLogger := range backends {
+		t.Run(name, func(t *testing.T) {
+			out.Reset()
+			fired = nil
+			l := newLogger()
+			l.WithFields(Fields{"password": "hunter2"}).Info("login")
+			l.Error("sampled out")
+			l.Debug("disabled")
+			l.Warnw("slow", String("password", "hunter2"))
+
+			assert.Equal(t, "INFO: login password=[REDACTED]\nWARNING: slow password=[REDACTED]\n", out.String())
+			assert.Equal(t, []string{"login", "slow"}, fired)
+			// The entry of WithFields is counted by the child logger.
+			assert.Equal(t, map[Level]uint64{WarningLevel: 1}, nonZero(l.(interface{ Counters() map[Level]uint64 }).Counters()))
+		})
+	}
+}
+
+func TestCorePipeline_Bundling(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	SetContextBundling("rid", 4)
+	defer SetContextBundling("", 0)
+
+	out := &syncBuffer{}
+	backends := map[string]func() FieldLogger{
+		"std": func() FieldLogger { return newStdLogger(InfoLevel, out, 0) },
+		"logrus": func() FieldLogger {
+			l := NewLogrusLogger(InfoLevel)
+			l.SetOutput(out)
+			l.SetFormatter(&TextFormatter{})
+			return l
+		},
+	}
+	for name, newLogger := range backends {
+		t.Run(name, func(t *testing.T) {
+			out.Reset()
+			l := newLogger()
+			l.WithFields(Fields{"rid": name}).Debug("d1")
+			l.Debugw("d2", String("rid", name))
+			l.WithFields(Fields{"rid": name}).Error("boom")
+
+			got := out.String()
+			assert.Contains(t, got, "ERROR: boom")
+			assert.Contains(t, got, "DEBUG d1")
+			assert.Contains(t, got, "DEBUG d2")
+			assert.NotContains(t, got, "DEBUG: ", "the bundled entries are not written")
+		})
+	}
+}
+
+// nonZero returns the counters without the zero ones.
+func nonZero(counters map[Level]uint64) map[Level]uint64 {
+	out := make(map[Level]uint64)
+	for lvl, n := range counters {
+		if n > 0 {
+			out[lvl] = n
+		}
+	}
+	return out
+}
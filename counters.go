@@ -0,0 +1,56 @@
+package golog
+
+import "sync/atomic"
+
+// globalCounters counts the entries emitted by all the loggers.
+var globalCounters levelCounters
+
+// levelCounters counts emitted entries per level.
+// The zero value is ready to use.
+type levelCounters struct {
+	n [DisabledLevel]uint64
+}
+
+func (c *levelCounters) inc(lvl Level) {
+	if lvl < DebugLevel || lvl >= DisabledLevel {
+		return
+	}
+	atomic.AddUint64(&c.n[lvl], 1)
+}
+
+func (c *levelCounters) snapshot() map[Level]uint64 {
+	m := make(map[Level]uint64, len(c.n))
+	for i := range c.n {
+		m[Level(i)] = atomic.LoadUint64(&c.n[i])
+	}
+	return m
+}
+
+func (c *levelCounters) reset() {
+	for i := range c.n {
+		atomic.StoreUint64(&c.n[i], 0)
+	}
+}
+
+// countEntry records an emitted entry of level lvl on both the
+// logger counters c and the global counters.
+func countEntry(c *levelCounters, lvl Level) {
+	c.inc(lvl)
+	globalCounters.inc(lvl)
+}
+
+// Counters returns the number of entries emitted per level by all
+// the loggers since the start of the program or the last call
+// to ResetCounters. Suppressed entries are not counted. Fatal
+// entries are counted as ErrorLevel, whatever the level of their
+// logger, by both backends.
+func Counters() map[Level]uint64 {
+	return globalCounters.snapshot()
+}
+
+// ResetCounters sets all the global counters, and the byte
+// counters of the sinks, to zero.
+func ResetCounters() {
+	globalCounters.reset()
+	resetBytes()
+}
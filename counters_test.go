@@ -0,0 +1,73 @@
+package golog
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounters(t *testing.T) {
+	t.Run("counts only the emitted entries", func(t *testing.T) {
+		defer SetLevel(InfoLevel)
+		ResetCounters()
+		SetLevel(InfoLevel)
+		l := newStdLogger(WarningLevel, ioutil.Discard, 0)
+		d := newStdLogger(DebugLevel, ioutil.Discard, 0)
+
+		l.Print("Hello")
+		l.Printf("Hello %s", "World")
+		d.Print("Suppressed")
+
+		assert.Equal(t, uint64(2), Counters()[WarningLevel])
+		assert.Equal(t, uint64(0), Counters()[DebugLevel])
+		assert.Equal(t, uint64(2), l.Counters()[WarningLevel])
+		assert.Equal(t, uint64(0), d.Counters()[DebugLevel])
+	})
+	t.Run("package level functions", func(t *testing.T) {
+		defer SetLevel(InfoLevel)
+		defer ErrorLogger.SetOutput(os.Stdout)
+		ResetCounters()
+		SetLevel(DebugLevel)
+		ErrorLogger.SetOutput(ioutil.Discard)
+
+		Errorf("Hello %s", "World")
+		Error("Hello World")
+
+		assert.Equal(t, uint64(2), Counters()[ErrorLevel])
+	})
+	t.Run("fatal entries are counted as errors", func(t *testing.T) {
+		defer SetLevel(InfoLevel)
+		SetLevel(InfoLevel)
+		lr := NewLogrusLogger(InfoLevel)
+		lr.SetOutput(ioutil.Discard)
+		loggers := map[string]FieldLogger{
+			"std":    newStdLogger(InfoLevel, ioutil.Discard, 0),
+			"logrus": lr,
+		}
+		for name, l := range loggers {
+			t.Run(name, func(t *testing.T) {
+				ResetCounters()
+				l.(ExitFuncSetter).SetExitFunc(func(int) {})
+				l.Fatal("boom")
+				l.Fatalf("boom %d", 2)
+				assert.Equal(t, map[Level]uint64{ErrorLevel: 2}, nonZero(Counters()))
+				assert.Equal(t, map[Level]uint64{ErrorLevel: 2}, nonZero(l.(interface{ Counters() map[Level]uint64 }).Counters()))
+			})
+		}
+	})
+	t.Run("reset", func(t *testing.T) {
+		globalCounters.inc(InfoLevel)
+		ResetCounters()
+		for lvl, n := range Counters() {
+			assert.Zero(t, n, lvl.String())
+		}
+	})
+	t.Run("levels out of range are ignored", func(t *testing.T) {
+		var c levelCounters
+		c.inc(DisabledLevel)
+		c.inc(Level(-1))
+		assert.Len(t, c.snapshot(), int(DisabledLevel))
+	})
+}
@@ -0,0 +1,123 @@
+package golog
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Credentials authenticate the requests of a sink.
+type Credentials struct {
+	// Token is sent as a bearer token.
+	Token string
+	// Expiry is when the token expires. Zero means never.
+	Expiry time.Time
+}
+
+// CredentialsProvider provides the credentials of a sink, which asks
+// for them before every request, so expiring tokens are refreshed
+// without restarting the process. The providers of the cloud SDKs are
+// adapted with CredentialsFunc, and cached with CachedCredentials when
+// getting a token is expensive.
+type CredentialsProvider interface {
+	Credentials() (Credentials, error)
+}
+
+// CredentialsFunc is an adapter to use a function as a
+// CredentialsProvider.
+type CredentialsFunc func() (Credentials, error)
+
+// Credentials calls f().
+func (f CredentialsFunc) Credentials() (Credentials, error) {
+	return f()
+}
+
+// StaticCredentials returns a CredentialsProvider of a token
+// that never changes.
+func StaticCredentials(token string) CredentialsProvider {
+	return CredentialsFunc(func() (Credentials, error) {
+		return Credentials{Token: token}, nil
+	})
+}
+
+// EnvCredentials returns a CredentialsProvider of the token held by
+// the environment variable name, read on every call.
+func EnvCredentials(name string) CredentialsProvider {
+	return CredentialsFunc(func() (Credentials, error) {
+		token := os.Getenv(name)
+		if token == "" {
+			return Credentials{}, fmt.Errorf("golog: credentials: %s is not set", name)
+		}
+		return Credentials{Token: token}, nil
+	})
+}
+
+// FileCredentials returns a CredentialsProvider of the token held by
+// the file at path, e.g. a Kubernetes projected service account token.
+// The file is read again whenever it changes, so the token can be
+// rotated by another process. Surrounding spaces are trimmed.
+func FileCredentials(path string) CredentialsProvider {
+	return &fileCredentials{path: path}
+}
+
+type fileCredentials struct {
+	path string
+
+	mu    sync.Mutex
+	mod   time.Time
+	size  int64
+	token string
+}
+
+func (c *fileCredentials) Credentials() (Credentials, error) {
+	fi, err := os.Stat(c.path)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("golog: credentials: %v", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == "" || !fi.ModTime().Equal(c.mod) || fi.Size() != c.size {
+		b, err := ioutil.ReadFile(c.path)
+		if err != nil {
+			return Credentials{}, fmt.Errorf("golog: credentials: %v", err)
+		}
+		token := strings.TrimSpace(string(b))
+		if token == "" {
+			return Credentials{}, fmt.Errorf("golog: credentials: %s is empty", c.path)
+		}
+		c.token, c.mod, c.size = token, fi.ModTime(), fi.Size()
+	}
+	return Credentials{Token: c.token}, nil
+}
+
+// CachedCredentials returns a CredentialsProvider caching the
+// credentials of p until margin before they expire. The credentials
+// without expiry are cached forever.
+func CachedCredentials(p CredentialsProvider, margin time.Duration) CredentialsProvider {
+	return &cachedCredentials{p: p, margin: margin}
+}
+
+type cachedCredentials struct {
+	p      CredentialsProvider
+	margin time.Duration
+
+	mu     sync.Mutex
+	cached *Credentials
+}
+
+func (c *cachedCredentials) Credentials() (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cr := c.cached; cr != nil && (cr.Expiry.IsZero() || now().Before(cr.Expiry.Add(-c.margin))) {
+		return *cr, nil
+	}
+	cr, err := c.p.Credentials()
+	if err != nil {
+		return Credentials{}, err
+	}
+	c.cached = &cr
+	return cr, nil
+}
@@ -0,0 +1,119 @@
+package golog
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsProviders(t *testing.T) {
+	t.Run("static", func(t *testing.T) {
+		cr, err := StaticCredentials("secret").Credentials()
+		require.NoError(t, err)
+		assert.Equal(t, Credentials{Token: "secret"}, cr)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		p := EnvCredentials("GOLOG_TEST_TOKEN")
+		os.Unsetenv("GOLOG_TEST_TOKEN")
+		_, err := p.Credentials()
+		assert.EqualError(t, err, "golog: credentials: GOLOG_TEST_TOKEN is not set")
+		os.Setenv("GOLOG_TEST_TOKEN", "from env")
+		defer os.Unsetenv("GOLOG_TEST_TOKEN")
+		cr, err := p.Credentials()
+		require.NoError(t, err)
+		assert.Equal(t, "from env", cr.Token)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "golog")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "token")
+		p := FileCredentials(path)
+		_, err = p.Credentials()
+		assert.Error(t, err)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("first\n"), 0600))
+		cr, err := p.Credentials()
+		require.NoError(t, err)
+		assert.Equal(t, "first", cr.Token)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("second-token\n"), 0600))
+		cr, err = p.Credentials()
+		require.NoError(t, err)
+		assert.Equal(t, "second-token", cr.Token)
+
+		require.NoError(t, ioutil.WriteFile(path, []byte(" \n"), 0600))
+		_, err = p.Credentials()
+		assert.EqualError(t, err, "golog: credentials: "+path+" is empty")
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		defer func() { now = time.Now }()
+		clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		now = func() time.Time { return clock }
+		calls := 0
+		p := CachedCredentials(CredentialsFunc(func() (Credentials, error) {
+			calls++
+			if calls == 3 {
+				return Credentials{}, errors.New("unavailable")
+			}
+			return Credentials{Token: "t", Expiry: clock.Add(time.Hour)}, nil
+		}), time.Minute)
+
+		for i := 0; i < 3; i++ {
+			_, err := p.Credentials()
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, calls)
+		clock = clock.Add(59 * time.Minute)
+		_, err := p.Credentials()
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls, "refreshed within the margin")
+		clock = clock.Add(2 * time.Hour)
+		_, err = p.Credentials()
+		assert.EqualError(t, err, "unavailable")
+	})
+}
+
+func TestHTTPWriter_Credentials(t *testing.T) {
+	var auth []string
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = append(auth, r.Header.Get("Authorization"))
+	}))
+	defer sink.Close()
+
+	token := "first"
+	w, err := NewHTTPWriter(HTTPConfig{
+		URL:   sink.URL,
+		Proxy: "none",
+		Credentials: CredentialsFunc(func() (Credentials, error) {
+			if token == "" {
+				return Credentials{}, errors.New("no token")
+			}
+			return Credentials{Token: token}, nil
+		}),
+	})
+	require.NoError(t, err)
+	defer w.Close()
+	_, err = w.Write([]byte("INFO: one\n"))
+	require.NoError(t, err)
+	token = "second"
+	_, err = w.Write([]byte("INFO: two\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bearer first", "Bearer second"}, auth)
+
+	token = ""
+	_, err = w.Write([]byte("INFO: three\n"))
+	assert.EqualError(t, err, "no token")
+	assert.Len(t, auth, 2)
+}
@@ -0,0 +1,39 @@
+package golog
+
+import (
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// diagHandler holds the diagHolder receiving the internal errors.
+var diagHandler atomic.Value
+
+// diagHolder wraps the handler so diagHandler always
+// stores the same concrete type.
+type diagHolder struct {
+	fn func(err error)
+}
+
+// SetDiagnosticHandler sets the function receiving the internal
+// errors of golog, e.g. the failures of hooks, formatters and
+// forwarders, so they can be counted or surfaced elsewhere. The
+// handler must not log with golog. By default, and when fn is nil,
+// the errors are written to os.Stderr.
+func SetDiagnosticHandler(fn func(err error)) {
+	diagHandler.Store(diagHolder{fn})
+}
+
+// reportError passes err to the diagnostic handler.
+func reportError(err error) {
+	if h, ok := diagHandler.Load().(diagHolder); ok && h.fn != nil {
+		h.fn(err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, err)
+}
+
+// reportf reports an internal error formatted as fmt.Errorf.
+func reportf(format string, v ...interface{}) {
+	reportError(fmt.Errorf(format, v...))
+}
@@ -0,0 +1,28 @@
+package golog
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingFormatter struct{}
+
+func (failingFormatter) Format(*Entry) ([]byte, error) {
+	return nil, errors.New("unsupported value")
+}
+
+func TestSetDiagnosticHandler(t *testing.T) {
+	defer SetDiagnosticHandler(nil)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var errs []string
+	SetDiagnosticHandler(func(err error) { errs = append(errs, err.Error()) })
+
+	l := newStdLogger(InfoLevel, ioutil.Discard, 0)
+	l.SetFormatter(failingFormatter{})
+	l.Print("lost")
+	assert.Equal(t, []string{"golog: failed to format entry: unsupported value"}, errs)
+}
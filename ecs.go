@@ -0,0 +1,70 @@
+package golog
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+// ecsVersion is the version of the Elastic Common Schema
+// written by the ECSFormatter.
+const ecsVersion = "1.6.0"
+
+// ecsKeys are the keys written by the ECSFormatter.
+var ecsKeys = map[string]bool{
+	"@timestamp":           true,
+	"log.level":            true,
+	"message":              true,
+	"ecs.version":          true,
+	"log.origin.file.name": true,
+	"log.origin.file.line": true,
+	"log.origin.function":  true,
+	MessageTemplateKey:     true,
+}
+
+// ECSFormatter formats an entry as one JSON object per line following
+// the Elastic Common Schema, so it can be shipped to Elasticsearch
+// without an ingest pipeline:
+//
+//	{"@timestamp":"2020-03-04T05:06:07.891Z","log.level":"warning","message":"disk almost full","ecs.version":"1.6.0","used":93}
+//
+// The time is written in UTC with milliseconds, and the caller as the
+// log.origin.file.name, log.origin.file.line and log.origin.function
+// keys. Fields that collide with the keys of the formatter are
+// prefixed with "fields.", as by the JSONFormatter.
+type ECSFormatter struct{}
+
+// Format implements the Formatter interface.
+func (f *ECSFormatter) Format(e *Entry) ([]byte, error) {
+	return f.appendFormat(make([]byte, 0, 160+len(e.Message)), e)
+}
+
+// appendFormat appends the formatted entry e to b.
+func (f *ECSFormatter) appendFormat(b []byte, e *Entry) ([]byte, error) {
+	esc := getEscaper()
+	b = append(b, `{"@timestamp":"`...)
+	b = appendTime(b, e.Time.UTC(), layoutRFC3339Milli)
+	b = append(b, `","log.level":`...)
+	b = esc.AppendJSON(b, e.Level.name())
+	b = append(b, `,"message":`...)
+	b = esc.AppendJSON(b, e.Message)
+	b = append(b, `,"ecs.version":"`+ecsVersion+`"`...)
+	if e.Caller != nil {
+		b = append(b, `,"log.origin.file.name":`...)
+		b = esc.AppendJSON(b, filepath.Base(e.Caller.File))
+		b = append(b, `,"log.origin.file.line":`...)
+		b = strconv.AppendInt(b, int64(e.Caller.Line), 10)
+		if e.Caller.Function != "" {
+			b = append(b, `,"log.origin.function":`...)
+			b = esc.AppendJSON(b, functionName(e.Caller.Function))
+		}
+	}
+	if e.Template != "" {
+		b = append(b, `,"`+MessageTemplateKey+`":`...)
+		b = esc.AppendJSON(b, e.Template)
+	}
+	b, err := appendJSONFields(b, e.Fields, func(k string) bool { return ecsKeys[k] })
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '}', '\n'), nil
+}
@@ -0,0 +1,34 @@
+package golog
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECSFormatter(t *testing.T) {
+	entry := &Entry{
+		Time:    time.Date(2020, 3, 4, 5, 6, 7, 891011000, time.FixedZone("CET", 3600)),
+		Level:   ErrorLevel,
+		Message: "request failed",
+		Fields:  Fields{"status": 500, "message": "collides"},
+		Caller:  &runtime.Frame{File: "/src/app/main.go", Line: 42, Function: "example.com/app.handle"},
+	}
+	got, err := (&ECSFormatter{}).Format(entry)
+	require.NoError(t, err)
+	assert.Equal(t, byte('\n'), got[len(got)-1])
+	assert.JSONEq(t, `{
+		"@timestamp": "2020-03-04T04:06:07.891Z",
+		"log.level": "error",
+		"message": "request failed",
+		"ecs.version": "1.6.0",
+		"log.origin.file.name": "main.go",
+		"log.origin.file.line": 42,
+		"log.origin.function": "app.handle",
+		"status": 500,
+		"fields.message": "collides"
+	}`, string(got))
+}
@@ -0,0 +1,60 @@
+package golog
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// Entry is a single log event, as passed to the formatters.
+type Entry struct {
+	// Time is when the entry was logged.
+	Time time.Time
+	// Level is the severity of the entry.
+	Level Level
+	// Message is the log message without the trailing newline.
+	Message string
+	// Template is the format string of a Printf-style call. It is
+	// only set when message templates are enabled.
+	Template string
+	// Fields are the key/value pairs attached to the entry.
+	// They must not be modified by the formatters.
+	Fields Fields
+	// Caller is the call site of the entry. It is nil when
+	// the formatter doesn't report it.
+	Caller *runtime.Frame
+	// Forced tells a must-deliver entry, which is not muted, sampled
+	// or discarded by the backpressure of an AsyncWriter, although
+	// its level must be enabled. See Force.
+	Forced bool
+
+	// sources are the fields of the entry from other sources
+	// than the call, merged into Fields by ResolvedFields.
+	sources *fieldSources
+	// shared tells an entry passed to the hooks, which may retain
+	// it, so it is not put back into the pool.
+	shared bool
+	// probe records what became of the entries of SelfTest.
+	probe *selfTestProbe
+	// fatal tells the entries of the Fatal calls, which are
+	// counted as ErrorLevel whatever their level.
+	fatal bool
+}
+
+// callerFrame returns the frame of the function calldepth
+// levels above the caller of callerFrame, with the same meaning
+// as the argument of runtime.Caller.
+func callerFrame(calldepth int) *runtime.Frame {
+	var pcs [1]uintptr
+	if runtime.Callers(calldepth+2, pcs[:]) == 0 {
+		return nil
+	}
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	return &frame
+}
+
+// shortCaller returns the file base name and line of the frame.
+func shortCaller(f *runtime.Frame) string {
+	return filepath.Base(f.File) + ":" + strconv.Itoa(f.Line)
+}
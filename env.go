@@ -0,0 +1,77 @@
+package golog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// The environment variables read by ConfigureFromEnv.
+const (
+	EnvLevel  = "GOLOG_LEVEL"
+	EnvFormat = "GOLOG_FORMAT"
+	EnvOutput = "GOLOG_OUTPUT"
+)
+
+// ConfigureFromEnv configures the package level loggers from the
+// environment:
+//
+//	GOLOG_LEVEL   the global level, see ParseLevel
+//	GOLOG_FORMAT  "text" (the default) or "json"
+//	GOLOG_OUTPUT  "stdout" (the default), "stderr" or a file path
+//	              to which the entries are appended
+//
+// Unset variables leave the corresponding configuration untouched.
+// Nothing is changed when one of the variables is invalid.
+func ConfigureFromEnv() error {
+	return configureFromEnv(os.Getenv)
+}
+
+func configureFromEnv(getenv func(string) string) error {
+	var apply []func()
+
+	if v := getenv(EnvLevel); v != "" {
+		lvl, err := ParseLevel(v)
+		if err != nil {
+			return fmt.Errorf("golog: %s: %v", EnvLevel, err)
+		}
+		apply = append(apply, func() { SetLevel(lvl) })
+	}
+
+	if v := getenv(EnvFormat); v != "" {
+		switch strings.ToLower(v) {
+		case "text":
+			// The text formatters of the loggers are restored,
+			// replacing a formatter set before.
+			apply = append(apply, func() {
+				for _, l := range packageLoggers() {
+					l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+				}
+			})
+		case "json":
+			apply = append(apply, func() { SetFormatter(&JSONFormatter{}) })
+		default:
+			return fmt.Errorf("golog: %s: unknown format %q", EnvFormat, v)
+		}
+	}
+
+	if v := getenv(EnvOutput); v != "" {
+		switch strings.ToLower(v) {
+		case "stdout":
+			apply = append(apply, func() { SetOutput(os.Stdout) })
+		case "stderr":
+			apply = append(apply, func() { SetOutput(os.Stderr) })
+		default:
+			f, err := os.OpenFile(v, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+			if err != nil {
+				return fmt.Errorf("golog: %s: %v", EnvOutput, err)
+			}
+			apply = append(apply, func() { SetOutput(f) })
+		}
+	}
+
+	for _, fn := range apply {
+		fn()
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package golog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureFromEnv(t *testing.T) {
+	env := func(m map[string]string) func(string) string {
+		return func(k string) string { return m[k] }
+	}
+	defer SetLevel(InfoLevel)
+	defer SetOutput(os.Stdout)
+	formatters := map[*stdLogger]Formatter{}
+	for _, l := range packageLoggers() {
+		formatters[l] = l.out.formatter
+	}
+	defer func() {
+		for l, f := range formatters {
+			l.SetFormatter(f)
+		}
+	}()
+
+	t.Run("level, format and file output", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "golog")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "app.log")
+
+		err = configureFromEnv(env(map[string]string{
+			EnvLevel:  "warning",
+			EnvFormat: "json",
+			EnvOutput: path,
+		}))
+		require.NoError(t, err)
+
+		Info("not written")
+		Warning("written")
+		b, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "not written")
+		assert.Contains(t, string(b), `"msg":"written"`)
+	})
+	t.Run("text format replaces json", func(t *testing.T) {
+		require.NoError(t, configureFromEnv(env(map[string]string{EnvFormat: "json"})))
+		require.IsType(t, &JSONFormatter{}, InfoLogger.out.formatter)
+
+		require.NoError(t, configureFromEnv(env(map[string]string{EnvFormat: "text"})))
+		for _, l := range packageLoggers() {
+			assert.Equal(t, &TextFormatter{Flags: defaultFlags(l.level)}, l.out.formatter)
+		}
+	})
+	t.Run("unset variables are ignored", func(t *testing.T) {
+		SetLevel(ErrorLevel)
+		require.NoError(t, configureFromEnv(env(nil)))
+		assert.Equal(t, ErrorLevel, getState().currentLevel)
+	})
+	t.Run("invalid values change nothing", func(t *testing.T) {
+		SetLevel(ErrorLevel)
+		err := configureFromEnv(env(map[string]string{EnvLevel: "debug", EnvFormat: "xml"}))
+		assert.Error(t, err)
+		assert.Equal(t, ErrorLevel, getState().currentLevel)
+
+		err = configureFromEnv(env(map[string]string{EnvLevel: "loud"}))
+		assert.Error(t, err)
+	})
+}
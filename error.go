@@ -0,0 +1,147 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrorKey is the field key holding the error attached by WithError.
+// The messages of the errors it wraps are held by the error.causes
+// field, and its stack trace, when enabled, by the error.stack field.
+// When enabled by SetErrorChains, its chain is held by the error.chain
+// field and the message of its root cause by the error.root field.
+const ErrorKey = "error"
+
+// ErrorCause is an error of the chain of an error, as reported
+// by the error.chain field.
+type ErrorCause struct {
+	// Type is the dynamic type of the error, e.g. "*fmt.wrapError".
+	Type string `json:"type"`
+	// Message is the message of the error.
+	Message string `json:"message"`
+}
+
+// ErrorChain is the chain of the errors wrapped by an error, from
+// the error itself to its root cause.
+type ErrorChain []ErrorCause
+
+// String returns the causes of c as "type: message", separated
+// by " | ".
+func (c ErrorChain) String() string {
+	var b strings.Builder
+	for i, cause := range c {
+		if i > 0 {
+			b.WriteString(" | ")
+		}
+		b.WriteString(cause.Type)
+		b.WriteString(": ")
+		b.WriteString(cause.Message)
+	}
+	return b.String()
+}
+
+// SetErrorStacks enables or disables attaching the stack trace
+// recorded by the errors to the entries of WithError. The stack is
+// the %+v form of the innermost error of the chain having a
+// StackTrace method, as the errors of github.com/pkg/errors have.
+func SetErrorStacks(enabled bool) {
+	updateState(func(s *globalState) {
+		s.errorStacks = enabled
+	})
+}
+
+// SetErrorChains enables or disables attaching the chain of the
+// errors of WithError as the error.chain field, an ErrorChain, and
+// the message of their root cause, the innermost error, as the
+// error.root field, so the entries can be grouped by root cause
+// rather than by the outermost wrapper.
+func SetErrorChains(enabled bool) {
+	updateState(func(s *globalState) {
+		s.errorChains = enabled
+	})
+}
+
+// WithError returns ErrorLogger with err attached, e.g.
+//
+//	golog.WithError(err).Error("failed to save the user")
+func WithError(err error) FieldLogger {
+	return ErrorLogger.WithError(err)
+}
+
+// WithError returns a logger like l with err attached as
+// the ErrorKey field. A nil err attaches nothing.
+func (l *stdLogger) WithError(err error) FieldLogger {
+	if err == nil {
+		return l
+	}
+	return l.WithFields(errorFields(err))
+}
+
+// WithError returns a logger like l with err attached as
+// the ErrorKey field. A nil err attaches nothing.
+func (l *Logrus) WithError(err error) FieldLogger {
+	if err == nil {
+		return l
+	}
+	return l.WithFields(errorFields(err))
+}
+
+// errorFields returns the fields describing err.
+func errorFields(err error) Fields {
+	st := getState()
+	fields := Fields{ErrorKey: err}
+	var causes []string
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		causes = append(causes, cause.Error())
+	}
+	if len(causes) > 0 {
+		fields[ErrorKey+".causes"] = causes
+	}
+	if st.errorChains {
+		chain := errorChain(err)
+		fields[ErrorKey+".chain"] = chain
+		fields[ErrorKey+".root"] = chain[len(chain)-1].Message
+	}
+	if st.errorStacks {
+		if stack := errorStack(err); stack != "" {
+			fields[ErrorKey+".stack"] = stack
+		}
+	}
+	return fields
+}
+
+// errorChain returns the chain of err, from err to its root cause.
+func errorChain(err error) ErrorChain {
+	var chain ErrorChain
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, ErrorCause{
+			Type:    reflect.TypeOf(err).String(),
+			Message: err.Error(),
+		})
+	}
+	return chain
+}
+
+// errorStack returns the %+v form of the innermost error of the
+// chain of err having a StackTrace method, or "" when none has.
+func errorStack(err error) Stacktrace {
+	var traced error
+	for ; err != nil; err = errors.Unwrap(err) {
+		if hasStackTrace(err) {
+			traced = err
+		}
+	}
+	if traced == nil {
+		return ""
+	}
+	return Stacktrace(fmt.Sprintf("%+v", traced))
+}
+
+// hasStackTrace reports whether err has a StackTrace method without
+// arguments, whatever its result type.
+func hasStackTrace(err error) bool {
+	m, ok := reflect.TypeOf(err).MethodByName("StackTrace")
+	return ok && m.Type.NumIn() == 1 && m.Type.NumOut() == 1
+}
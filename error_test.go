@@ -0,0 +1,102 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tracedError mimics the errors of github.com/pkg/errors.
+type tracedError struct{ msg string }
+
+func (e tracedError) Error() string         { return e.msg }
+func (e tracedError) StackTrace() []uintptr { return nil }
+func (e tracedError) Format(s fmt.State, verb rune) {
+	io.WriteString(s, e.msg)
+	if verb == 'v' && s.Flag('+') {
+		io.WriteString(s, "\nmain.save\n\t/src/main.go:12")
+	}
+}
+
+func TestWithError(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var out syncBuffer
+	l := newStdLogger(InfoLevel, &out, 0)
+
+	t.Run("wrapped errors", func(t *testing.T) {
+		out.Reset()
+		err := fmt.Errorf("save user: %w", fmt.Errorf("insert: %w", io.ErrUnexpectedEOF))
+		l.WithError(err).Error("failed")
+		assert.Equal(t, "ERROR: failed error=\"save user: insert: unexpected EOF\" "+
+			"error.causes=\"insert: unexpected EOF,unexpected EOF\"\n", out.String())
+	})
+
+	t.Run("nil error", func(t *testing.T) {
+		out.Reset()
+		l.WithError(nil).Error("failed")
+		assert.Equal(t, "ERROR: failed\n", out.String())
+	})
+
+	t.Run("stack traces", func(t *testing.T) {
+		out.Reset()
+		err := fmt.Errorf("save user: %w", tracedError{"boom"})
+		l.WithError(err).Error("failed")
+		assert.NotContains(t, out.String(), "main.save")
+
+		out.Reset()
+		SetErrorStacks(true)
+		defer SetErrorStacks(false)
+		l.WithError(err).Error("failed")
+		assert.Equal(t, "ERROR: failed error=\"save user: boom\" error.causes=boom\n"+
+			"\tboom\n\tmain.save\n\t\t/src/main.go:12\n", out.String())
+
+		out.Reset()
+		l.SetFormatter(&JSONFormatter{})
+		defer l.SetFormatter(&TextFormatter{})
+		l.WithError(errors.New("plain")).Error("failed")
+		assert.Contains(t, out.String(), `"msg":"failed","error":"plain"}`)
+	})
+
+	t.Run("chains", func(t *testing.T) {
+		SetErrorChains(true)
+		defer SetErrorChains(false)
+		err := fmt.Errorf("save user: %w", fmt.Errorf("insert: %w", io.ErrUnexpectedEOF))
+
+		out.Reset()
+		l.WithError(err).Error("failed")
+		assert.Equal(t, "ERROR: failed error=\"save user: insert: unexpected EOF\" "+
+			"error.causes=\"insert: unexpected EOF,unexpected EOF\" "+
+			"error.chain=\"*fmt.wrapError: save user: insert: unexpected EOF | "+
+			"*fmt.wrapError: insert: unexpected EOF | *errors.errorString: unexpected EOF\" "+
+			"error.root=\"unexpected EOF\"\n", out.String())
+
+		out.Reset()
+		l.SetFormatter(&JSONFormatter{})
+		defer l.SetFormatter(&TextFormatter{})
+		l.WithError(io.EOF).Error("failed")
+		assert.Contains(t, out.String(), `"error":"EOF",`+
+			`"error.chain":[{"type":"*errors.errorString","message":"EOF"}],"error.root":"EOF"}`)
+	})
+
+	t.Run("package level", func(t *testing.T) {
+		var buf syncBuffer
+		ErrorLogger.SetOutput(&buf)
+		defer ErrorLogger.SetOutput(os.Stdout)
+		WithError(io.EOF).Error("failed")
+		assert.Contains(t, buf.String(), "failed error=EOF\n")
+	})
+
+	t.Run("logrus", func(t *testing.T) {
+		var buf syncBuffer
+		lr := NewLogrusLogger(InfoLevel)
+		lr.SetOutput(&buf)
+		lr.SetFormatter(&TextFormatter{})
+		lr.WithError(io.EOF).Error("failed")
+		assert.Equal(t, "ERROR: failed error=EOF\n", buf.String())
+	})
+}
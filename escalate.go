@@ -0,0 +1,134 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxEscalationKeys is the number of fingerprints tracked by an
+// escalation rule before the inactive ones are forgotten.
+const maxEscalationKeys = 1024
+
+// EscalationRule turns chronic entries into errors: when more than
+// Threshold entries of Level with the same fingerprint are logged
+// within Window, an Error summary entry is emitted with ErrorLogger,
+// notifying its hooks, and the count of the fingerprint restarts.
+type EscalationRule struct {
+	// Level is the level of the counted entries, e.g. WarningLevel.
+	Level Level
+	// Key is the field holding the fingerprint of the entries, e.g.
+	// an event code. When empty, or when the field is missing, the
+	// message template, or the message itself, is used instead.
+	Key string
+	// Threshold is the number of entries tolerated within Window.
+	Threshold int
+	// Window is the duration the entries are counted over.
+	Window time.Duration
+}
+
+// escalation is an EscalationRule and its counts.
+type escalation struct {
+	EscalationRule
+
+	mu     sync.Mutex
+	events map[string][]time.Time
+}
+
+// SetEscalationRules replaces the escalation rules of the package.
+// Calling it without rules removes all of them.
+func SetEscalationRules(rules ...EscalationRule) error {
+	escalations := make([]*escalation, 0, len(rules))
+	for _, r := range rules {
+		if r.Threshold <= 0 || r.Window <= 0 {
+			return errors.New("golog: escalation rule needs a positive threshold and window")
+		}
+		escalations = append(escalations, &escalation{
+			EscalationRule: r,
+			events:         make(map[string][]time.Time),
+		})
+	}
+	updateState(func(s *globalState) {
+		s.escalations = escalations
+	})
+	return nil
+}
+
+// escalate counts e in the rules and emits the summaries
+// of the fingerprints exceeding their threshold.
+func escalate(rules []*escalation, e *Entry) {
+	for _, r := range rules {
+		if r.Level != e.Level {
+			continue
+		}
+		fingerprint := fingerprint(e, r.Key)
+		if n, ok := r.count(fingerprint, e.Time); ok {
+			r.emit(fingerprint, n)
+		}
+	}
+}
+
+// fingerprint returns the value of the field key of e, or its message
+// template, or its message, when key is empty or the field missing.
+func fingerprint(e *Entry, key string) string {
+	if key != "" {
+		if v, ok := e.Fields[key]; ok {
+			return fmt.Sprint(v)
+		}
+	}
+	if e.Template != "" {
+		return e.Template
+	}
+	return e.Message
+}
+
+// count records an entry of fingerprint at t and reports whether
+// the threshold is exceeded, with the number of entries counted.
+func (r *escalation) count(fingerprint string, t time.Time) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.events) >= maxEscalationKeys {
+		r.prune(t)
+	}
+	events := append(r.recent(r.events[fingerprint], t), t)
+	if len(events) > r.Threshold {
+		delete(r.events, fingerprint)
+		return len(events), true
+	}
+	r.events[fingerprint] = events
+	return 0, false
+}
+
+// recent returns the times of events within the window ending at t.
+func (r *escalation) recent(events []time.Time, t time.Time) []time.Time {
+	cutoff := t.Add(-r.Window)
+	i := 0
+	for i < len(events) && !events[i].After(cutoff) {
+		i++
+	}
+	return events[i:]
+}
+
+// prune forgets the fingerprints without recent events.
+func (r *escalation) prune(t time.Time) {
+	for k, events := range r.events {
+		if len(r.recent(events, t)) == 0 {
+			delete(r.events, k)
+		}
+	}
+}
+
+func (r *escalation) emit(fingerprint string, n int) {
+	if !ErrorLogger.isPrint() {
+		return
+	}
+	fields := Fields{
+		"escalation.fingerprint": fingerprint,
+		"escalation.count":       n,
+		"escalation.level":       r.Level.name(),
+		"escalation.window":      r.Window.String(),
+	}
+	ErrorLogger.WithFields(fields).(*stdLogger).Output(0,
+		fmt.Sprintf("escalated: %q logged %d times within %v", fingerprint, n, r.Window))
+}
@@ -0,0 +1,79 @@
+package golog
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetEscalationRules(t *testing.T) {
+	defer func() { now = time.Now }()
+	defer SetEscalationRules()
+	defer ResetHooks()
+	defer ErrorLogger.SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	assert.Error(t, SetEscalationRules(EscalationRule{Level: WarningLevel}))
+	assert.NoError(t, SetEscalationRules(EscalationRule{
+		Level:     WarningLevel,
+		Key:       "code",
+		Threshold: 2,
+		Window:    time.Minute,
+	}))
+	errs := &syncBuffer{}
+	ErrorLogger.SetOutput(errs)
+	var fired []*Entry
+	AddHook(HookFunc(func(e *Entry) error {
+		if e.Level == ErrorLevel {
+			fired = append(fired, e)
+		}
+		return nil
+	}))
+
+	l := newStdLogger(WarningLevel, &syncBuffer{}, 0)
+	disk := l.WithFields(Fields{"code": "DISK_LOW"})
+	disk.Print("disk almost full")
+	disk.Print("disk almost full")
+	l.WithFields(Fields{"code": "OTHER"}).Print("other")
+	assert.Empty(t, fired)
+
+	clock = clock.Add(30 * time.Second)
+	disk.Print("disk almost full")
+	if assert.Len(t, fired, 1) {
+		assert.Equal(t, `escalated: "DISK_LOW" logged 3 times within 1m0s`, fired[0].Message)
+		assert.Equal(t, Fields{
+			"escalation.fingerprint": "DISK_LOW",
+			"escalation.count":       3,
+			"escalation.level":       "warning",
+			"escalation.window":      "1m0s",
+		}, fired[0].Fields)
+	}
+	assert.Contains(t, errs.String(), "ERROR: ")
+
+	t.Run("the count restarts after an escalation", func(t *testing.T) {
+		fired = nil
+		disk.Print("disk almost full")
+		disk.Print("disk almost full")
+		assert.Empty(t, fired)
+	})
+	t.Run("entries outside of the window are not counted", func(t *testing.T) {
+		fired = nil
+		clock = clock.Add(2 * time.Minute)
+		disk.Print("disk almost full")
+		assert.Empty(t, fired)
+	})
+	t.Run("the message is the fingerprint without the key", func(t *testing.T) {
+		fired = nil
+		for i := 0; i < 3; i++ {
+			l.Print("retrying")
+		}
+		if assert.Len(t, fired, 1) {
+			assert.Equal(t, "retrying", fired[0].Fields["escalation.fingerprint"])
+		}
+	})
+}
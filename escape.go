@@ -0,0 +1,150 @@
+package golog
+
+import (
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Escaper is the strategy used by the structured formatters
+// to escape strings.
+type Escaper interface {
+	// AppendJSON appends s to dst as a quoted JSON string.
+	AppendJSON(dst []byte, s string) []byte
+	// AppendLogfmt appends s to dst as a logfmt value, quoting
+	// it only when needed.
+	AppendLogfmt(dst []byte, s string) []byte
+}
+
+var (
+	// ASCIIEscaper is a fast Escaper that only escapes the quote,
+	// backslash and control characters. Bytes outside of the ASCII
+	// range are copied as is, so invalid UTF-8 is not detected.
+	ASCIIEscaper Escaper = asciiEscaper{}
+	// UTF8Escaper is a strict Escaper that also replaces invalid
+	// UTF-8 with the replacement character and escapes the
+	// line and paragraph separators.
+	UTF8Escaper Escaper = utf8Escaper{}
+)
+
+// escaper holds the Escaper in use by the package.
+var escaper atomic.Value
+
+func init() {
+	escaper.Store(escaperHolder{UTF8Escaper})
+}
+
+// escaperHolder keeps the concrete type stored
+// in the atomic.Value the same.
+type escaperHolder struct {
+	Escaper
+}
+
+// SetEscaper sets the escaping strategy used by the formatters.
+// The default is UTF8Escaper.
+func SetEscaper(e Escaper) {
+	if e == nil {
+		e = UTF8Escaper
+	}
+	escaper.Store(escaperHolder{e})
+}
+
+func getEscaper() Escaper {
+	return escaper.Load().(escaperHolder).Escaper
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendEscapedByte appends the JSON escape sequence of b.
+func appendEscapedByte(dst []byte, b byte) []byte {
+	switch b {
+	case '"', '\\':
+		return append(dst, '\\', b)
+	case '\n':
+		return append(dst, '\\', 'n')
+	case '\r':
+		return append(dst, '\\', 'r')
+	case '\t':
+		return append(dst, '\\', 't')
+	}
+	return append(dst, '\\', 'u', '0', '0', hexDigits[b>>4], hexDigits[b&0xF])
+}
+
+func needsEscape(b byte) bool {
+	return b < 0x20 || b == '"' || b == '\\'
+}
+
+type asciiEscaper struct{}
+
+func (asciiEscaper) AppendJSON(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if !needsEscape(s[i]) {
+			continue
+		}
+		dst = append(dst, s[start:i]...)
+		dst = appendEscapedByte(dst, s[i])
+		start = i + 1
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+func (e asciiEscaper) AppendLogfmt(dst []byte, s string) []byte {
+	if s == "" {
+		return append(dst, '"', '"')
+	}
+	for i := 0; i < len(s); i++ {
+		if b := s[i]; b <= ' ' || b == '=' || b == '"' || b == '\\' || b == 0x7f {
+			return e.AppendJSON(dst, s)
+		}
+	}
+	return append(dst, s...)
+}
+
+type utf8Escaper struct{}
+
+func (utf8Escaper) AppendJSON(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if needsEscape(b) {
+				dst = append(dst, s[start:i]...)
+				dst = appendEscapedByte(dst, b)
+				start = i + 1
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, `\ufffd`...)
+		case r == '\u2028' || r == '\u2029':
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+func (e utf8Escaper) AppendLogfmt(dst []byte, s string) []byte {
+	if s == "" {
+		return append(dst, '"', '"')
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return e.AppendJSON(dst, s)
+		}
+	}
+	return append(dst, s...)
+}
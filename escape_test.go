@@ -0,0 +1,87 @@
+package golog
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscaper_AppendJSON(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		ascii string
+		utf8  string
+	}{
+		{name: "plain", input: "Hello World", ascii: `"Hello World"`, utf8: `"Hello World"`},
+		{name: "quote and backslash", input: `say "hi" \o/`, ascii: `"say \"hi\" \\o/"`, utf8: `"say \"hi\" \\o/"`},
+		{name: "control characters", input: "a\nb\tc\x01", ascii: `"a\nb\tc\u0001"`, utf8: `"a\nb\tc\u0001"`},
+		{name: "valid unicode", input: "héllo 世界", ascii: `"héllo 世界"`, utf8: `"héllo 世界"`},
+		{name: "invalid utf-8", input: "a\xffb", ascii: "\"a\xffb\"", utf8: `"a\ufffdb"`},
+		{name: "line separator", input: "a\u2028b", ascii: "\"a\u2028b\"", utf8: `"a\u2028b"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.ascii, string(ASCIIEscaper.AppendJSON(nil, c.input)))
+			got := UTF8Escaper.AppendJSON(nil, c.input)
+			assert.Equal(t, c.utf8, string(got))
+			assert.True(t, json.Valid(got))
+		})
+	}
+}
+
+func TestEscaper_AppendLogfmt(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "bare value", input: "hello", want: `hello`},
+		{name: "empty value", input: "", want: `""`},
+		{name: "value with space", input: "hello world", want: `"hello world"`},
+		{name: "value with equal sign", input: "a=b", want: `"a=b"`},
+		{name: "value with newline", input: "a\nb", want: `"a\nb"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, string(ASCIIEscaper.AppendLogfmt(nil, c.input)))
+			assert.Equal(t, c.want, string(UTF8Escaper.AppendLogfmt(nil, c.input)))
+		})
+	}
+	t.Run("strict mode quotes invalid utf-8", func(t *testing.T) {
+		assert.Equal(t, `"a\ufffd"`, string(UTF8Escaper.AppendLogfmt(nil, "a\xff")))
+		assert.Equal(t, "a\xff", string(ASCIIEscaper.AppendLogfmt(nil, "a\xff")))
+	})
+}
+
+func TestSetEscaper(t *testing.T) {
+	defer SetEscaper(nil)
+	SetEscaper(ASCIIEscaper)
+	assert.Equal(t, ASCIIEscaper, getEscaper())
+	SetEscaper(nil)
+	assert.Equal(t, UTF8Escaper, getEscaper())
+}
+
+var escapeBenchInputs = map[string]string{
+	"short": "user logged in",
+	"long":  strings.Repeat("the quick brown fox jumps over the lazy dog ", 50),
+	"mixed": strings.Repeat("héllo \"wörld\"\t", 100),
+}
+
+func benchmarkEscaper(b *testing.B, e Escaper) {
+	for name, input := range escapeBenchInputs {
+		b.Run(name, func(b *testing.B) {
+			buf := make([]byte, 0, 4096)
+			b.SetBytes(int64(len(input)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf = e.AppendJSON(buf[:0], input)
+			}
+		})
+	}
+}
+
+func BenchmarkASCIIEscaper(b *testing.B) { benchmarkEscaper(b, ASCIIEscaper) }
+func BenchmarkUTF8Escaper(b *testing.B)  { benchmarkEscaper(b, UTF8Escaper) }
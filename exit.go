@@ -0,0 +1,96 @@
+package golog
+
+import (
+	"io"
+	"os"
+)
+
+// SetExitFunc sets the function called by Fatal and Fatalf to
+// terminate the program, so tests can intercept it. A nil fn
+// restores os.Exit. The pauses are resumed and the sinks flushed,
+// as by Sync, before it is called. The exit function of a logger
+// set with its SetExitFunc method takes precedence.
+func SetExitFunc(fn func(code int)) {
+	updateState(func(s *globalState) {
+		s.exit = fn
+	})
+}
+
+// OnFatal registers fn to be called after a fatal entry is written
+// and before the program exits, e.g. to flush buffers or to shut
+// down gracefully. The functions are called in registration order.
+func OnFatal(fn func()) {
+	updateState(func(s *globalState) {
+		onFatal := make([]func(), len(s.onFatal), len(s.onFatal)+1)
+		copy(onFatal, s.onFatal)
+		s.onFatal = append(onFatal, fn)
+	})
+}
+
+// ResetOnFatal removes all the functions registered with OnFatal.
+func ResetOnFatal() {
+	updateState(func(s *globalState) {
+		s.onFatal = nil
+	})
+}
+
+// fatalExit writes the entries held by the pauses, runs the OnFatal
+// functions, flushes the sinks, w, the output of the logger, included,
+// so the fatal entry is not lost, and terminates the program with fn,
+// the global exit function or os.Exit.
+func fatalExit(fn func(int), w io.Writer) {
+	st := getState()
+	resumeAll()
+	for _, f := range st.onFatal {
+		f()
+	}
+	err := Sync()
+	if f, ok := w.(flusher); ok {
+		if ferr := f.Flush(); err == nil {
+			err = ferr
+		}
+	}
+	if err != nil {
+		reportf("golog: fatal: %v", err)
+	}
+	if fn == nil {
+		fn = st.exit
+	}
+	if fn == nil {
+		fn = os.Exit
+	}
+	fn(1)
+}
+
+// SetExitFunc sets the function called by Fatal and Fatalf to
+// terminate the program, overriding the global one for l and the
+// loggers derived with WithFields. A nil fn restores the default.
+func (l *stdLogger) SetExitFunc(fn func(code int)) {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.exit = fn
+}
+
+func (l *stdLogger) exit() {
+	l.out.mu.Lock()
+	fn, w := l.out.exit, l.out.w
+	l.out.mu.Unlock()
+	fatalExit(fn, w)
+}
+
+// SetExitFunc sets the function called by Fatal and Fatalf to
+// terminate the program, overriding the global one for l and the
+// loggers derived with WithFields. A nil fn restores the default.
+func (l *Logrus) SetExitFunc(fn func(code int)) {
+	l.cfg.exitMu.Lock()
+	defer l.cfg.exitMu.Unlock()
+	l.cfg.exitFunc = fn
+}
+
+func (l *Logrus) exit() {
+	l.cfg.exitMu.Lock()
+	fn := l.cfg.exitFunc
+	l.cfg.exitMu.Unlock()
+	l.Resume()
+	fatalExit(fn, l.Out())
+}
@@ -0,0 +1,83 @@
+package golog
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetExitFunc(t *testing.T) {
+	defer SetExitFunc(nil)
+	defer ResetOnFatal()
+	defer ErrorLogger.SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	ErrorLogger.SetOutput(ioutil.Discard)
+
+	var calls []string
+	SetExitFunc(func(code int) { calls = append(calls, "exit") })
+	OnFatal(func() { calls = append(calls, "flush") })
+	OnFatal(func() { calls = append(calls, "shutdown") })
+
+	Fatal("boom")
+	assert.Equal(t, []string{"flush", "shutdown", "exit"}, calls)
+
+	t.Run("per logger exit function", func(t *testing.T) {
+		calls = nil
+		var code int
+		l := newStdLogger(ErrorLevel, ioutil.Discard, 0)
+		l.SetExitFunc(func(c int) { code = c })
+		l.WithFields(Fields{"k": "v"}).Fatalf("boom %d", 1)
+		assert.Equal(t, 1, code)
+		assert.Equal(t, []string{"flush", "shutdown"}, calls)
+	})
+	t.Run("logrus", func(t *testing.T) {
+		calls = nil
+		l := NewLogrusLogger(ErrorLevel)
+		l.SetOutput(ioutil.Discard)
+		l.Fatal("boom")
+		l.SetExitFunc(func(int) { calls = append(calls, "logrus exit") })
+		l.Fatalf("boom %d", 2)
+		assert.Equal(t, []string{"flush", "shutdown", "exit", "flush", "shutdown", "logrus exit"}, calls)
+	})
+	t.Run("flushes the sinks", func(t *testing.T) {
+		ResetOnFatal()
+		defer SetFormatter(&TextFormatter{})
+		SetFormatter(&TextFormatter{})
+		for name, newSink := range map[string]func(w io.Writer) io.Writer{
+			"AsyncWriter":    func(w io.Writer) io.Writer { return NewAsyncWriter(w, 0, Block) },
+			"BufferedWriter": func(w io.Writer) io.Writer { return NewBufferedWriter(w, 0, time.Hour) },
+			"paused": func(w io.Writer) io.Writer {
+				Pause()
+				return w
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				out := &syncBuffer{}
+				sink := newSink(out)
+				if c, ok := sink.(io.Closer); ok {
+					defer c.Close()
+				}
+				var got string
+				SetExitFunc(func(int) { got = out.String() })
+				ErrorLogger.SetOutput(sink)
+				Fatal("boom")
+				assert.Equal(t, "ERROR: boom\n", got)
+
+				got = ""
+				l := NewLogrusLogger(ErrorLevel)
+				l.SetFormatter(&TextFormatter{})
+				l.SetOutput(sink)
+				l.Pause()
+				l.SetExitFunc(func(int) { got = out.String() })
+				out.Reset()
+				l.Fatal("boom")
+				assert.Equal(t, "ERROR: boom\n", got)
+			})
+		}
+	})
+}
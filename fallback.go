@@ -0,0 +1,59 @@
+package golog
+
+import (
+	"fmt"
+	"time"
+)
+
+// FormatErrorKey is the field key of the error of the formatter in the
+// fallback entries, written in plain text when a formatter fails.
+const FormatErrorKey = "format_error"
+
+// formatEntry formats e with f into a pooled buffer, see format. When
+// f fails or panics, e.g. in the MarshalJSON method of a field value,
+// the failure is reported to the diagnostic handler and e is formatted
+// by fallbackFormat instead, so neither the entry nor the caller are
+// lost.
+func formatEntry(f Formatter, e *Entry) ([]byte, *[]byte) {
+	b, buf, err := recoverFormat(f, e, true)
+	if err != nil {
+		reportf("golog: failed to format entry: %v", err)
+		return fallbackFormat(e, err), nil
+	}
+	return b, buf
+}
+
+// safeFormat formats e with f, turning a panic of f into an error.
+func safeFormat(f Formatter, e *Entry) ([]byte, error) {
+	b, _, err := recoverFormat(f, e, false)
+	return b, err
+}
+
+// recoverFormat formats e with f, into a pooled buffer when pooled is
+// set, and recovers from a panic of f.
+func recoverFormat(f Formatter, e *Entry, pooled bool) (b []byte, buf *[]byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			b, buf, err = nil, nil, fmt.Errorf("formatter panicked: %v", r)
+		}
+	}()
+	if pooled {
+		return format(f, e)
+	}
+	b, err = f.Format(e)
+	return b, nil, err
+}
+
+// fallbackFormat formats e as a plain text line holding its level,
+// time and message along with err as the FormatErrorKey field. The
+// fields of e are left out, as they may be what made the formatter
+// fail.
+func fallbackFormat(e *Entry, err error) []byte {
+	b := make([]byte, 0, 64+len(e.Message))
+	b = append(b, e.Level.prefix()...)
+	b = appendTime(b, e.Time, time.RFC3339)
+	b = append(b, ' ')
+	b = append(b, e.Message...)
+	b = appendTextField(b, FormatErrorKey, err.Error())
+	return append(b, '\n')
+}
@@ -0,0 +1,64 @@
+package golog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panickingValue struct{}
+
+func (panickingValue) MarshalJSON() ([]byte, error) {
+	panic("boom")
+}
+
+func TestFormatEntry_Fallback(t *testing.T) {
+	defer SetDiagnosticHandler(nil)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var errs []string
+	SetDiagnosticHandler(func(err error) { errs = append(errs, err.Error()) })
+
+	t.Run("panic", func(t *testing.T) {
+		errs = nil
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0)
+		l.SetClock(ClockFunc(func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }))
+		l.SetFormatter(&JSONFormatter{})
+		require.NotPanics(t, func() {
+			l.WithFields(Fields{"v": panickingValue{}}).Error("failed")
+		})
+		l.Info("next")
+		lines := out.String()
+		assert.Regexp(t, `^ERROR: 2020-01-02T03:04:05Z failed format_error="formatter panicked: boom"\n\{.*"msg":"next"\}\n$`, lines)
+		assert.Equal(t, []string{"golog: failed to format entry: formatter panicked: boom"}, errs)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		errs = nil
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0)
+		l.SetFormatter(failingFormatter{})
+		l.Warn("kept")
+		assert.Regexp(t, `^WARNING: \S+ kept format_error="unsupported value"\n$`, out.String())
+	})
+
+	t.Run("logrus", func(t *testing.T) {
+		var out syncBuffer
+		l := NewLogrusLogger(InfoLevel)
+		l.SetOutput(&out)
+		l.SetFormatter(&JSONFormatter{})
+		require.NotPanics(t, func() {
+			l.WithFields(Fields{"v": panickingValue{}}).Info("failed")
+		})
+		assert.Regexp(t, `^INFO: \S+ failed format_error="formatter panicked: boom"\n$`, out.String())
+	})
+
+	t.Run("hooks report the panic", func(t *testing.T) {
+		h := &WriterHook{Writer: &syncBuffer{}, Formatter: &JSONFormatter{}}
+		err := h.Fire(&Entry{Message: "failed", Fields: Fields{"v": panickingValue{}}})
+		assert.EqualError(t, err, "formatter panicked: boom")
+	})
+}
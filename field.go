@@ -0,0 +1,226 @@
+package golog
+
+import "time"
+
+// fieldKind is the type of the value of a Field.
+type fieldKind uint8
+
+const (
+	skipKind fieldKind = iota
+	stringKind
+	intKind
+	int64Kind
+	boolKind
+	durationKind
+	timeKind
+	anyKind
+)
+
+// Field is a typed key/value pair attached to a single entry by the
+// Debugw, Infow, Warnw, Errorw and Tracew functions. The scalar values
+// are held without boxing, so the fields of a disabled entry cost no
+// allocation:
+//
+//	golog.Infow("user logged in", golog.String("user", name), golog.Int("attempts", n))
+type Field struct {
+	Key     string
+	kind    fieldKind
+	integer int64
+	str     string
+	iface   interface{}
+}
+
+// String returns a Field holding the string v.
+func String(key, v string) Field {
+	return Field{Key: key, kind: stringKind, str: v}
+}
+
+// Int returns a Field holding the int v.
+func Int(key string, v int) Field {
+	return Field{Key: key, kind: intKind, integer: int64(v)}
+}
+
+// Int64 returns a Field holding the int64 v.
+func Int64(key string, v int64) Field {
+	return Field{Key: key, kind: int64Kind, integer: v}
+}
+
+// Bool returns a Field holding the bool v.
+func Bool(key string, v bool) Field {
+	var i int64
+	if v {
+		i = 1
+	}
+	return Field{Key: key, kind: boolKind, integer: i}
+}
+
+// Duration returns a Field holding the time.Duration v.
+func Duration(key string, v time.Duration) Field {
+	return Field{Key: key, kind: durationKind, integer: int64(v)}
+}
+
+// Time returns a Field holding the time.Time v.
+func Time(key string, v time.Time) Field {
+	if y := v.Year(); y <= 1678 || y >= 2262 {
+		// Out of the range of UnixNano.
+		return Field{Key: key, kind: anyKind, iface: v}
+	}
+	return Field{Key: key, kind: timeKind, integer: v.UnixNano(), iface: v.Location()}
+}
+
+// Err returns a Field holding err as the ErrorKey field.
+// A nil err adds no field.
+func Err(err error) Field {
+	if err == nil {
+		return Field{kind: skipKind}
+	}
+	return Field{Key: ErrorKey, kind: anyKind, iface: err}
+}
+
+// Any returns a Field holding v.
+func Any(key string, v interface{}) Field {
+	return Field{Key: key, kind: anyKind, iface: v}
+}
+
+// Value returns the value of f.
+func (f Field) Value() interface{} {
+	switch f.kind {
+	case stringKind:
+		return f.str
+	case intKind:
+		return int(f.integer)
+	case int64Kind:
+		return f.integer
+	case boolKind:
+		return f.integer == 1
+	case durationKind:
+		return time.Duration(f.integer)
+	case timeKind:
+		return time.Unix(0, f.integer).In(f.iface.(*time.Location))
+	case anyKind:
+		return f.iface
+	}
+	return nil
+}
+
+// fieldsOf returns the fields as Fields, or nil when there
+// is none. The later fields override the earlier ones.
+func fieldsOf(fields []Field) Fields {
+	if len(fields) == 0 {
+		return nil
+	}
+	m := make(Fields, len(fields))
+	for _, f := range fields {
+		if f.kind != skipKind {
+			m[f.Key] = f.Value()
+		}
+	}
+	return m
+}
+
+// Debugw logs msg with fields at DebugLevel.
+func Debugw(msg string, fields ...Field) {
+	DebugLogger.writeFields(stdCallDepth, DebugLevel, msg, fields)
+}
+
+// Tracew logs msg with fields at TraceLevel.
+func Tracew(msg string, fields ...Field) {
+	TraceLogger.writeFields(stdCallDepth, TraceLevel, msg, fields)
+}
+
+// Infow logs msg with fields at InfoLevel.
+func Infow(msg string, fields ...Field) {
+	InfoLogger.writeFields(stdCallDepth, InfoLevel, msg, fields)
+}
+
+// Warnw logs msg with fields at WarningLevel.
+func Warnw(msg string, fields ...Field) {
+	WarningLogger.writeFields(stdCallDepth, WarningLevel, msg, fields)
+}
+
+// Errorw logs msg with fields at ErrorLevel.
+func Errorw(msg string, fields ...Field) {
+	ErrorLogger.writeFields(stdCallDepth, ErrorLevel, msg, fields)
+}
+
+// Debugw logs msg with fields at DebugLevel.
+func (l *stdLogger) Debugw(msg string, fields ...Field) {
+	l.writeFields(stdCallDepth, DebugLevel, msg, fields)
+}
+
+// Tracew logs msg with fields at TraceLevel.
+func (l *stdLogger) Tracew(msg string, fields ...Field) {
+	l.writeFields(stdCallDepth, TraceLevel, msg, fields)
+}
+
+// Infow logs msg with fields at InfoLevel.
+func (l *stdLogger) Infow(msg string, fields ...Field) {
+	l.writeFields(stdCallDepth, InfoLevel, msg, fields)
+}
+
+// Warnw logs msg with fields at WarningLevel.
+func (l *stdLogger) Warnw(msg string, fields ...Field) {
+	l.writeFields(stdCallDepth, WarningLevel, msg, fields)
+}
+
+// Errorw logs msg with fields at ErrorLevel.
+func (l *stdLogger) Errorw(msg string, fields ...Field) {
+	l.writeFields(stdCallDepth, ErrorLevel, msg, fields)
+}
+
+// writeFields writes the entry msg at lvl with the fields of the call,
+// unless the entries of l at lvl with them are not printed. The fields
+// are only converted to check the levels depending on them when the
+// entries of lvl are disabled otherwise.
+func (l *stdLogger) writeFields(calldepth int, lvl Level, msg string, fields []Field) {
+	if l.isPrintLevel(lvl) || bundlesFields(lvl, fields) {
+		l.write(calldepth, lvl, msg, "", writeDefault, fieldsOf(fields))
+		return
+	}
+	if !getState().gatesOnFields() {
+		return
+	}
+	if call := fieldsOf(fields); l.isPrintCall(lvl, call) {
+		l.write(calldepth, lvl, msg, "", writeDefault, call)
+	}
+}
+
+// Debugw logs msg with fields at DebugLevel.
+func (l *Logrus) Debugw(msg string, fields ...Field) {
+	l.logFields(DebugLevel, msg, fields)
+}
+
+// Tracew logs msg with fields at TraceLevel.
+func (l *Logrus) Tracew(msg string, fields ...Field) {
+	l.logFields(TraceLevel, msg, fields)
+}
+
+// Infow logs msg with fields at InfoLevel.
+func (l *Logrus) Infow(msg string, fields ...Field) {
+	l.logFields(InfoLevel, msg, fields)
+}
+
+// Warnw logs msg with fields at WarningLevel.
+func (l *Logrus) Warnw(msg string, fields ...Field) {
+	l.logFields(WarningLevel, msg, fields)
+}
+
+// Errorw logs msg with fields at ErrorLevel.
+func (l *Logrus) Errorw(msg string, fields ...Field) {
+	l.logFields(ErrorLevel, msg, fields)
+}
+
+// logFields logs msg at lvl with the fields of the call, unless the
+// entries of l at lvl with them are disabled.
+func (l *Logrus) logFields(lvl Level, msg string, fields []Field) {
+	if l.isEnabledLevel(lvl) || bundlesFields(lvl, fields) {
+		l.log(2, lvl, msg, "", false, fieldsOf(fields))
+		return
+	}
+	if !getState().gatesOnFields() {
+		return
+	}
+	if call := fieldsOf(fields); l.isEnabledCall(lvl, call) {
+		l.log(2, lvl, msg, "", false, call)
+	}
+}
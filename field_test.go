@@ -0,0 +1,107 @@
+package golog
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestField_Value(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6, time.FixedZone("CET", 3600))
+	far := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+	err := errors.New("boom")
+	tests := []struct {
+		field Field
+		key   string
+		want  interface{}
+	}{
+		{String("s", "v"), "s", "v"},
+		{Int("i", -3), "i", -3},
+		{Int64("i64", 1<<40), "i64", int64(1 << 40)},
+		{Bool("t", true), "t", true},
+		{Bool("f", false), "f", false},
+		{Duration("d", 1500*time.Millisecond), "d", 1500 * time.Millisecond},
+		{Time("ts", ts), "ts", ts},
+		{Time("far", far), "far", far},
+		{Err(err), ErrorKey, err},
+		{Any("a", []int{1}), "a", []int{1}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.key, tt.field.Key)
+		assert.Equal(t, tt.want, tt.field.Value(), tt.key)
+	}
+	assert.Nil(t, fieldsOf(nil))
+	assert.Equal(t, Fields{"a": 2}, fieldsOf([]Field{Int("a", 1), Err(nil), Int("a", 2)}))
+}
+
+func TestInfow(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+
+	t.Run("logger", func(t *testing.T) {
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0).WithFields(Fields{"user": "alice", "app": "api"})
+		l.Infow("logged in", String("user", "bob"), Int("attempts", 2), Duration("took", time.Second))
+		l.Debugw("hidden", Bool("debug", true))
+		l.Errorw("failed", Err(errors.New("boom")))
+		assert.Equal(t, "INFO: logged in app=api attempts=2 took=1s user=bob\n"+
+			"ERROR: failed app=api error=boom user=alice\n", out.String())
+	})
+
+	t.Run("package level", func(t *testing.T) {
+		var out syncBuffer
+		WarningLogger.SetOutput(&out)
+		defer WarningLogger.SetOutput(os.Stdout)
+		WarningLogger.SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+		defer WarningLogger.SetFormatter(&TextFormatter{Flags: defaultFlags(WarningLevel)})
+		Warnw("slow", Int("ms", 1200))
+		assert.Regexp(t, `^WARNING: field_test\.go:\d+: slow ms=1200\n$`, out.String())
+	})
+
+	t.Run("logrus", func(t *testing.T) {
+		var out syncBuffer
+		l := NewLogrusLogger(InfoLevel)
+		l.SetOutput(&out)
+		l.SetFormatter(&TextFormatter{})
+		l.WithFields(Fields{"user": "alice"}).Infow("logged in", String("user", "bob"))
+		l.Tracew("hidden")
+		assert.Equal(t, "INFO: logged in user=bob\n", out.String())
+	})
+
+	t.Run("verbose captures", func(t *testing.T) {
+		defer CaptureVerbose("user_id", 123, time.Minute)()
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0)
+		l.Debugw("captured", Int("user_id", 123))
+		l.Debugw("other user", Int("user_id", 456))
+		lr := NewLogrusLogger(InfoLevel)
+		lr.SetOutput(&out)
+		lr.SetFormatter(&TextFormatter{})
+		lr.Debugw("logrus", Int("user_id", 123))
+		assert.Equal(t, "DEBUG: captured user_id=123\nDEBUG: logrus user_id=123\n", out.String())
+	})
+
+	t.Run("no allocation when disabled", func(t *testing.T) {
+		err := errors.New("boom")
+		allocs := testing.AllocsPerRun(100, func() {
+			Debugw("hidden", String("user", "alice"), Int("attempts", 2), Bool("ok", true),
+				Duration("took", time.Second), Err(err))
+		})
+		assert.Zero(t, allocs)
+	})
+}
+
+func BenchmarkInfow(b *testing.B) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	l := newStdLogger(InfoLevel, ioutil.Discard, 0)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Infow("hello", String("user", "alice"), Int("attempts", i))
+	}
+}
@@ -0,0 +1,117 @@
+package golog
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Fields is a set of key/value pairs attached to log entries.
+//
+// Besides the scalar values, []string, []int, map[string]string and
+// nested Fields values are encoded natively: as arrays and objects in
+// JSON output, and as comma-joined values and dotted keys in text output.
+type Fields map[string]interface{}
+
+// String returns the fields in text form, sorted by key,
+// e.g. `tags=a,b user.id=42`.
+func (f Fields) String() string {
+	return string(appendTextFields(nil, f))
+}
+
+// copyFields returns a shallow copy of f.
+func copyFields(f Fields) Fields {
+	c := make(Fields, len(f))
+	for k, v := range f {
+		c[k] = v
+	}
+	return c
+}
+
+// sortedKeys returns the keys of f in lexical order.
+func (f Fields) sortedKeys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendTextFields appends f to dst as space separated
+// key=value pairs.
+func appendTextFields(dst []byte, f Fields) []byte {
+	return appendTextFieldsPrefix(dst, "", f)
+}
+
+func appendTextFieldsPrefix(dst []byte, prefix string, f Fields) []byte {
+	for _, k := range f.sortedKeys() {
+		dst = appendTextField(dst, prefix+k, f[k])
+	}
+	return dst
+}
+
+// appendTextField appends a key=value pair to dst. Maps are
+// flattened into dotted keys.
+func appendTextField(dst []byte, key string, v interface{}) []byte {
+	switch v := v.(type) {
+	case Fields:
+		return appendTextFieldsPrefix(dst, key+".", v)
+	case map[string]interface{}:
+		return appendTextFieldsPrefix(dst, key+".", Fields(v))
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			dst = appendTextField(dst, key+"."+k, v[k])
+		}
+		return dst
+	}
+	if len(dst) > 0 {
+		dst = append(dst, ' ')
+	}
+	dst = append(dst, key...)
+	dst = append(dst, '=')
+	return appendTextValue(dst, v)
+}
+
+// appendTextValue appends the text form of a field value to dst.
+// Slices are comma-joined.
+func appendTextValue(dst []byte, v interface{}) []byte {
+	esc := getEscaper()
+	switch v := v.(type) {
+	case string:
+		return esc.AppendLogfmt(dst, v)
+	case []string:
+		var joined []byte
+		for i, s := range v {
+			if i > 0 {
+				joined = append(joined, ',')
+			}
+			joined = append(joined, s...)
+		}
+		return esc.AppendLogfmt(dst, string(joined))
+	case []int:
+		for i, n := range v {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = strconv.AppendInt(dst, int64(n), 10)
+		}
+		return dst
+	case int:
+		return strconv.AppendInt(dst, int64(v), 10)
+	case int64:
+		return strconv.AppendInt(dst, v, 10)
+	case bool:
+		return strconv.AppendBool(dst, v)
+	case error:
+		return esc.AppendLogfmt(dst, v.Error())
+	case fmt.Stringer:
+		return esc.AppendLogfmt(dst, v.String())
+	}
+	return esc.AppendLogfmt(dst, fmt.Sprint(v))
+}
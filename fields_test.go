@@ -0,0 +1,43 @@
+package golog
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFields_String(t *testing.T) {
+	cases := []struct {
+		name   string
+		fields Fields
+		want   string
+	}{
+		{name: "scalars are sorted by key", fields: Fields{"b": 2, "a": "x", "c": true}, want: "a=x b=2 c=true"},
+		{name: "strings are quoted when needed", fields: Fields{"msg": "hello world"}, want: `msg="hello world"`},
+		{name: "string slices are comma-joined", fields: Fields{"tags": []string{"a", "b"}}, want: "tags=a,b"},
+		{name: "int slices are comma-joined", fields: Fields{"ids": []int{1, 2, 3}}, want: "ids=1,2,3"},
+		{name: "string maps use dotted keys", fields: Fields{"labels": map[string]string{"z": "1", "a": "2"}}, want: "labels.a=2 labels.z=1"},
+		{name: "nested fields use dotted keys", fields: Fields{"user": Fields{"id": 42, "name": "jay"}}, want: "user.id=42 user.name=jay"},
+		{name: "errors use their message", fields: Fields{"error": errors.New("boom")}, want: "error=boom"},
+		{name: "empty fields", fields: Fields{}, want: ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, c.fields.String())
+		})
+	}
+}
+
+func TestFields_JSON(t *testing.T) {
+	fields := Fields{
+		"tags":   []string{"a", "b"},
+		"ids":    []int{1, 2},
+		"labels": map[string]string{"env": "prod"},
+		"user":   Fields{"id": 42},
+	}
+	got, err := json.Marshal(fields)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"tags":["a","b"],"ids":[1,2],"labels":{"env":"prod"},"user":{"id":42}}`, string(got))
+}
@@ -0,0 +1,97 @@
+package golog
+
+import (
+	"fmt"
+	"io"
+)
+
+// Forced logs must-deliver entries with the package level loggers,
+// for the rare critical business events that must never be
+// suppressed. See Force.
+type Forced struct{}
+
+// Force returns a Forced whose entries are neither muted, sampled
+// nor discarded by the backpressure of an AsyncWriter. They are
+// still dropped when their level is disabled:
+//
+//	golog.Force().Warnf("payment %s refunded by %s", id, operator)
+//
+// The adapters mark the entries passed to Emit with Entry.Forced.
+func Force() Forced {
+	return Forced{}
+}
+
+// logForced logs the message returned by msg with the package level
+// logger of lvl. It must be called directly by the methods of Forced.
+func (Forced) logForced(lvl Level, format string, msg func() string) {
+	l := packageLogger(lvl)
+	if l == nil || !l.isPrint() {
+		return
+	}
+	l.write(stdCallDepth, lvl, msg(), format, writeForced, nil)
+}
+
+// Debug logs with DebugLogger.
+func (f Forced) Debug(v ...interface{}) {
+	f.logForced(DebugLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+
+// Debugf logs with DebugLogger.
+func (f Forced) Debugf(format string, v ...interface{}) {
+	f.logForced(DebugLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+
+// Trace logs with TraceLogger.
+func (f Forced) Trace(v ...interface{}) {
+	f.logForced(TraceLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+
+// Tracef logs with TraceLogger.
+func (f Forced) Tracef(format string, v ...interface{}) {
+	f.logForced(TraceLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+
+// Info logs with InfoLogger.
+func (f Forced) Info(v ...interface{}) {
+	f.logForced(InfoLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+
+// Infof logs with InfoLogger.
+func (f Forced) Infof(format string, v ...interface{}) {
+	f.logForced(InfoLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+
+// Warn logs with WarningLogger.
+func (f Forced) Warn(v ...interface{}) {
+	f.logForced(WarningLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+
+// Warnf logs with WarningLogger.
+func (f Forced) Warnf(format string, v ...interface{}) {
+	f.logForced(WarningLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+
+// Error logs with ErrorLogger.
+func (f Forced) Error(v ...interface{}) {
+	f.logForced(ErrorLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+
+// Errorf logs with ErrorLogger.
+func (f Forced) Errorf(format string, v ...interface{}) {
+	f.logForced(ErrorLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+
+// forcedWriter is implemented by the sinks which may discard
+// entries, to write the forced ones anyway.
+type forcedWriter interface {
+	writeForced(lvl Level, p []byte) (int, error)
+}
+
+// writeEntry writes the formatted entry p of e to w, with
+// writeForced when e is forced and w is a forcedWriter.
+func writeEntry(w io.Writer, e *Entry, p []byte) (int, error) {
+	if fw, ok := w.(forcedWriter); ok && e.Forced {
+		return fw.writeForced(e.Level, p)
+	}
+	return writeLevel(w, e.Level, p)
+}
@@ -0,0 +1,106 @@
+package golog
+
+import (
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dropErrors is a Sampler discarding the errors.
+type dropErrors struct{}
+
+func (dropErrors) Sample(e *Entry) bool { return e.Level != ErrorLevel }
+
+func TestForce(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer func() {
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+
+	AddSampler(dropErrors{})
+	defer ResetSamplers()
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC) }
+	require.NoError(t, SetMuteRules(MuteRule{Level: WarningLevel, Match: "payment", Start: "00:00", End: "23:59", Location: time.UTC}))
+	defer SetMuteRules()
+
+	Warn("payment 42 refunded")
+	Error("payment 43 refunded")
+	Force().Warnf("payment %d refunded", 44)
+	Force().Error("payment 45 refunded")
+	Force().Debug("payment 46 refunded")
+	assert.Equal(t, "WARNING: force_test.go:40: payment 44 refunded\n"+
+		"ERROR: force_test.go:41: payment 45 refunded\n", out.String())
+
+	t.Run("Emit", func(t *testing.T) {
+		out.Reset()
+		SetFormatter(&TextFormatter{})
+		Emit(&Entry{Level: InfoLevel, Message: "payment 47 refunded"})
+		Emit(&Entry{Level: InfoLevel, Message: "payment 48 refunded", Forced: true})
+		assert.Equal(t, "INFO: payment 48 refunded\n", out.String())
+	})
+
+	t.Run("AsyncWriter", func(t *testing.T) {
+		gw := newGateWriter()
+		w := NewAsyncWriter(gw, 1, DropNewest)
+		defer w.Close()
+		SetOutput(w)
+		ResetSamplers()
+		SetMuteRules()
+
+		Info("1")
+		<-gw.started
+		Info("2")
+		Info("3")
+		written := make(chan struct{})
+		go func() {
+			Force().Info("4")
+			close(written)
+		}()
+		select {
+		case <-written:
+			t.Fatal("forced entry did not wait for room")
+		case <-time.After(20 * time.Millisecond):
+		}
+		close(gw.gate)
+		<-written
+		require.NoError(t, w.Flush())
+		assert.Equal(t, "INFO: 1\nINFO: 2\nINFO: 4\n", gw.out.String())
+	})
+
+	t.Run("AsyncWriter DropOldest", func(t *testing.T) {
+		gw := newGateWriter()
+		w := NewAsyncWriter(gw, 1, DropOldest)
+		defer w.Close()
+		SetOutput(w)
+
+		Info("1")
+		<-gw.started
+		Force().Info("2")
+		written := make(chan struct{})
+		go func() {
+			Error("3")
+			close(written)
+		}()
+		select {
+		case <-written:
+			t.Error("the forced entry was discarded")
+		case <-time.After(20 * time.Millisecond):
+		}
+		close(gw.gate)
+		<-written
+		require.NoError(t, w.Flush())
+		assert.Equal(t, "INFO: 1\nINFO: 2\nERROR: 3\n", gw.out.String())
+	})
+}
@@ -0,0 +1,249 @@
+package golog
+
+import (
+	"encoding/json"
+	"log"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// Formatter turns an entry into the bytes that are
+// written to the output of a logger.
+type Formatter interface {
+	Format(e *Entry) ([]byte, error)
+}
+
+var (
+	_ Formatter = (*TextFormatter)(nil)
+	_ Formatter = (*JSONFormatter)(nil)
+	_ Formatter = (*ConsoleFormatter)(nil)
+	_ Formatter = (*SyslogFormatter)(nil)
+	_ Formatter = (*TemplateFormatter)(nil)
+)
+
+// TextFormatter formats an entry as a line like the standard log
+// package does: the level prefix, the header selected by Flags,
+// the message and the fields.
+type TextFormatter struct {
+	// Flags are the flags of the standard log package, e.g.
+	// log.LstdFlags|log.Lshortfile.
+	Flags int
+	// Caller selects how the call site is reported. When set, it
+	// replaces the log.Lshortfile and log.Llongfile flags.
+	Caller CallerFormat
+}
+
+// Format implements the Formatter interface. The Stacktrace
+// fields are rendered as indented blocks below the line.
+func (f *TextFormatter) Format(e *Entry) ([]byte, error) {
+	return f.appendFormat(make([]byte, 0, 64+len(e.Message)), e)
+}
+
+// appendFormat appends the formatted entry e to b.
+func (f *TextFormatter) appendFormat(b []byte, e *Entry) ([]byte, error) {
+	prefix := e.Level.prefix()
+	if f.Flags&log.Lmsgprefix == 0 {
+		b = append(b, prefix...)
+	}
+	b = f.appendHeader(b, e)
+	if f.Flags&log.Lmsgprefix != 0 {
+		b = append(b, prefix...)
+	}
+	b = append(b, e.Message...)
+	fields, stacks := splitStacktraces(e.Fields)
+	if len(fields) > 0 {
+		b = appendTextFields(b, fields)
+	}
+	b = append(b, '\n')
+	return appendStacktraces(b, stacks), nil
+}
+
+// appendHeader appends the date, time and file
+// selected by the flags, as the log package does.
+func (f *TextFormatter) appendHeader(b []byte, e *Entry) []byte {
+	if f.Flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		t := e.Time
+		if f.Flags&log.LUTC != 0 {
+			t = t.UTC()
+		}
+		if f.Flags&log.Ldate != 0 {
+			year, month, day := t.Date()
+			b = appendInt(b, year, 4)
+			b = append(b, '/')
+			b = appendInt(b, int(month), 2)
+			b = append(b, '/')
+			b = appendInt(b, day, 2)
+			b = append(b, ' ')
+		}
+		if f.Flags&(log.Ltime|log.Lmicroseconds) != 0 {
+			b = appendClock(b, t)
+			if f.Flags&log.Lmicroseconds != 0 {
+				b = append(b, '.')
+				b = appendInt(b, t.Nanosecond()/1e3, 6)
+			}
+			b = append(b, ' ')
+		}
+	}
+	if f.Caller != 0 {
+		b = appendCaller(b, e.Caller, f.Caller)
+		b = append(b, ": "...)
+	} else if f.Flags&(log.Lshortfile|log.Llongfile) != 0 {
+		file, line := "???", 0
+		if e.Caller != nil {
+			file, line = e.Caller.File, e.Caller.Line
+			if f.Flags&log.Lshortfile != 0 {
+				file = filepath.Base(file)
+			}
+		}
+		b = append(b, file...)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(line), 10)
+		b = append(b, ": "...)
+	}
+	return b
+}
+
+// appendInt appends i zero-padded to width digits.
+func appendInt(b []byte, i int, width int) []byte {
+	var buf [20]byte
+	n := len(buf) - 1
+	for i >= 10 || width > 1 {
+		width--
+		q := i / 10
+		buf[n] = byte('0' + i - q*10)
+		n--
+		i = q
+	}
+	buf[n] = byte('0' + i)
+	return append(b, buf[n:]...)
+}
+
+// JSONFormatter formats an entry as one JSON object per line with
+// the time, level, caller, msg and fields keys. Fields that collide
+// with these keys are prefixed with "fields.", as many times as
+// needed to collide with no other field.
+type JSONFormatter struct {
+	// TimeFormat is the layout of the timestamp.
+	// Defaults to time.RFC3339Nano.
+	TimeFormat string
+	// Caller selects how the call site is reported. Defaults to
+	// CallerShortFile. With CallerFunction, the function is
+	// reported apart, as the function key.
+	Caller CallerFormat
+}
+
+// reservedKeys are the keys written by the JSONFormatter.
+var reservedKeys = map[string]bool{
+	"time":             true,
+	"level":            true,
+	"caller":           true,
+	"msg":              true,
+	MessageTemplateKey: true,
+}
+
+// Format implements the Formatter interface.
+func (f *JSONFormatter) Format(e *Entry) ([]byte, error) {
+	return f.appendFormat(make([]byte, 0, 128+len(e.Message)), e)
+}
+
+// appendFormat appends the formatted entry e to b.
+func (f *JSONFormatter) appendFormat(b []byte, e *Entry) ([]byte, error) {
+	esc := getEscaper()
+	layout := f.TimeFormat
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	b = append(b, `{"time":`...)
+	if isFastTimeLayout(layout) {
+		b = append(b, '"')
+		b = appendTime(b, e.Time, layout)
+		b = append(b, '"')
+	} else {
+		b = esc.AppendJSON(b, e.Time.Format(layout))
+	}
+	b = append(b, `,"level":`...)
+	b = esc.AppendJSON(b, e.Level.name())
+	caller := f.Caller
+	if caller&^CallerFunction == 0 {
+		caller |= CallerShortFile
+	}
+	if e.Caller != nil {
+		b = append(b, `,"caller":`...)
+		b = esc.AppendJSON(b, string(appendCaller(nil, e.Caller, caller&^CallerFunction)))
+		if caller&CallerFunction != 0 {
+			b = append(b, `,"function":`...)
+			b = esc.AppendJSON(b, string(appendCaller(nil, e.Caller, CallerFunction)))
+		}
+	}
+	b = append(b, `,"msg":`...)
+	b = esc.AppendJSON(b, e.Message)
+	if e.Template != "" {
+		b = append(b, `,"`+MessageTemplateKey+`":`...)
+		b = esc.AppendJSON(b, e.Template)
+	}
+	b, err := appendJSONFields(b, e.Fields, func(k string) bool {
+		return reservedKeys[k] || (k == "function" && caller&CallerFunction != 0)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '}', '\n'), nil
+}
+
+// appendJSONFields appends the fields, sorted by key, as the members
+// of a JSON object following other members, whose keys are reserved.
+func appendJSONFields(b []byte, fields Fields, reserved func(k string) bool) ([]byte, error) {
+	esc := getEscaper()
+	for _, k := range fields.sortedKeys() {
+		b = append(b, ',')
+		b = esc.AppendJSON(b, fieldKey(k, fields, reserved))
+		b = append(b, ':')
+		var err error
+		if b, err = appendJSONValue(b, fields[k]); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// fieldKey returns the key of the field k of fields in an object
+// whose keys are reserved: k itself, unless it is reserved, in which
+// case it is prefixed with "fields." until it collides with neither
+// a reserved key nor another field.
+func fieldKey(k string, fields Fields, reserved func(k string) bool) string {
+	if !reserved(k) {
+		return k
+	}
+	key := "fields." + k
+	for {
+		if _, ok := fields[key]; !ok && !reserved(key) {
+			return key
+		}
+		key = "fields." + key
+	}
+}
+
+// appendJSONValue appends the JSON encoding of a field value.
+func appendJSONValue(b []byte, v interface{}) ([]byte, error) {
+	esc := getEscaper()
+	switch v := v.(type) {
+	case nil:
+		return append(b, "null"...), nil
+	case string:
+		return esc.AppendJSON(b, v), nil
+	case error:
+		return esc.AppendJSON(b, v.Error()), nil
+	case int:
+		return strconv.AppendInt(b, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(b, v, 10), nil
+	case bool:
+		return strconv.AppendBool(b, v), nil
+	}
+	p, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, p...), nil
+}
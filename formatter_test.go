@@ -0,0 +1,133 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextFormatter(t *testing.T) {
+	entry := &Entry{
+		Time:    time.Date(2020, 3, 4, 5, 6, 7, 8000, time.UTC),
+		Level:   WarningLevel,
+		Message: "Hello World",
+		Caller:  &runtime.Frame{File: "/src/app/main.go", Line: 42},
+	}
+	cases := []struct {
+		name  string
+		flags int
+		want  string
+	}{
+		{name: "no flags", flags: 0, want: "WARNING: Hello World\n"},
+		{name: "standard flags", flags: log.LstdFlags | log.LUTC, want: "WARNING: 2020/03/04 05:06:07 Hello World\n"},
+		{name: "microseconds", flags: log.Ltime | log.Lmicroseconds | log.LUTC, want: "WARNING: 05:06:07.000008 Hello World\n"},
+		{name: "short file", flags: log.Lshortfile, want: "WARNING: main.go:42: Hello World\n"},
+		{name: "long file", flags: log.Llongfile, want: "WARNING: /src/app/main.go:42: Hello World\n"},
+		{name: "message prefix", flags: log.Lshortfile | log.Lmsgprefix, want: "main.go:42: WARNING: Hello World\n"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := (&TextFormatter{Flags: c.flags}).Format(entry)
+			require.NoError(t, err)
+			assert.Equal(t, c.want, string(got))
+		})
+	}
+	t.Run("fields", func(t *testing.T) {
+		e := *entry
+		e.Fields = Fields{"user": 42}
+		got, err := (&TextFormatter{}).Format(&e)
+		require.NoError(t, err)
+		assert.Equal(t, "WARNING: Hello World user=42\n", string(got))
+	})
+	t.Run("same output as the log package", func(t *testing.T) {
+		SetLevel(InfoLevel)
+		var want, got bytes.Buffer
+		log.New(&want, InfoLevel.prefix(), log.Lshortfile).Println("Hello World")
+		newStdLogger(InfoLevel, &got, log.Lshortfile).Println("Hello World")
+		assert.Equal(t, want.String()[:len("INFO: formatter_test.go:")], got.String()[:len("INFO: formatter_test.go:")])
+	})
+}
+
+func TestJSONFormatter(t *testing.T) {
+	entry := &Entry{
+		Time:     time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC),
+		Level:    ErrorLevel,
+		Message:  "request \"failed\"",
+		Template: "request %q",
+		Fields:   Fields{"status": 500, "error": errors.New("boom"), "msg": "collides", "tags": []string{"a"}},
+		Caller:   &runtime.Frame{File: "/src/app/main.go", Line: 42},
+	}
+	got, err := (&JSONFormatter{}).Format(entry)
+	require.NoError(t, err)
+	assert.Equal(t, byte('\n'), got[len(got)-1])
+	assert.JSONEq(t, `{
+		"time": "2020-03-04T05:06:07Z",
+		"level": "error",
+		"caller": "main.go:42",
+		"msg": "request \"failed\"",
+		"msg_template": "request %q",
+		"status": 500,
+		"error": "boom",
+		"fields.msg": "collides",
+		"tags": ["a"]
+	}`, string(got))
+
+	t.Run("custom time format", func(t *testing.T) {
+		got, err := (&JSONFormatter{TimeFormat: time.Kitchen}).Format(&Entry{Time: entry.Time})
+		require.NoError(t, err)
+		assert.Contains(t, string(got), `"time":"5:06AM"`)
+	})
+	t.Run("renamed fields colliding with other fields", func(t *testing.T) {
+		got, err := (&JSONFormatter{}).Format(&Entry{Fields: Fields{
+			"msg":               "reserved",
+			"fields.msg":        "user",
+			"fields.fields.msg": "user too",
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, strings.Count(string(got), `"fields.msg":`), "duplicate key in %s", got)
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(got, &m))
+		assert.Equal(t, "user", m["fields.msg"])
+		assert.Equal(t, "user too", m["fields.fields.msg"])
+		assert.Equal(t, "reserved", m["fields.fields.fields.msg"])
+	})
+	t.Run("unsupported field value", func(t *testing.T) {
+		_, err := (&JSONFormatter{}).Format(&Entry{Fields: Fields{"nan": math.NaN()}})
+		assert.Error(t, err)
+	})
+}
+
+func TestStdLogger_SetFormatter(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var out bytes.Buffer
+	l := newStdLogger(InfoLevel, &out, log.LstdFlags)
+	l.SetFormatter(&JSONFormatter{})
+
+	l.WithFields(Fields{"request_id": "abc"}).Printf("Hello %s", "World")
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, "info", got["level"])
+	assert.Equal(t, "Hello World", got["msg"])
+	assert.Equal(t, "abc", got["request_id"])
+	assert.Equal(t, "formatter_test.go", got["caller"].(string)[:len("formatter_test.go")])
+	assert.NotContains(t, got, MessageTemplateKey)
+
+	t.Run("message template", func(t *testing.T) {
+		SetMessageTemplate(true)
+		defer SetMessageTemplate(false)
+		out.Reset()
+		l.Printf("Hello %s", "World")
+		assert.Contains(t, out.String(), `"msg_template":"Hello %s"`)
+	})
+}
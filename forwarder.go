@@ -0,0 +1,203 @@
+package golog
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// maxFrameSize is the largest entry accepted by readFrame.
+const maxFrameSize = 1 << 20
+
+var (
+	// ErrFrameTooLarge is returned when a framed entry exceeds
+	// the maximum size of one mebibyte.
+	ErrFrameTooLarge = errors.New("golog: frame too large")
+	// ErrForwarderClosed is returned by Forwarder.Serve
+	// after a call to Close.
+	ErrForwarderClosed = errors.New("golog: forwarder closed")
+)
+
+// writeFrame writes p to w prefixed with its length as
+// a 4 bytes big endian unsigned integer.
+func writeFrame(w io.Writer, p []byte) error {
+	if len(p) > maxFrameSize {
+		return ErrFrameTooLarge
+	}
+	buf := make([]byte, 4+len(p))
+	binary.BigEndian.PutUint32(buf, uint32(len(p)))
+	copy(buf[4:], p)
+	_, err := w.Write(buf)
+	return err
+}
+
+// readFrame reads one length-prefixed entry from r.
+func readFrame(r io.Reader) ([]byte, error) {
+	var hdr [4]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, err
+	}
+	n := binary.BigEndian.Uint32(hdr[:])
+	if n > maxFrameSize {
+		return nil, ErrFrameTooLarge
+	}
+	p := make([]byte, n)
+	if _, err := io.ReadFull(r, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// defaultDrainTimeout is the default DrainTimeout of a Forwarder.
+const defaultDrainTimeout = 100 * time.Millisecond
+
+// Forwarder accepts length-prefixed entries from local processes
+// and fans them out to its sinks, so several processes can share
+// one egress pipeline.
+type Forwarder struct {
+	sinks []io.Writer
+	// DrainTimeout is how long Close keeps reading the frames
+	// in flight on the open connections. Defaults to 100ms.
+	DrainTimeout time.Duration
+
+	mu        sync.Mutex // protects the fields below and the writes to the sinks
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// NewForwarder returns a Forwarder that writes every
+// entry it receives to each of the sinks.
+func NewForwarder(sinks ...io.Writer) *Forwarder {
+	return &Forwarder{
+		sinks:        sinks,
+		DrainTimeout: defaultDrainTimeout,
+		listeners:    make(map[net.Listener]struct{}),
+		conns:        make(map[net.Conn]struct{}),
+	}
+}
+
+// ServeForwarder listens on the unix socket addr and forwards
+// the received entries to the sinks. It blocks until an error
+// occurs. A stale socket file at addr is removed.
+func ServeForwarder(addr string, sinks ...io.Writer) error {
+	if fi, err := os.Stat(addr); err == nil && fi.Mode()&os.ModeSocket != 0 {
+		os.Remove(addr)
+	}
+	ln, err := net.Listen("unix", addr)
+	if err != nil {
+		return err
+	}
+	return NewForwarder(sinks...).Serve(ln)
+}
+
+// Serve accepts connections on ln until the forwarder is
+// closed or ln fails. It always returns a non-nil error.
+func (f *Forwarder) Serve(ln net.Listener) error {
+	f.mu.Lock()
+	if f.closed {
+		f.mu.Unlock()
+		ln.Close()
+		return ErrForwarderClosed
+	}
+	f.listeners[ln] = struct{}{}
+	f.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			f.mu.Lock()
+			closed := f.closed
+			delete(f.listeners, ln)
+			f.mu.Unlock()
+			if closed {
+				return ErrForwarderClosed
+			}
+			return err
+		}
+		f.mu.Lock()
+		if f.closed {
+			f.mu.Unlock()
+			conn.Close()
+			return ErrForwarderClosed
+		}
+		f.conns[conn] = struct{}{}
+		f.wg.Add(1)
+		f.mu.Unlock()
+		go f.handle(conn)
+	}
+}
+
+func (f *Forwarder) handle(conn net.Conn) {
+	defer f.wg.Done()
+	defer func() {
+		f.mu.Lock()
+		delete(f.conns, conn)
+		f.mu.Unlock()
+		conn.Close()
+	}()
+	r, err := frameReader(bufio.NewReader(conn))
+	if err != nil {
+		if !f.isClosed() {
+			reportf("golog: forwarder: %v", err)
+		}
+		return
+	}
+	for {
+		p, err := readFrame(r)
+		if err != nil {
+			// After Close, the connections end with the
+			// deadline of the drain.
+			if err != io.EOF && !f.isClosed() {
+				reportf("golog: forwarder: %v", err)
+			}
+			return
+		}
+		f.forward(p)
+	}
+}
+
+func (f *Forwarder) forward(p []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, w := range f.sinks {
+		if _, err := w.Write(p); err != nil {
+			reportf("golog: forwarder: %v", err)
+		}
+	}
+}
+
+func (f *Forwarder) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+// Close stops the listeners, then reads the frames in flight on the
+// open connections for DrainTimeout at most, and waits for them to
+// be forwarded before closing the connections.
+func (f *Forwarder) Close() error {
+	f.mu.Lock()
+	f.closed = true
+	var err error
+	for ln := range f.listeners {
+		if cerr := ln.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	// The handlers read until the deadline, or the end of the
+	// connection, and close it.
+	deadline := time.Now().Add(f.DrainTimeout)
+	for conn := range f.conns {
+		conn.SetReadDeadline(deadline)
+	}
+	f.mu.Unlock()
+	f.wg.Wait()
+	return err
+}
@@ -0,0 +1,97 @@
+package golog
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrame(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeFrame(&buf, []byte("hello")))
+		require.NoError(t, writeFrame(&buf, []byte("world")))
+		p, err := readFrame(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(p))
+		p, err = readFrame(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, "world", string(p))
+	})
+	t.Run("too large", func(t *testing.T) {
+		assert.Equal(t, ErrFrameTooLarge, writeFrame(ioutil.Discard, make([]byte, maxFrameSize+1)))
+		_, err := readFrame(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff}))
+		assert.Equal(t, ErrFrameTooLarge, err)
+	})
+}
+
+func TestForwarder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	addr := filepath.Join(dir, "forwarder.sock")
+
+	sink1, sink2 := &syncBuffer{}, &syncBuffer{}
+	ln, err := net.Listen("unix", addr)
+	require.NoError(t, err)
+	f := NewForwarder(sink1, sink2)
+	served := make(chan error, 1)
+	go func() { served <- f.Serve(ln) }()
+
+	for _, msg := range []string{"INFO: from process 1\n", "ERROR: from process 2\n"} {
+		conn, err := net.Dial("unix", addr)
+		require.NoError(t, err)
+		require.NoError(t, writeFrame(conn, []byte(msg)))
+		conn.Close()
+	}
+
+	assert.Eventually(t, func() bool {
+		return bytes.Count([]byte(sink2.String()), []byte("\n")) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Contains(t, sink1.String(), "from process 1")
+	assert.Contains(t, sink1.String(), "from process 2")
+
+	require.NoError(t, f.Close())
+	assert.Equal(t, ErrForwarderClosed, <-served)
+}
+
+func TestForwarder_Close(t *testing.T) {
+	var errs syncBuffer
+	SetDiagnosticHandler(func(err error) { errs.Write([]byte(err.Error() + "\n")) })
+	defer SetDiagnosticHandler(nil)
+
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	addr := filepath.Join(dir, "forwarder.sock")
+	ln, err := net.Listen("unix", addr)
+	require.NoError(t, err)
+	sink := &syncBuffer{}
+	f := NewForwarder(sink)
+	served := make(chan error, 1)
+	go func() { served <- f.Serve(ln) }()
+
+	// The connection stays open, with frames in flight.
+	conn, err := net.Dial("unix", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, writeFrame(conn, []byte("INFO: accepted\n")))
+	assert.Eventually(t, func() bool { return sink.String() != "" }, time.Second, time.Millisecond)
+	for i := 0; i < 100; i++ {
+		require.NoError(t, writeFrame(conn, []byte("INFO: in flight\n")))
+	}
+	require.NoError(t, f.Close())
+	assert.Equal(t, ErrForwarderClosed, <-served)
+	assert.Equal(t, 100, strings.Count(sink.String(), "in flight"))
+	assert.Empty(t, errs.String())
+	_, err = net.Dial("unix", addr)
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package golog
+
+import (
+	"strconv"
+	"time"
+)
+
+// gcpSourceLocationKey is the key of the caller
+// written by the GCPFormatter.
+const gcpSourceLocationKey = "logging.googleapis.com/sourceLocation"
+
+// gcpKeys are the keys written by the GCPFormatter.
+var gcpKeys = map[string]bool{
+	"timestamp":          true,
+	"severity":           true,
+	"message":            true,
+	gcpSourceLocationKey: true,
+	MessageTemplateKey:   true,
+}
+
+// GCPFormatter formats an entry as one JSON object per line in the
+// structured logging format of Google Cloud Logging, which parses the
+// entries written to the standard output on Cloud Run, GKE or App
+// Engine:
+//
+//	{"timestamp":"2020-03-04T05:06:07.891011Z","severity":"WARNING","message":"disk almost full","used":93}
+//
+// The severity is given by GCPSeverity, and the caller is written as
+// the logging.googleapis.com/sourceLocation object. Fields that collide
+// with the keys of the formatter are prefixed with "fields.", as by
+// the JSONFormatter.
+type GCPFormatter struct{}
+
+// GCPSeverity returns the LogSeverity of lvl in Google Cloud
+// Logging: DEBUG for DebugLevel and TraceLevel, INFO, WARNING
+// and ERROR.
+func GCPSeverity(lvl Level) string {
+	switch lvl {
+	case InfoLevel:
+		return "INFO"
+	case WarningLevel:
+		return "WARNING"
+	case ErrorLevel:
+		return "ERROR"
+	}
+	return "DEBUG"
+}
+
+// Format implements the Formatter interface.
+func (f *GCPFormatter) Format(e *Entry) ([]byte, error) {
+	return f.appendFormat(make([]byte, 0, 160+len(e.Message)), e)
+}
+
+// appendFormat appends the formatted entry e to b.
+func (f *GCPFormatter) appendFormat(b []byte, e *Entry) ([]byte, error) {
+	esc := getEscaper()
+	b = append(b, `{"timestamp":"`...)
+	b = appendTime(b, e.Time.UTC(), time.RFC3339Nano)
+	b = append(b, `","severity":"`...)
+	b = append(b, GCPSeverity(e.Level)...)
+	b = append(b, `","message":`...)
+	b = esc.AppendJSON(b, e.Message)
+	if e.Caller != nil {
+		// The line is a string, as the int64 values of the
+		// protocol buffers encoded in JSON.
+		b = append(b, `,"`+gcpSourceLocationKey+`":{"file":`...)
+		b = esc.AppendJSON(b, e.Caller.File)
+		b = append(b, `,"line":"`...)
+		b = strconv.AppendInt(b, int64(e.Caller.Line), 10)
+		b = append(b, '"')
+		if e.Caller.Function != "" {
+			b = append(b, `,"function":`...)
+			b = esc.AppendJSON(b, e.Caller.Function)
+		}
+		b = append(b, '}')
+	}
+	if e.Template != "" {
+		b = append(b, `,"`+MessageTemplateKey+`":`...)
+		b = esc.AppendJSON(b, e.Template)
+	}
+	b, err := appendJSONFields(b, e.Fields, func(k string) bool { return gcpKeys[k] })
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '}', '\n'), nil
+}
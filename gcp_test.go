@@ -0,0 +1,39 @@
+package golog
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCPFormatter(t *testing.T) {
+	entry := &Entry{
+		Time:    time.Date(2020, 3, 4, 5, 6, 7, 891011000, time.UTC),
+		Level:   WarningLevel,
+		Message: "slow query",
+		Fields:  Fields{"ms": 1200, "severity": 13},
+		Caller:  &runtime.Frame{File: "/src/app/db.go", Line: 7, Function: "example.com/app.query"},
+	}
+	got, err := (&GCPFormatter{}).Format(entry)
+	require.NoError(t, err)
+	assert.Equal(t, byte('\n'), got[len(got)-1])
+	assert.JSONEq(t, `{
+		"timestamp": "2020-03-04T05:06:07.891011Z",
+		"severity": "WARNING",
+		"message": "slow query",
+		"logging.googleapis.com/sourceLocation": {"file": "/src/app/db.go", "line": "7", "function": "example.com/app.query"},
+		"ms": 1200,
+		"fields.severity": 13
+	}`, string(got))
+}
+
+func TestGCPSeverity(t *testing.T) {
+	assert.Equal(t, "DEBUG", GCPSeverity(DebugLevel))
+	assert.Equal(t, "DEBUG", GCPSeverity(TraceLevel))
+	assert.Equal(t, "INFO", GCPSeverity(InfoLevel))
+	assert.Equal(t, "WARNING", GCPSeverity(WarningLevel))
+	assert.Equal(t, "ERROR", GCPSeverity(ErrorLevel))
+}
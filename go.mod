@@ -1,8 +1,9 @@
 module github.com/jayvib/golog
 
-go 1.13
+go 1.14
 
 require (
 	github.com/sirupsen/logrus v1.4.2
-	github.com/stretchr/testify v1.4.0
+	github.com/stretchr/testify v1.7.0
+	gopkg.in/yaml.v2 v2.2.2
 )
@@ -6,30 +6,57 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
+// Concurrency guarantees
+//
+// All the exported functions and the loggers of the package are safe
+// to use concurrently. Configuration is never mutated in place: SetLevel
+// publishes a new immutable global state and SetOutput swaps the
+// destination of a logger atomically. The guarantees for an entry
+// logged during a concurrent change are narrower:
+//
+//   - The level gate and the write each load the global state, so an
+//     entry let through by the previous level may be written with the
+//     state published since.
+//   - From the mute rules to the hooks, the write uses a single global
+//     state, never a mix of two.
+//   - The formatter and the output of the logger are read together,
+//     under the lock of the output, after that state is loaded, so the
+//     entry is formatted and written with the ones set at that time.
 var (
-	// mu protects read-write of the state
-	mu sync.RWMutex
-	// state is the global state of the package log level
-	state = &globalState{
-		currentLevel: InfoLevel,
-	}
+	// mu serializes the updates of the state.
+	mu sync.Mutex
+	// state holds the *globalState of the package. It is loaded
+	// without locking, so suppressed calls on the hot path never
+	// contend with each other. A published state must be treated
+	// as immutable.
+	state atomic.Value
 )
+
+func init() {
+	state.Store(&globalState{
+		currentLevel: InfoLevel,
+		stackLevel:   DisabledLevel,
+	})
+}
+
 var (
 	// DebugLogger is a standard logger use for debugging.
-	DebugLogger = &stdLogger{level: DebugLevel, l: log.New(os.Stdout, DebugLevel.String(), log.LstdFlags|log.Lshortfile)}
+	DebugLogger = newLevelLogger(DebugLevel)
 	// TraceLogger is a standard logger use for tracing.
-	TraceLogger = &stdLogger{level: TraceLevel, l: log.New(os.Stdout, TraceLevel.String(), log.LstdFlags|log.Lshortfile)}
+	TraceLogger = newLevelLogger(TraceLevel)
 	// InfoLogger is a standard logger info log.
-	InfoLogger = &stdLogger{level: InfoLevel, l: log.New(os.Stdout, InfoLevel.String(), log.LstdFlags)}
+	InfoLogger = newLevelLogger(InfoLevel)
 	// WarningLogger is a standard logger warning log.
-	WarningLogger = &stdLogger{level: WarningLevel, l: log.New(os.Stdout, WarningLevel.String(), log.LstdFlags)}
+	WarningLogger = newLevelLogger(WarningLevel)
 	// ErrorLogger is a standard logger use for printing errors.
-	ErrorLogger = &stdLogger{level: ErrorLevel, l: log.New(os.Stdout, ErrorLevel.String(), log.LstdFlags|log.Lshortfile)}
+	ErrorLogger = newLevelLogger(ErrorLevel)
 	// DisabledLogger is a standard logger use to disable all logs.
-	DisabledLogger = &stdLogger{level: DisabledLevel, l: log.New(ioutil.Discard, DisabledLevel.String(), log.LstdFlags|log.Lshortfile)}
+	DisabledLogger = newLevelLogger(DisabledLevel)
 )
 
 // Level represents the log level of severity
@@ -51,8 +78,14 @@ const (
 	DisabledLevel              // DisabledLevel use level for disabled state
 )
 
-var _ Logger = (*stdLogger)(nil)
-var _ Logger = (*Logrus)(nil)
+var _ FieldLogger = (*stdLogger)(nil)
+var _ FieldLogger = (*Logrus)(nil)
+var _ Pauser = (*stdLogger)(nil)
+var _ Pauser = (*Logrus)(nil)
+var _ ClockSetter = (*stdLogger)(nil)
+var _ ClockSetter = (*Logrus)(nil)
+var _ ExitFuncSetter = (*stdLogger)(nil)
+var _ ExitFuncSetter = (*Logrus)(nil)
 
 // Logger represents a general logger interface.
 type Logger interface {
@@ -64,8 +97,81 @@ type Logger interface {
 	SetOutput(w io.Writer)
 }
 
-// String is to implement Stringer interface
+// FieldLogger is a Logger choosing the level of every entry and
+// deriving the loggers adding fields, as the loggers of NewStdLogger
+// and NewLogrusLogger do. Print, Printf, Println, Fatal and Fatalf
+// log at the level of the logger, Panic and Panicf at ErrorLevel, the
+// leveled methods, from Debug to Errorf, at their own level and Log
+// and Logf at the level chosen by the caller.
+//
+// The other capabilities of the loggers of the package are left to
+// small interfaces, such as Pauser, ClockSetter and ExitFuncSetter,
+// so a Logger can be checked for them with a type assertion.
+type FieldLogger interface {
+	Logger
+	Panic(v ...interface{})
+	Panicf(format string, v ...interface{})
+	Debug(v ...interface{})
+	Debugf(format string, v ...interface{})
+	Trace(v ...interface{})
+	Tracef(format string, v ...interface{})
+	Info(v ...interface{})
+	Infof(format string, v ...interface{})
+	Warn(v ...interface{})
+	Warnf(format string, v ...interface{})
+	Error(v ...interface{})
+	Errorf(format string, v ...interface{})
+	Log(lvl Level, v ...interface{})
+	Logf(lvl Level, format string, v ...interface{})
+	DebugFn(fn func() string)
+	TraceFn(fn func() string)
+	LogFn(lvl Level, fn func() string)
+	Debugw(msg string, fields ...Field)
+	Tracew(msg string, fields ...Field)
+	Infow(msg string, fields ...Field)
+	Warnw(msg string, fields ...Field)
+	Errorw(msg string, fields ...Field)
+	SetFormatter(f Formatter)
+	WithFields(fields Fields) FieldLogger
+	With(fields Fields) FieldLogger
+	Named(name string) FieldLogger
+	AddCallerSkip(n int) FieldLogger
+	AddStacktrace(lvl Level) FieldLogger
+	WithError(err error) FieldLogger
+	AddHook(h Hook)
+	AddSampler(s Sampler)
+	Writer() io.WriteCloser
+}
+
+// Pauser is implemented by the loggers whose entries can be held
+// in memory while the output is swapped, see Pause.
+type Pauser interface {
+	Pause()
+	Resume()
+}
+
+// ClockSetter is implemented by the loggers telling the time
+// of their entries with a Clock that can be replaced.
+type ClockSetter interface {
+	SetClock(c Clock)
+}
+
+// ExitFuncSetter is implemented by the loggers whose exit function
+// can be replaced, see SetExitFunc.
+type ExitFuncSetter interface {
+	SetExitFunc(fn func(code int))
+}
+
+// String returns the lower case name of the level, e.g. "debug",
+// as accepted by ParseLevel. It implements the fmt.Stringer and,
+// along with Set, the flag.Value interfaces.
 func (l Level) String() string {
+	return l.name()
+}
+
+// prefix returns the prefix of the entries of the level
+// written by the TextFormatter, e.g. "DEBUG: ".
+func (l Level) prefix() string {
 	switch l {
 	case DebugLevel:
 		return "DEBUG: "
@@ -83,49 +189,226 @@ func (l Level) String() string {
 	return ""
 }
 
+// name returns the lower case name of the level
+// used by the structured formatters.
+func (l Level) name() string {
+	switch l {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case ErrorLevel:
+		return "error"
+	case DisabledLevel:
+		return "disabled"
+	case TraceLevel:
+		return "trace"
+	case WarningLevel:
+		return "warning"
+	}
+	return ""
+}
+
+// ParseLevel returns the level named s. The name is case-insensitive
+// and one of "debug", "trace", "info", "warning" (or "warn"), "error"
+// and "disabled".
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return DebugLevel, nil
+	case "trace":
+		return TraceLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "warning", "warn":
+		return WarningLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "disabled":
+		return DisabledLevel, nil
+	}
+	return InfoLevel, fmt.Errorf("golog: unknown level %q", s)
+}
+
+// globalState is a snapshot of the package configuration.
 type globalState struct {
 	currentLevel Level
+	// messageTemplate records the format string of the
+	// Printf-style calls in the structured output.
+	messageTemplate bool
+	// muteRules are the scheduled mute rules.
+	muteRules []muteRule
+	// processors are run on every entry before formatting.
+	processors []Processor
+	// samplers decide which entries are emitted.
+	samplers []Sampler
+	// captures enable all the levels for matching fields.
+	captures []verboseCapture
+	// hooks are fired on every entry before formatting.
+	hooks []Hook
+	// exit terminates the program after a fatal entry.
+	exit func(int)
+	// onFatal are called before exit.
+	onFatal []func()
+	// ordered serializes the emission of all the entries.
+	ordered bool
+	// escalations turn the repeated entries into errors.
+	escalations []*escalation
+	// loggerNames names the entries of the package level
+	// functions after the calling package.
+	loggerNames bool
+	// loggerLevels are the levels of the named loggers.
+	loggerLevels map[string]Level
+	// globalFields are attached to every entry.
+	globalFields Fields
+	// fieldPrecedence is the order in which the sources
+	// of the fields override each other.
+	fieldPrecedence FieldPrecedence
+	// stackLevel is the level from which the entries
+	// capture a stack trace.
+	stackLevel Level
+	// errorStacks attaches the stack traces of the errors.
+	errorStacks bool
+	// errorChains attaches the chains of the errors.
+	errorChains bool
+	// bundle keeps the debug entries to bundle with the errors.
+	bundle *bundler
+	// contextExtractors extract the fields of the contexts.
+	contextExtractors []ContextExtractor
 }
 
+// getState returns the current snapshot of the global state.
+// The returned value must not be modified.
 func getState() *globalState {
+	return state.Load().(*globalState)
+}
+
+// updateState publishes a copy of the current state
+// modified by fn.
+func updateState(fn func(s *globalState)) {
 	mu.Lock()
 	defer mu.Unlock()
-	return state
+	next := *getState()
+	fn(&next)
+	state.Store(&next)
 }
+
 func setGlobalStateLevel(lvl Level) {
-	mu.Lock()
-	defer mu.Unlock()
-	state.currentLevel = lvl
+	updateState(func(s *globalState) {
+		s.currentLevel = lvl
+	})
 }
 
 // SetLevel accepts log level to be set on the
 // global state.
 func SetLevel(lvl Level) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	cancelOverride("")
 	setGlobalStateLevel(lvl)
+	levelsChanged()
+}
+
+// GetLevel returns the global level.
+func GetLevel() Level {
+	return getState().currentLevel
+}
+
+// MessageTemplateKey is the field key that holds the raw format
+// string of Printf-style calls when message templates are enabled.
+const MessageTemplateKey = "msg_template"
+
+// SetMessageTemplate enables or disables recording the raw format
+// string of Printf-style calls as the MessageTemplateKey field in
+// structured output, so identical events can be grouped even when
+// the interpolated values differ. Plain text output is not affected.
+func SetMessageTemplate(enabled bool) {
+	updateState(func(s *globalState) {
+		s.messageTemplate = enabled
+	})
+}
+
+// packageLoggers returns the package level loggers
+// that are not disabled.
+func packageLoggers() []*stdLogger {
+	return []*stdLogger{DebugLogger, TraceLogger, InfoLogger, WarningLogger, ErrorLogger}
+}
+
+// packageLogger returns the package level logger
+// of lvl, or nil when there is none.
+func packageLogger(lvl Level) *stdLogger {
+	for _, l := range packageLoggers() {
+		if l.level == lvl {
+			return l
+		}
+	}
+	return nil
+}
+
+// SetFormatter sets the formatter of all the package level loggers.
+func SetFormatter(f Formatter) {
+	for _, l := range packageLoggers() {
+		l.SetFormatter(f)
+	}
+}
+
+// SetOutput sets the output of all the package level loggers.
+func SetOutput(w io.Writer) {
+	for _, l := range packageLoggers() {
+		l.SetOutput(w)
+	}
+}
+
+// SetLevelOutput sets the output of the package level logger
+// of lvl, so the severities can be split between streams:
+//
+//	golog.SetOutput(os.Stdout)
+//	golog.SetLevelOutput(golog.ErrorLevel, os.Stderr)
+//
+// Fatal and Fatalf write to the output of ErrorLevel.
+func SetLevelOutput(lvl Level, w io.Writer) {
+	if l := packageLogger(lvl); l != nil {
+		l.SetOutput(w)
+	}
+}
+
+// LevelOutput returns the output of the package level logger
+// of lvl, or nil when there is none.
+func LevelOutput(lvl Level) io.Writer {
+	l := packageLogger(lvl)
+	if l == nil {
+		return nil
+	}
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	return l.out.w
+}
+
+// NewStdLogger accepts level and return a new standard
+// logger that is bind to the level. Every call returns an
+// independent logger with its own output and formatter,
+// writing to os.Stdout.
+func NewStdLogger(level Level) FieldLogger {
+	return newLevelLogger(level)
 }
 
-// NewStdLogger accepts level and return a
-// standard logger that is bind to the level.
-func NewStdLogger(level Level) Logger {
-	return loggerFactory(level)
+// newLevelLogger returns a logger of level with the
+// default output and flags of the level.
+func newLevelLogger(level Level) *stdLogger {
+	if level == DisabledLevel {
+		return newStdLogger(level, ioutil.Discard, defaultFlags(level))
+	}
+	return newStdLogger(level, os.Stdout, defaultFlags(level))
 }
-func loggerFactory(level Level) Logger {
-	var l Logger
+
+// defaultFlags returns the standard log package flags
+// used by the loggers of level.
+func defaultFlags(level Level) int {
 	switch level {
-	case DebugLevel:
-		l = DebugLogger
-	case TraceLevel:
-		l = TraceLogger
-	case InfoLevel:
-		l = InfoLogger
-	case WarningLevel:
-		l = WarningLogger
-	case ErrorLevel:
-		l = ErrorLogger
-	case DisabledLevel:
-		l = DisabledLogger
+	case InfoLevel, WarningLevel:
+		return log.LstdFlags
 	}
-	return l
+	return log.LstdFlags | log.Lshortfile
 }
 
 const (
@@ -133,8 +416,45 @@ const (
 )
 
 type stdLogger struct {
-	level Level
-	l     *log.Logger
+	level    Level
+	out      *output
+	fields   Fields
+	counters levelCounters
+	// callerSkip is the number of wrapper frames skipped
+	// to report the caller.
+	callerSkip int
+	// ctxFields are the fields of the context the
+	// logger was taken from by FromContext.
+	ctxFields Fields
+	// stackLevel overrides the global level from which the
+	// entries capture a stack trace when not nil.
+	stackLevel *Level
+}
+
+// output is the destination and the formatter of a logger. It is
+// shared by the loggers derived with WithFields.
+type output struct {
+	// mu serializes the writes and the changes of configuration,
+	// so every entry is formatted and written with one configuration.
+	mu        sync.Mutex
+	w         io.Writer
+	formatter Formatter
+	hooks     []Hook
+	samplers  []Sampler
+	clock     clockValue
+	exit      func(int)
+}
+
+// newStdLogger returns a logger of level writing to w with a
+// TextFormatter using the standard log package flags.
+func newStdLogger(level Level, w io.Writer, flags int) *stdLogger {
+	return &stdLogger{
+		level: level,
+		out: &output{
+			w:         w,
+			formatter: &TextFormatter{Flags: flags},
+		},
+	}
 }
 
 func (l *stdLogger) Print(v ...interface{}) {
@@ -147,40 +467,404 @@ func (l *stdLogger) Printf(format string, v ...interface{}) {
 	if !l.isPrint() {
 		return
 	}
-	l.Output(stdCallDepth, fmt.Sprintf(format, v...))
+	l.outputTemplate(stdCallDepth, fmt.Sprintf(format, v...), format)
 }
 func (l *stdLogger) Println(v ...interface{}) {
 	if !l.isPrint() {
 		return
 	}
-	l.Output(stdCallDepth, fmt.Sprintln(v...))
+	l.Output(stdCallDepth, sprintln(v))
 }
 func (l *stdLogger) Fatal(v ...interface{}) {
 	if !l.isPrint() {
 		return
 	}
-	l.Output(stdCallDepth, fmt.Sprint(v...))
-	os.Exit(1)
+	l.outputFatal(stdCallDepth, fmt.Sprint(v...), "")
+	l.exit()
 }
 func (l *stdLogger) Fatalf(format string, v ...interface{}) {
 	if !l.isPrint() {
 		return
 	}
-	l.Output(stdCallDepth, fmt.Sprintf(format, v...))
-	os.Exit(1)
+	l.outputFatal(stdCallDepth, fmt.Sprintf(format, v...), format)
+	l.exit()
+}
+func (l *stdLogger) Panic(v ...interface{}) {
+	s := fmt.Sprint(v...)
+	if l.isPrintLevel(ErrorLevel) {
+		l.outputLevel(stdCallDepth, ErrorLevel, s, "")
+	}
+	panic(s)
+}
+func (l *stdLogger) Panicf(format string, v ...interface{}) {
+	s := fmt.Sprintf(format, v...)
+	if l.isPrintLevel(ErrorLevel) {
+		l.outputLevel(stdCallDepth, ErrorLevel, s, format)
+	}
+	panic(s)
+}
+func (l *stdLogger) Debug(v ...interface{}) {
+	if l.isPrintLevel(DebugLevel) {
+		l.outputLevel(stdCallDepth, DebugLevel, sprintln(v), "")
+	}
+}
+func (l *stdLogger) Debugf(format string, v ...interface{}) {
+	if l.isPrintLevel(DebugLevel) {
+		l.outputLevel(stdCallDepth, DebugLevel, fmt.Sprintf(format, v...), format)
+	}
+}
+func (l *stdLogger) Trace(v ...interface{}) {
+	if l.isPrintLevel(TraceLevel) {
+		l.outputLevel(stdCallDepth, TraceLevel, sprintln(v), "")
+	}
+}
+func (l *stdLogger) Tracef(format string, v ...interface{}) {
+	if l.isPrintLevel(TraceLevel) {
+		l.outputLevel(stdCallDepth, TraceLevel, fmt.Sprintf(format, v...), format)
+	}
+}
+func (l *stdLogger) Info(v ...interface{}) {
+	if l.isPrintLevel(InfoLevel) {
+		l.outputLevel(stdCallDepth, InfoLevel, sprintln(v), "")
+	}
+}
+func (l *stdLogger) Infof(format string, v ...interface{}) {
+	if l.isPrintLevel(InfoLevel) {
+		l.outputLevel(stdCallDepth, InfoLevel, fmt.Sprintf(format, v...), format)
+	}
+}
+func (l *stdLogger) Warn(v ...interface{}) {
+	if l.isPrintLevel(WarningLevel) {
+		l.outputLevel(stdCallDepth, WarningLevel, sprintln(v), "")
+	}
+}
+func (l *stdLogger) Warnf(format string, v ...interface{}) {
+	if l.isPrintLevel(WarningLevel) {
+		l.outputLevel(stdCallDepth, WarningLevel, fmt.Sprintf(format, v...), format)
+	}
+}
+func (l *stdLogger) Error(v ...interface{}) {
+	if l.isPrintLevel(ErrorLevel) {
+		l.outputLevel(stdCallDepth, ErrorLevel, sprintln(v), "")
+	}
+}
+func (l *stdLogger) Errorf(format string, v ...interface{}) {
+	if l.isPrintLevel(ErrorLevel) {
+		l.outputLevel(stdCallDepth, ErrorLevel, fmt.Sprintf(format, v...), format)
+	}
+}
+func (l *stdLogger) Log(lvl Level, v ...interface{}) {
+	if lvl < DisabledLevel && l.isPrintLevel(lvl) {
+		l.outputLevel(stdCallDepth, lvl, sprintln(v), "")
+	}
+}
+func (l *stdLogger) Logf(lvl Level, format string, v ...interface{}) {
+	if lvl < DisabledLevel && l.isPrintLevel(lvl) {
+		l.outputLevel(stdCallDepth, lvl, fmt.Sprintf(format, v...), format)
+	}
 }
 func (l *stdLogger) isPrint() bool {
-	gstate := getState()
-	if l.level < gstate.currentLevel {
-		return false
+	return l.isPrintLevel(l.level)
+}
+
+// isPrintLevel reports whether the entries of l at lvl are printed,
+// or kept by the context bundling.
+func (l *stdLogger) isPrintLevel(lvl Level) bool {
+	return l.isPrintCall(lvl, nil)
+}
+
+// isPrintCall reports whether the entries of l at lvl with the fields
+// of the call are printed, or kept by the context bundling.
+func (l *stdLogger) isPrintCall(lvl Level, call Fields) bool {
+	st := getState()
+	if st.enabled(lvl, l.gateFields(st, call)) {
+		return true
 	}
-	return true
+	return st.bundle != nil && st.bundle.keeps(lvl, l.fields, l.ctxFields, call)
 }
 func (l *stdLogger) SetOutput(w io.Writer) {
-	l.l.SetOutput(w)
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.w = w
+}
+
+// Out returns the output of the logger.
+func (l *stdLogger) Out() io.Writer {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	return l.out.w
+}
+
+// SetFormatter sets the formatter of the logger. A nil
+// formatter restores a TextFormatter with the standard flags.
+func (l *stdLogger) SetFormatter(f Formatter) {
+	if f == nil {
+		f = &TextFormatter{Flags: log.LstdFlags}
+	}
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.formatter = f
+}
+
+// AddHook registers h to be fired on every entry of the logger
+// and of the loggers derived from it with WithFields.
+func (l *stdLogger) AddHook(h Hook) {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	l.out.hooks = append(l.out.hooks, h)
+}
+
+// SetClock sets the clock telling the time of the entries of
+// the logger and of the loggers derived with WithFields, so tests
+// simulating several components can use independent fake clocks.
+// A nil clock restores the system clock.
+func (l *stdLogger) SetClock(c Clock) {
+	l.out.clock.set(c)
+}
+
+// AddSampler registers s to be consulted, after the global
+// samplers, on every entry of l and of the loggers sharing
+// its output.
+func (l *stdLogger) AddSampler(s Sampler) {
+	l.out.mu.Lock()
+	defer l.out.mu.Unlock()
+	samplers := make([]Sampler, len(l.out.samplers), len(l.out.samplers)+1)
+	copy(samplers, l.out.samplers)
+	l.out.samplers = append(samplers, s)
 }
+
+// Output writes the entry s. calldepth has the same meaning
+// as in log.Logger.Output; zero leaves the call site unset.
 func (l *stdLogger) Output(calldepth int, s string) {
-	l.l.Output(calldepth, s)
+	l.write(calldepth, l.level, s, "", writeDefault, nil)
+}
+
+// outputTemplate writes the entry s logged with
+// the Printf-style format template.
+func (l *stdLogger) outputTemplate(calldepth int, s, template string) {
+	l.write(calldepth, l.level, s, template, writeDefault, nil)
+}
+
+// outputLevel writes the entry s at lvl instead of
+// the level of l.
+func (l *stdLogger) outputLevel(calldepth int, lvl Level, s, template string) {
+	l.write(calldepth, lvl, s, template, writeDefault, nil)
+}
+
+// outputFatal writes the entry s of a Fatal call, which
+// captures a stack trace whatever the level of l.
+func (l *stdLogger) outputFatal(calldepth int, s, template string) {
+	l.write(calldepth, l.level, s, template, writeFatal, nil)
+}
+
+// writeMode tells the entries of the Fatal calls and of Force
+// from the others.
+type writeMode int
+
+const (
+	writeDefault writeMode = iota
+	writeFatal
+	writeForced
+)
+
+// write writes the entry s at lvl, which is usually the level of l,
+// with the fields of the call and the handling of mode.
+func (l *stdLogger) write(calldepth int, lvl Level, s, template string, mode writeMode, call Fields) {
+	st := getState()
+	if mode != writeForced && st.isMuted(lvl, func() string { return s }) {
+		return
+	}
+	e := getEntry()
+	defer releaseEntry(e)
+	e.Level = lvl
+	e.Forced = mode == writeForced
+	e.fatal = mode == writeFatal
+	e.Message = strings.TrimSuffix(s, "\n")
+	e.Fields = call
+	l.attachFields(st, e)
+	if st.messageTemplate {
+		e.Template = template
+	}
+	if calldepth > 0 {
+		// Account for the frame of write.
+		calldepth += 1 + l.callerSkip
+		if captureStack(l.stackThreshold(st), lvl, mode == writeFatal) {
+			resolveFields(e)
+			// write is the caller of stacktrace, not a frame above it.
+			addCallField(e, StacktraceKey, stacktrace(calldepth-1))
+		}
+	}
+	if l.emitOrdered(st, e, calldepth) {
+		escalate(st.escalations, e)
+	}
+}
+
+// emitOrdered times and emits e, holding the dispatcher lock when
+// the ordered delivery is enabled. A zero Time is replaced by the
+// time of the clock of l.
+func (l *stdLogger) emitOrdered(st *globalState, e *Entry, calldepth int) bool {
+	defer lockOrdered(st)()
+	if e.Time.IsZero() {
+		e.Time = l.out.clock.now()
+	}
+	if calldepth > 0 {
+		// Account for the frame of emitOrdered.
+		calldepth++
+	}
+	return l.emit(st, e, calldepth)
+}
+
+// emit samples, processes, formats and writes e with the state st
+// and reports whether e was kept by the samplers and the levels of
+// the named loggers. The call site calldepth levels above the caller
+// of emit is recorded when e has no caller and one is needed; a zero
+// calldepth leaves the caller unset.
+func (l *stdLogger) emit(st *globalState, e *Entry, calldepth int) bool {
+	resolveFields(e)
+	if st.loggerNames && calldepth > 0 && l.isPackageLogger() {
+		nameEntry(e, calldepth)
+		if !st.enabled(e.Level, e.Fields) && st.bundle == nil {
+			return false
+		}
+	}
+	o := l.out
+	o.mu.Lock()
+	samplers := o.samplers
+	o.mu.Unlock()
+	if !admit(st, e, samplers, &l.counters) {
+		return false
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	hooked := len(st.hooks)+len(o.hooks) > 0
+	if e.Caller == nil && calldepth > 0 && (hooked || reportsCaller(o.formatter)) {
+		e.Caller = callerFrame(calldepth)
+	}
+	process(st, e, o.hooks)
+	b, buf := formatEntry(o.formatter, e)
+	defer releaseBuffer(buf, b)
+	if holdWrite(o, e.Level, b) {
+		e.probe.record(o.w, true, nil)
+		return true
+	}
+	n, err := writeEntry(o.w, e, b)
+	countBytes(o.w, n)
+	if err != nil {
+		countDrop()
+	}
+	e.probe.record(o.w, false, err)
+	return true
+}
+
+// Emit writes e with the package level logger of its level, so
+// adapters of other logging APIs share the level state, the
+// pipeline and the outputs of golog. e is dropped when its level
+// is disabled, but neither muted nor sampled when it is Forced. A
+// zero Time is replaced by the current time and the Template is only
+// kept when message templates are enabled. The Caller is reported
+// as is.
+func Emit(e *Entry) {
+	l := packageLogger(e.Level)
+	if l == nil {
+		return
+	}
+	st := getState()
+	if !st.enabled(e.Level, e.Fields) {
+		return
+	}
+	if !e.Forced && st.isMuted(e.Level, func() string { return e.Message }) {
+		return
+	}
+	entry := *e
+	entry.Message = strings.TrimSuffix(entry.Message, "\n")
+	if !st.messageTemplate {
+		entry.Template = ""
+	}
+	attachGlobalFields(st, &entry)
+	if l.emitOrdered(st, &entry, 0) {
+		escalate(st.escalations, &entry)
+	}
+}
+
+// Enabled reports whether the entries of lvl are
+// logged at the current global level.
+func Enabled(lvl Level) bool {
+	return lvl < DisabledLevel && lvl >= getState().currentLevel
+}
+
+// reportsCaller reports whether f needs the call site
+// of the entries.
+func reportsCaller(f Formatter) bool {
+	switch f := f.(type) {
+	case *TextFormatter:
+		return f.Flags&(log.Lshortfile|log.Llongfile) != 0 || f.Caller != 0
+	case *ConsoleFormatter, *CLIFormatter, *SyslogFormatter:
+		return false
+	}
+	return true
+}
+
+// WithFields returns a logger that shares the level and output
+// of l and renders fields, merged with the fields of l, after
+// the message of every entry.
+func (l *stdLogger) WithFields(fields Fields) FieldLogger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	c := l.clone()
+	c.fields = merged
+	return c
+}
+
+// AddCallerSkip returns a logger like l that reports the caller n
+// more frames up the stack, for the helpers wrapping a logger:
+//
+//	var log = golog.InfoLogger.AddCallerSkip(1)
+//
+//	func logRequest(r *http.Request) {
+//		log.Infof("%s %s", r.Method, r.URL)
+//	}
+//
+// The callers of logRequest are reported instead of logRequest.
+func (l *stdLogger) AddCallerSkip(n int) FieldLogger {
+	c := l.clone()
+	c.callerSkip += n
+	return c
+}
+
+// clone returns a logger sharing the configuration
+// of l with counters of its own.
+func (l *stdLogger) clone() *stdLogger {
+	return &stdLogger{
+		level:      l.level,
+		out:        l.out,
+		fields:     l.fields,
+		callerSkip: l.callerSkip,
+		ctxFields:  l.ctxFields,
+		stackLevel: l.stackLevel,
+	}
+}
+
+// With is a shorthand for WithFields.
+func (l *stdLogger) With(fields Fields) FieldLogger {
+	return l.WithFields(fields)
+}
+
+// Named returns a logger like l whose name is name, appended
+// with a dot to the name of l, reported as the LoggerKey field.
+func (l *stdLogger) Named(name string) FieldLogger {
+	return l.WithFields(Fields{LoggerKey: childName(l.fields, name)})
+}
+
+// Counters returns the number of entries emitted by
+// the logger per level.
+func (l *stdLogger) Counters() map[Level]uint64 {
+	return l.counters.snapshot()
 }
 
 // Debug is a convenient function that will be use for debugging.
@@ -188,7 +872,7 @@ func Debug(v ...interface{}) {
 	if !DebugLogger.isPrint() {
 		return
 	}
-	DebugLogger.Output(stdCallDepth, fmt.Sprintln(v...))
+	DebugLogger.Output(stdCallDepth, sprintln(v))
 }
 
 // Debugf is a convenient function that accepts format string
@@ -197,7 +881,16 @@ func Debugf(format string, v ...interface{}) {
 	if !DebugLogger.isPrint() {
 		return
 	}
-	DebugLogger.Output(stdCallDepth, fmt.Sprintf(format, v...))
+	DebugLogger.outputTemplate(stdCallDepth, fmt.Sprintf(format, v...), format)
+}
+
+// Debugln is like Debug. It exists for the symmetry with the
+// Println-style APIs of the other loggers.
+func Debugln(v ...interface{}) {
+	if !DebugLogger.isPrint() {
+		return
+	}
+	DebugLogger.Output(stdCallDepth, sprintln(v))
 }
 
 // Error is a convenient function that accepts arguments v
@@ -206,7 +899,7 @@ func Error(v ...interface{}) {
 	if !ErrorLogger.isPrint() {
 		return
 	}
-	ErrorLogger.Output(stdCallDepth, fmt.Sprintln(v...))
+	ErrorLogger.Output(stdCallDepth, sprintln(v))
 }
 
 // Errorf is a convenient function that accepts format string
@@ -215,7 +908,16 @@ func Errorf(format string, v ...interface{}) {
 	if !ErrorLogger.isPrint() {
 		return
 	}
-	ErrorLogger.Output(stdCallDepth, fmt.Sprintf(format, v...))
+	ErrorLogger.outputTemplate(stdCallDepth, fmt.Sprintf(format, v...), format)
+}
+
+// Errorln is like Error. It exists for the symmetry with the
+// Println-style APIs of the other loggers.
+func Errorln(v ...interface{}) {
+	if !ErrorLogger.isPrint() {
+		return
+	}
+	ErrorLogger.Output(stdCallDepth, sprintln(v))
 }
 
 // Info is a convenient function that accepts arguments v
@@ -224,7 +926,7 @@ func Info(v ...interface{}) {
 	if !InfoLogger.isPrint() {
 		return
 	}
-	InfoLogger.Output(stdCallDepth, fmt.Sprintln(v...))
+	InfoLogger.Output(stdCallDepth, sprintln(v))
 }
 
 // Infof is a convenient function that accepts format string
@@ -233,7 +935,16 @@ func Infof(format string, v ...interface{}) {
 	if !InfoLogger.isPrint() {
 		return
 	}
-	InfoLogger.Output(stdCallDepth, fmt.Sprintf(format, v...))
+	InfoLogger.outputTemplate(stdCallDepth, fmt.Sprintf(format, v...), format)
+}
+
+// Infoln is like Info. It exists for the symmetry with the
+// Println-style APIs of the other loggers.
+func Infoln(v ...interface{}) {
+	if !InfoLogger.isPrint() {
+		return
+	}
+	InfoLogger.Output(stdCallDepth, sprintln(v))
 }
 
 // Trace is a convenient function that accepts argument v
@@ -242,7 +953,7 @@ func Trace(v ...interface{}) {
 	if !TraceLogger.isPrint() {
 		return
 	}
-	TraceLogger.Output(stdCallDepth, fmt.Sprintln(v...))
+	TraceLogger.Output(stdCallDepth, sprintln(v))
 }
 
 // Tracef is a convenient function that accepts format string
@@ -251,7 +962,16 @@ func Tracef(format string, v ...interface{}) {
 	if !TraceLogger.isPrint() {
 		return
 	}
-	TraceLogger.Output(stdCallDepth, fmt.Sprintf(format, v...))
+	TraceLogger.outputTemplate(stdCallDepth, fmt.Sprintf(format, v...), format)
+}
+
+// Traceln is like Trace. It exists for the symmetry with the
+// Println-style APIs of the other loggers.
+func Traceln(v ...interface{}) {
+	if !TraceLogger.isPrint() {
+		return
+	}
+	TraceLogger.Output(stdCallDepth, sprintln(v))
 }
 
 // Warning is a convenient function that accepts argument v
@@ -260,7 +980,7 @@ func Warning(v ...interface{}) {
 	if !WarningLogger.isPrint() {
 		return
 	}
-	WarningLogger.Output(stdCallDepth, fmt.Sprintln(v...))
+	WarningLogger.Output(stdCallDepth, sprintln(v))
 }
 
 // Warningf is a convenient function that accepts argument v and
@@ -269,7 +989,40 @@ func Warningf(format string, v ...interface{}) {
 	if !WarningLogger.isPrint() {
 		return
 	}
-	WarningLogger.Output(stdCallDepth, fmt.Sprintf(format, v...))
+	WarningLogger.outputTemplate(stdCallDepth, fmt.Sprintf(format, v...), format)
+}
+
+// Warningln is like Warning. It exists for the symmetry with the
+// Println-style APIs of the other loggers.
+func Warningln(v ...interface{}) {
+	if !WarningLogger.isPrint() {
+		return
+	}
+	WarningLogger.Output(stdCallDepth, sprintln(v))
+}
+
+// Warn is an alias of Warning.
+func Warn(v ...interface{}) {
+	if !WarningLogger.isPrint() {
+		return
+	}
+	WarningLogger.Output(stdCallDepth, sprintln(v))
+}
+
+// Warnf is an alias of Warningf.
+func Warnf(format string, v ...interface{}) {
+	if !WarningLogger.isPrint() {
+		return
+	}
+	WarningLogger.outputTemplate(stdCallDepth, fmt.Sprintf(format, v...), format)
+}
+
+// Warnln is an alias of Warningln.
+func Warnln(v ...interface{}) {
+	if !WarningLogger.isPrint() {
+		return
+	}
+	WarningLogger.Output(stdCallDepth, sprintln(v))
 }
 
 // Fatal is a convenient function that accepts argument v
@@ -278,8 +1031,8 @@ func Fatal(v ...interface{}) {
 	if !ErrorLogger.isPrint() {
 		return
 	}
-	ErrorLogger.Output(stdCallDepth, fmt.Sprint(v...))
-	os.Exit(1)
+	ErrorLogger.outputFatal(stdCallDepth, fmt.Sprint(v...), "")
+	ErrorLogger.exit()
 }
 
 // Fatalf is a convenient function that accepts format string
@@ -289,6 +1042,45 @@ func Fatalf(format string, v ...interface{}) {
 	if !ErrorLogger.isPrint() {
 		return
 	}
-	ErrorLogger.Output(stdCallDepth, fmt.Sprintf(format, v...))
-	os.Exit(1)
+	ErrorLogger.outputFatal(stdCallDepth, fmt.Sprintf(format, v...), format)
+	ErrorLogger.exit()
+}
+
+// Panic is a convenient function that logs argument v as an
+// error and then panics with the message.
+func Panic(v ...interface{}) {
+	s := fmt.Sprint(v...)
+	if ErrorLogger.isPrint() {
+		ErrorLogger.Output(stdCallDepth, s)
+	}
+	panic(s)
+}
+
+// Panicf is a convenient function that logs the formatted
+// message as an error and then panics with it.
+func Panicf(format string, v ...interface{}) {
+	s := fmt.Sprintf(format, v...)
+	if ErrorLogger.isPrint() {
+		ErrorLogger.outputTemplate(stdCallDepth, s, format)
+	}
+	panic(s)
+}
+
+// Log logs the arguments v at lvl, chosen at runtime, e.g. from
+// the status of an HTTP response. DisabledLevel logs nothing.
+func Log(lvl Level, v ...interface{}) {
+	l := packageLogger(lvl)
+	if l == nil || !l.isPrint() {
+		return
+	}
+	l.Output(stdCallDepth, sprintln(v))
+}
+
+// Logf is like Log but formats the message as fmt.Sprintf.
+func Logf(lvl Level, format string, v ...interface{}) {
+	l := packageLogger(lvl)
+	if l == nil || !l.isPrint() {
+		return
+	}
+	l.outputTemplate(stdCallDepth, fmt.Sprintf(format, v...), format)
 }
@@ -4,9 +4,15 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // Requirements:
@@ -38,10 +44,7 @@ func TestLogger_Print(t *testing.T) {
 		}
 		for _, c := range containsCases {
 			t.Run(fmt.Sprintf("%s:%s", c.lvl, c.name), func(t *testing.T) {
-				l := &stdLogger{
-					level: c.lvl,
-					l:     log.New(c.out, c.lvl.String(), log.Lshortfile),
-				}
+				l := newStdLogger(c.lvl, c.out, log.Lshortfile)
 				SetLevel(c.setLvl)
 				l.Print(c.input, c.input)
 				got := c.out.String()
@@ -52,10 +55,7 @@ func TestLogger_Print(t *testing.T) {
 	t.Run("Empty value", func(t *testing.T) {
 		t.Run("when set level is info level, error logs should not print", func(t *testing.T) {
 			out := &bytes.Buffer{}
-			l := &stdLogger{
-				level: ErrorLevel,
-				l:     log.New(out, ErrorLevel.String(), log.LstdFlags|log.Lshortfile),
-			}
+			l := newStdLogger(ErrorLevel, out, log.LstdFlags|log.Lshortfile)
 			SetLevel(InfoLevel)
 			l.Print("Hello world")
 			want := "Hello world"
@@ -114,7 +114,7 @@ func TestWarningLoggerPrintln(t *testing.T) {
 		WarningLogger.SetOutput(out)
 		WarningLogger.Println("Hello World")
 		assert.Contains(t, out.String(), "Hello World")
-		assert.True(t, strings.HasPrefix(out.String(), WarningLevel.String()))
+		assert.True(t, strings.HasPrefix(out.String(), WarningLevel.prefix()))
 	})
 }
 func TestLogger_Printf(t *testing.T) {
@@ -143,10 +143,7 @@ func TestLogger_Printf(t *testing.T) {
 	}
 	for _, c := range cases {
 		t.Run(fmt.Sprintf("%s:%s", c.lvl, c.name), func(t *testing.T) {
-			l := &stdLogger{
-				level: c.lvl,
-				l:     log.New(c.out, c.lvl.String(), log.Lshortfile),
-			}
+			l := newStdLogger(c.lvl, c.out, log.Lshortfile)
 			SetLevel(c.setLvl)
 			l.Printf(c.format, c.input)
 			got := c.out.String()
@@ -211,3 +208,399 @@ func TestDisable(t *testing.T) {
 	Info("Hello Info Log!")
 	Error("Hello Info Log in Debug Level!")
 }
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+// Run with -race to verify the concurrency guarantees.
+func TestConcurrentConfiguration(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	outs := []*syncBuffer{{}, {}}
+	l := newStdLogger(InfoLevel, outs[0], log.LstdFlags)
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			SetLevel(Level(i % 3))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			l.SetOutput(outs[i%2])
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			l.Println("Hello World")
+		}
+	}()
+	wg.Wait()
+
+	var lines int
+	for _, out := range outs {
+		for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			lines++
+			assert.True(t, strings.HasPrefix(line, InfoLevel.prefix()), line)
+			assert.True(t, strings.HasSuffix(line, "Hello World"), line)
+		}
+	}
+	assert.True(t, lines <= 500)
+}
+
+func TestStdLogger_WithFields(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &bytes.Buffer{}
+	parent := newStdLogger(InfoLevel, out, 0)
+
+	t.Run("fields are rendered after the message", func(t *testing.T) {
+		out.Reset()
+		parent.WithFields(Fields{"request_id": "abc", "user": 42}).Println("Hello World")
+		assert.Equal(t, "INFO: Hello World request_id=abc user=42\n", out.String())
+	})
+	t.Run("fields are merged with the parent fields", func(t *testing.T) {
+		out.Reset()
+		child := parent.WithFields(Fields{"request_id": "abc", "user": 1})
+		child.WithFields(Fields{"user": 2}).Printf("Hello %s", "World")
+		assert.Equal(t, "INFO: Hello World request_id=abc user=2\n", out.String())
+	})
+	t.Run("the parent is not modified", func(t *testing.T) {
+		out.Reset()
+		parent.WithFields(Fields{"request_id": "abc"})
+		parent.Print("Hello World")
+		assert.Equal(t, "INFO: Hello World\n", out.String())
+	})
+	t.Run("fields are suppressed with the entry", func(t *testing.T) {
+		out.Reset()
+		SetLevel(ErrorLevel)
+		parent.WithFields(Fields{"request_id": "abc"}).Print("Hello World")
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestParseLevel(t *testing.T) {
+	cases := []struct {
+		input string
+		want  Level
+	}{
+		{input: "debug", want: DebugLevel},
+		{input: "TRACE", want: TraceLevel},
+		{input: "Info", want: InfoLevel},
+		{input: "warning", want: WarningLevel},
+		{input: "warn", want: WarningLevel},
+		{input: " error ", want: ErrorLevel},
+		{input: "disabled", want: DisabledLevel},
+	}
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			got, err := ParseLevel(c.input)
+			assert.NoError(t, err)
+			assert.Equal(t, c.want, got)
+		})
+	}
+	t.Run("unknown level", func(t *testing.T) {
+		_, err := ParseLevel("verbose")
+		assert.EqualError(t, err, `golog: unknown level "verbose"`)
+	})
+}
+
+func TestNewStdLogger(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var out1, out2 bytes.Buffer
+	l1 := NewStdLogger(InfoLevel)
+	l2 := NewStdLogger(InfoLevel)
+	assert.False(t, l1 == l2)
+	assert.False(t, l1 == Logger(InfoLogger))
+
+	l1.SetOutput(&out1)
+	l2.SetOutput(&out2)
+	l1.Print("first")
+	l2.Print("second")
+	assert.Contains(t, out1.String(), "first")
+	assert.NotContains(t, out1.String(), "second")
+	assert.Contains(t, out2.String(), "second")
+	assert.Equal(t, os.Stdout, InfoLogger.out.w)
+}
+
+func TestSetLevelOutput(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var stdout, stderr bytes.Buffer
+	SetOutput(&stdout)
+	SetLevelOutput(ErrorLevel, &stderr)
+
+	Info("started")
+	Error("failed")
+	assert.Contains(t, stdout.String(), "started")
+	assert.NotContains(t, stdout.String(), "failed")
+	assert.Contains(t, stderr.String(), "failed")
+	assert.NotContains(t, stderr.String(), "started")
+	assert.Equal(t, &stderr, LevelOutput(ErrorLevel))
+	assert.Nil(t, LevelOutput(DisabledLevel))
+}
+
+func BenchmarkSuppressed(b *testing.B) {
+	defer SetLevel(InfoLevel)
+	SetLevel(ErrorLevel)
+	l := newStdLogger(InfoLevel, ioutil.Discard, 0)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			l.Print("suppressed")
+		}
+	})
+}
+
+func TestEmit(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+
+	assert.False(t, Enabled(DebugLevel))
+	assert.True(t, Enabled(ErrorLevel))
+	assert.False(t, Enabled(DisabledLevel))
+
+	Emit(&Entry{Level: DebugLevel, Message: "hidden"})
+	Emit(&Entry{Level: DisabledLevel, Message: "hidden"})
+	assert.Empty(t, out.String())
+
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local)
+	Emit(&Entry{Time: ts, Level: WarningLevel, Message: "from adapter\n", Fields: Fields{"k": "v"}})
+	assert.Equal(t, "WARNING: 2020/01/02 03:04:05 from adapter k=v\n", out.String())
+}
+
+func TestPanic(t *testing.T) {
+	defer ErrorLogger.SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	ErrorLogger.SetOutput(out)
+
+	assert.PanicsWithValue(t, "invalid state 42", func() { Panicf("invalid state %d", 42) })
+	assert.Contains(t, out.String(), "ERROR: ")
+	assert.Contains(t, out.String(), "golog_test.go:")
+	assert.Contains(t, out.String(), "invalid state 42\n")
+
+	t.Run("loggers log at error severity", func(t *testing.T) {
+		out := &syncBuffer{}
+		l := newStdLogger(InfoLevel, out, log.Lshortfile)
+		assert.PanicsWithValue(t, "boom", func() { l.Panic("boom") })
+		assert.Regexp(t, `^ERROR: golog_test.go:\d+: boom\n$`, out.String())
+		assert.Equal(t, uint64(1), l.Counters()[ErrorLevel])
+	})
+	t.Run("panics when errors are disabled", func(t *testing.T) {
+		out.Reset()
+		SetLevel(DisabledLevel)
+		assert.PanicsWithValue(t, "quiet", func() { Panic("quiet") })
+		assert.Empty(t, out.String())
+		SetLevel(InfoLevel)
+	})
+	t.Run("logrus", func(t *testing.T) {
+		out := &syncBuffer{}
+		l := NewLogrusLogger(InfoLevel)
+		l.SetOutput(out)
+		l.SetFormatter(&TextFormatter{})
+		assert.PanicsWithValue(t, "boom 1", func() { l.Panicf("boom %d", 1) })
+		assert.Equal(t, "ERROR: boom 1\n", out.String())
+	})
+}
+
+func TestAliases(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(DebugLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+	defer func() {
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+
+	Debugln("a", 1)
+	Traceln("b", 2)
+	Infoln("c", 3)
+	Warningln("d", 4)
+	Warn("e", 5)
+	Warnf("f %d", 6)
+	Warnln("g", 7)
+	Errorln("h", 8)
+	assert.Regexp(t, `^DEBUG: golog_test.go:\d+: a 1
+TRACE: golog_test.go:\d+: b 2
+INFO: golog_test.go:\d+: c 3
+WARNING: golog_test.go:\d+: d 4
+WARNING: golog_test.go:\d+: e 5
+WARNING: golog_test.go:\d+: f 6
+WARNING: golog_test.go:\d+: g 7
+ERROR: golog_test.go:\d+: h 8
+$`, out.String())
+}
+
+func TestLeveledMethods(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	loggers := map[string]func(w *syncBuffer) FieldLogger{
+		"std": func(w *syncBuffer) FieldLogger { return newStdLogger(InfoLevel, w, 0) },
+		"logrus": func(w *syncBuffer) FieldLogger {
+			l := NewLogrusLogger(InfoLevel)
+			l.SetOutput(w)
+			l.SetFormatter(&TextFormatter{})
+			return l
+		},
+	}
+	for name, newLogger := range loggers {
+		t.Run(name, func(t *testing.T) {
+			out := &syncBuffer{}
+			l := newLogger(out)
+			l.Debug("hidden")
+			l.Tracef("hidden %d", 1)
+			l.Info("started")
+			l.Warnf("slow %dms", 250)
+			l.Error("failed")
+			l.Errorf("failed %s", "again")
+			assert.Equal(t, "INFO: started\n"+
+				"WARNING: slow 250ms\n"+
+				"ERROR: failed\n"+
+				"ERROR: failed again\n", out.String())
+		})
+	}
+}
+
+func TestLog(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+	defer func() {
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+
+	statusLevel := func(code int) Level {
+		if code >= 500 {
+			return ErrorLevel
+		}
+		return InfoLevel
+	}
+	Log(statusLevel(200), "GET /users", 200)
+	Logf(statusLevel(503), "GET /orders %d", 503)
+	Log(DebugLevel, "hidden")
+	Log(DisabledLevel, "hidden")
+	assert.Regexp(t, `^INFO: golog_test.go:\d+: GET /users 200
+ERROR: golog_test.go:\d+: GET /orders 503
+$`, out.String())
+
+	loggers := map[string]FieldLogger{
+		"std":    newStdLogger(InfoLevel, out, 0),
+		"logrus": NewLogrusLogger(InfoLevel),
+	}
+	loggers["logrus"].SetOutput(out)
+	loggers["logrus"].SetFormatter(&TextFormatter{})
+	for name, l := range loggers {
+		t.Run(name, func(t *testing.T) {
+			out.Reset()
+			l.Log(WarningLevel, "slow")
+			l.Logf(ErrorLevel, "failed %d", 1)
+			l.Log(DebugLevel, "hidden")
+			l.Log(DisabledLevel, "hidden")
+			assert.Equal(t, "WARNING: slow\nERROR: failed 1\n", out.String())
+		})
+	}
+}
+
+// logVia logs msg with l from a helper, as the
+// wrappers of the applications do.
+func logVia(l FieldLogger, msg string) {
+	l.Infof("%s", msg)
+}
+
+func TestLogger_AddCallerSkip(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	l := newStdLogger(InfoLevel, out, log.Lshortfile)
+	at := func() string {
+		_, file, line, _ := runtime.Caller(1)
+		return fmt.Sprintf("%s:%d", filepath.Base(file), line+1)
+	}
+
+	t.Run("direct calls report the call site", func(t *testing.T) {
+		out.Reset()
+		want := at()
+		l.Print("print")
+		assert.Equal(t, "INFO: "+want+": print\n", out.String())
+
+		out.Reset()
+		want = at()
+		l.Log(WarningLevel, "log")
+		assert.Equal(t, "WARNING: "+want+": log\n", out.String())
+	})
+	t.Run("wrappers skip their frame", func(t *testing.T) {
+		out.Reset()
+		want := at()
+		logVia(l.AddCallerSkip(1), "wrapped")
+		assert.Equal(t, "INFO: "+want+": wrapped\n", out.String())
+
+		out.Reset()
+		want = at()
+		logVia(l.WithFields(Fields{"k": "v"}).AddCallerSkip(1).Named("child"), "derived")
+		assert.Equal(t, "INFO: "+want+": derived k=v logger=child\n", out.String())
+
+		out.Reset()
+		logVia(l, "unskipped")
+		assert.Contains(t, out.String(), "golog_test.go:")
+		assert.NotContains(t, out.String(), want)
+	})
+	t.Run("package loggers", func(t *testing.T) {
+		defer SetOutput(os.Stdout)
+		defer InfoLogger.SetFormatter(&TextFormatter{Flags: defaultFlags(InfoLevel)})
+		SetOutput(out)
+		InfoLogger.SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+
+		out.Reset()
+		want := at()
+		Info("package")
+		assert.Equal(t, "INFO: "+want+": package\n", out.String())
+
+		out.Reset()
+		want = at()
+		logVia(InfoLogger.AddCallerSkip(1), "package wrapped")
+		assert.Equal(t, "INFO: "+want+": package wrapped\n", out.String())
+	})
+}
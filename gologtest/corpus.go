@@ -0,0 +1,138 @@
+package gologtest
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/jayvib/golog"
+)
+
+// UpdateEnv is the environment variable that makes CheckFormatter
+// rewrite the golden files instead of comparing with them:
+//
+//	GOLOGTEST_UPDATE=1 go test ./...
+const UpdateEnv = "GOLOGTEST_UPDATE"
+
+// CorpusEntry is a representative entry of the corpus.
+type CorpusEntry struct {
+	// Name identifies the entry and names its golden file.
+	Name  string
+	Entry golog.Entry
+}
+
+// corpusTime is the time of every entry of the corpus.
+var corpusTime = time.Date(2020, time.March, 4, 5, 6, 7, 891011000, time.UTC)
+
+// Corpus returns the entries used to detect the changes of
+// a format: the levels, the field types, the escaping of the
+// messages and keys, the message templates, the keys reserved
+// by the formatters and the callers. Every call returns new
+// entries that can be modified freely.
+func Corpus() []CorpusEntry {
+	return []CorpusEntry{
+		{Name: "plain", Entry: golog.Entry{
+			Time:    corpusTime,
+			Level:   golog.InfoLevel,
+			Message: "Hello World",
+		}},
+		{Name: "empty", Entry: golog.Entry{
+			Time:  corpusTime,
+			Level: golog.DebugLevel,
+		}},
+		{Name: "fields", Entry: golog.Entry{
+			Time:    corpusTime,
+			Level:   golog.WarningLevel,
+			Message: "disk almost full",
+			Fields: golog.Fields{
+				"string":  "sda1",
+				"int":     42,
+				"int64":   int64(-7),
+				"float":   0.93,
+				"bool":    true,
+				"nil":     nil,
+				"error":   errors.New("no space left"),
+				"strings": []string{"a", "b"},
+				"nested":  golog.Fields{"path": "/var", "used": 99},
+			},
+		}},
+		{Name: "escaping", Entry: golog.Entry{
+			Time:    corpusTime,
+			Level:   golog.ErrorLevel,
+			Message: "quote \" backslash \\ tab \t newline \n é ✓ \x01",
+			Fields: golog.Fields{
+				"with space": "a b",
+				"equals":     "k=v",
+				"unicode":    "héllo",
+				"control":    "\x00\x1f",
+			},
+		}},
+		{Name: "template", Entry: golog.Entry{
+			Time:     corpusTime,
+			Level:    golog.InfoLevel,
+			Message:  "user alice logged in",
+			Template: "user %s logged in",
+			Fields:   golog.Fields{"user": "alice"},
+		}},
+		{Name: "reserved_keys", Entry: golog.Entry{
+			Time:    corpusTime,
+			Level:   golog.TraceLevel,
+			Message: "reserved",
+			Fields: golog.Fields{
+				"time":   "yesterday",
+				"level":  "custom",
+				"msg":    "shadowed",
+				"caller": "elsewhere",
+			},
+		}},
+		{Name: "caller", Entry: golog.Entry{
+			Time:    corpusTime,
+			Level:   golog.ErrorLevel,
+			Message: "failed",
+			Caller: &runtime.Frame{
+				File:     "/src/app/server/handler.go",
+				Line:     42,
+				Function: "app/server.(*Handler).ServeHTTP",
+			},
+		}},
+	}
+}
+
+// CheckFormatter formats every entry of the corpus with f and
+// compares the output with the golden files <name>.golden in dir.
+// The golden files are written instead when UpdateEnv is set.
+func CheckFormatter(tb testing.TB, f golog.Formatter, dir string) {
+	tb.Helper()
+	update := os.Getenv(UpdateEnv) != ""
+	for _, c := range Corpus() {
+		e := c.Entry
+		got, err := f.Format(&e)
+		if err != nil {
+			tb.Errorf("gologtest: %s: format: %v", c.Name, err)
+			continue
+		}
+		path := filepath.Join(dir, c.Name+".golden")
+		if update {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				tb.Fatalf("gologtest: %v", err)
+			}
+			if err := ioutil.WriteFile(path, got, 0644); err != nil {
+				tb.Fatalf("gologtest: %v", err)
+			}
+			continue
+		}
+		want, err := ioutil.ReadFile(path)
+		if err != nil {
+			tb.Errorf("gologtest: %s: %v", c.Name, err)
+			continue
+		}
+		if !bytes.Equal(got, want) {
+			tb.Errorf("gologtest: %s: output changed\ngot:  %q\nwant: %q", c.Name, got, want)
+		}
+	}
+}
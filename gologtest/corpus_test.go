@@ -0,0 +1,81 @@
+package gologtest
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/jayvib/golog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// skipUpdate skips the tests of the comparisons when the
+// golden files are being written.
+func skipUpdate(t *testing.T) {
+	if os.Getenv(UpdateEnv) != "" {
+		t.Skip("writing the golden files")
+	}
+}
+
+// pidFormatter replaces the process ID written by the syslog
+// formatter, so its output doesn't change between the runs.
+type pidFormatter struct {
+	golog.Formatter
+}
+
+func (f pidFormatter) Format(e *golog.Entry) ([]byte, error) {
+	b, err := f.Formatter.Format(e)
+	pid := " " + strconv.Itoa(os.Getpid()) + " "
+	return bytes.Replace(b, []byte(pid), []byte(" PID "), 1), err
+}
+
+func TestCheckFormatter(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		CheckFormatter(t, &golog.TextFormatter{Flags: log.LstdFlags | log.Lmicroseconds | log.LUTC | log.Lshortfile}, "testdata/text")
+	})
+	t.Run("json", func(t *testing.T) {
+		CheckFormatter(t, &golog.JSONFormatter{}, "testdata/json")
+	})
+	t.Run("logfmt", func(t *testing.T) {
+		CheckFormatter(t, &golog.LogfmtFormatter{}, "testdata/logfmt")
+	})
+	t.Run("ecs", func(t *testing.T) {
+		CheckFormatter(t, &golog.ECSFormatter{}, "testdata/ecs")
+	})
+	t.Run("gcp", func(t *testing.T) {
+		CheckFormatter(t, &golog.GCPFormatter{}, "testdata/gcp")
+	})
+	t.Run("proto", func(t *testing.T) {
+		CheckFormatter(t, golog.ProtoFormatter{}, "testdata/proto")
+	})
+	t.Run("console", func(t *testing.T) {
+		CheckFormatter(t, &golog.ConsoleFormatter{}, "testdata/console")
+	})
+	t.Run("syslog", func(t *testing.T) {
+		f := &golog.SyslogFormatter{Facility: golog.FacilityLocal0, AppName: "app", Hostname: "host"}
+		CheckFormatter(t, pidFormatter{f}, "testdata/syslog")
+	})
+	t.Run("template", func(t *testing.T) {
+		f, err := golog.NewTemplateFormatter(`{{date "15:04:05.000" .Time}} {{colorize .Level (level .Level | pad 5)}} {{.Message | truncate 20}} {{fields .Fields}} {{caller .Caller}}`, nil)
+		require.NoError(t, err)
+		CheckFormatter(t, f, "testdata/template")
+	})
+	t.Run("cli", func(t *testing.T) {
+		CheckFormatter(t, &golog.CLIFormatter{}, "testdata/cli")
+	})
+	t.Run("reports the changed outputs", func(t *testing.T) {
+		skipUpdate(t)
+		tb := &fakeTB{TB: t}
+		CheckFormatter(tb, &golog.TextFormatter{}, "testdata/json")
+		assert.Len(t, tb.errors, len(Corpus()))
+	})
+	t.Run("reports the missing golden files", func(t *testing.T) {
+		skipUpdate(t)
+		tb := &fakeTB{TB: t}
+		CheckFormatter(tb, &golog.JSONFormatter{}, "testdata/missing")
+		assert.Len(t, tb.errors, len(Corpus()))
+	})
+}
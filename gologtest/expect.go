@@ -0,0 +1,36 @@
+package gologtest
+
+import (
+	"testing"
+
+	"github.com/jayvib/golog"
+)
+
+// ExpectNoErrors fails the test if any Error or Fatal entry is
+// emitted by golog between the call and the end of the test.
+//
+// The check relies on the global golog counters, so entries
+// emitted by tests running in parallel are also taken into account.
+// The errors are not detected when the counters are reset by
+// ResetCounters during the test.
+func ExpectNoErrors(tb testing.TB) {
+	tb.Helper()
+	before := errorCount()
+	tb.Cleanup(func() {
+		if after := errorCount(); after > before {
+			tb.Errorf("gologtest: %d error entries were logged during the test", after-before)
+		}
+	})
+}
+
+// errorCount returns the number of entries of ErrorLevel and above
+// counted by golog, which counts the Fatal entries as ErrorLevel.
+func errorCount() uint64 {
+	var n uint64
+	for lvl, c := range golog.Counters() {
+		if lvl >= golog.ErrorLevel {
+			n += c
+		}
+	}
+	return n
+}
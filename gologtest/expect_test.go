@@ -0,0 +1,84 @@
+package gologtest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/jayvib/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTB records the failures and the cleanup functions
+// instead of acting on them.
+type fakeTB struct {
+	testing.TB
+	errors   []string
+	logs     []string
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Log(args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprint(args...))
+}
+
+func (f *fakeTB) Cleanup(fn func()) {
+	f.cleanups = append(f.cleanups, fn)
+}
+
+func (f *fakeTB) cleanup() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func TestExpectNoErrors(t *testing.T) {
+	golog.ErrorLogger.SetOutput(ioutil.Discard)
+	golog.SetLevel(golog.InfoLevel)
+
+	t.Run("passes when no error was logged", func(t *testing.T) {
+		tb := &fakeTB{TB: t}
+		ExpectNoErrors(tb)
+		golog.Info("Hello World")
+		tb.cleanup()
+		assert.Empty(t, tb.errors)
+	})
+	t.Run("fails when an error was logged", func(t *testing.T) {
+		tb := &fakeTB{TB: t}
+		ExpectNoErrors(tb)
+		golog.Error("Something went wrong")
+		tb.cleanup()
+		assert.Len(t, tb.errors, 1)
+	})
+	t.Run("fails when a fatal entry was logged", func(t *testing.T) {
+		l := golog.NewStdLogger(golog.InfoLevel)
+		l.SetOutput(ioutil.Discard)
+		l.(golog.ExitFuncSetter).SetExitFunc(func(int) {})
+		tb := &fakeTB{TB: t}
+		ExpectNoErrors(tb)
+		l.Fatal("Something went wrong")
+		tb.cleanup()
+		assert.Len(t, tb.errors, 1)
+	})
+	t.Run("counters reset during the test", func(t *testing.T) {
+		golog.Error("Something went wrong")
+		tb := &fakeTB{TB: t}
+		ExpectNoErrors(tb)
+		golog.ResetCounters()
+		tb.cleanup()
+		assert.Empty(t, tb.errors)
+	})
+	t.Run("errors before the call are ignored", func(t *testing.T) {
+		golog.Error("Something went wrong")
+		tb := &fakeTB{TB: t}
+		ExpectNoErrors(tb)
+		tb.cleanup()
+		assert.Empty(t, tb.errors)
+	})
+}
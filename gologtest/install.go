@@ -0,0 +1,50 @@
+package gologtest
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jayvib/golog"
+)
+
+// levels are the levels of the golog package level loggers.
+var levels = []golog.Level{
+	golog.DebugLevel,
+	golog.TraceLevel,
+	golog.InfoLevel,
+	golog.WarningLevel,
+	golog.ErrorLevel,
+}
+
+// Install redirects the output of the golog package level loggers
+// to tb.Log until the end of the test, so the application logs are
+// shown interleaved with the failures in the go test output. The
+// previous outputs are restored on cleanup.
+//
+// The outputs are global, so tests calling Install must not run
+// in parallel.
+func Install(tb testing.TB) {
+	tb.Helper()
+	prev := make(map[golog.Level]io.Writer, len(levels))
+	for _, lvl := range levels {
+		prev[lvl] = golog.LevelOutput(lvl)
+	}
+	golog.SetOutput(tbWriter{tb})
+	tb.Cleanup(func() {
+		for lvl, w := range prev {
+			golog.SetLevelOutput(lvl, w)
+		}
+	})
+}
+
+// tbWriter writes every entry with tb.Log.
+type tbWriter struct {
+	tb testing.TB
+}
+
+func (w tbWriter) Write(p []byte) (int, error) {
+	w.tb.Helper()
+	w.tb.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
@@ -0,0 +1,32 @@
+package gologtest
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/jayvib/golog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstall(t *testing.T) {
+	defer golog.SetOutput(os.Stdout)
+	golog.SetLevel(golog.InfoLevel)
+	var stderr bytes.Buffer
+	golog.SetLevelOutput(golog.ErrorLevel, &stderr)
+
+	tb := &fakeTB{TB: t}
+	Install(tb)
+	golog.Info("Hello World")
+	golog.Error("Something went wrong")
+	tb.cleanup()
+
+	if assert.Len(t, tb.logs, 2) {
+		assert.Contains(t, tb.logs[0], "Hello World")
+		assert.Contains(t, tb.logs[1], "Something went wrong")
+		assert.NotContains(t, tb.logs[0], "\n")
+	}
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, &stderr, golog.LevelOutput(golog.ErrorLevel))
+	assert.Equal(t, os.Stdout, golog.LevelOutput(golog.InfoLevel))
+}
@@ -0,0 +1,189 @@
+// Package gologtest provides helpers for testing golog and
+// the code that uses it.
+package gologtest
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jayvib/golog"
+)
+
+// StressConfig describes the load that Stress will generate.
+type StressConfig struct {
+	// Logger is the logger under test.
+	Logger golog.Logger
+	// Goroutines is the number of concurrent writers. Defaults to 1.
+	Goroutines int
+	// Rate is the number of entries per second each goroutine
+	// will log. Zero means as fast as possible.
+	Rate int
+	// Duration is how long the load will run. Defaults to one second.
+	Duration time.Duration
+	// PayloadSize is the size in bytes of every message.
+	PayloadSize int
+	// Output is an optional destination. When set, the Logger output
+	// is replaced with a wrapper of Output so that Stress can
+	// count the delivered and suppressed entries. The
+	// previous output is restored once the run is over when the
+	// Logger has an Out method returning it, as the loggers of
+	// golog do.
+	Output io.Writer
+}
+
+// StressResult is the report of a Stress run.
+type StressResult struct {
+	// Entries is the number of entries that were logged.
+	Entries uint64
+	// Delivered is the number of entries that reached the
+	// Output. It is zero when no Output is configured.
+	Delivered uint64
+	// Drops is the number of entries discarded during the run, as
+	// counted by golog.Drops: the failed writes and the entries
+	// discarded by the sinks, such as an AsyncWriter. golog.Drops
+	// counts the drops of every logger of the process.
+	Drops uint64
+	// Suppressed is the number of entries that did not reach the
+	// Output and were not dropped, i.e. the ones discarded by the
+	// levels, the samplers or the mute rules. It is zero when no
+	// Output is configured.
+	Suppressed uint64
+	// Elapsed is the actual duration of the run.
+	Elapsed time.Duration
+	// Throughput is the number of entries logged per second.
+	Throughput float64
+	// Allocs is the number of heap allocations during the run.
+	Allocs uint64
+	// AllocBytes is the number of bytes allocated during the run.
+	AllocBytes uint64
+}
+
+// AllocsPerEntry returns the average heap allocations per entry.
+func (r StressResult) AllocsPerEntry() float64 {
+	if r.Entries == 0 {
+		return 0
+	}
+	return float64(r.Allocs) / float64(r.Entries)
+}
+
+// ErrNilLogger is returned by Stress when the config has no logger.
+var ErrNilLogger = errors.New("gologtest: stress config has no logger")
+
+// Stress drives the configured logger with cfg.Goroutines concurrent
+// writers for cfg.Duration and reports the throughput, allocations
+// and drops.
+func Stress(cfg StressConfig) (StressResult, error) {
+	if cfg.Logger == nil {
+		return StressResult{}, ErrNilLogger
+	}
+	if cfg.Goroutines <= 0 {
+		cfg.Goroutines = 1
+	}
+	if cfg.Duration <= 0 {
+		cfg.Duration = time.Second
+	}
+
+	var counter *lineCounter
+	if cfg.Output != nil {
+		counter = &lineCounter{w: cfg.Output}
+		if o, ok := cfg.Logger.(outputter); ok {
+			defer cfg.Logger.SetOutput(o.Out())
+		}
+		cfg.Logger.SetOutput(counter)
+	}
+
+	payload := strings.Repeat("x", cfg.PayloadSize)
+	var entries uint64
+	var wg sync.WaitGroup
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	drops := golog.Drops()
+	start := time.Now()
+	deadline := start.Add(cfg.Duration)
+
+	for i := 0; i < cfg.Goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var tick <-chan time.Time
+			if cfg.Rate > 0 {
+				// The rates above one entry per nanosecond
+				// tick every nanosecond.
+				interval := time.Second / time.Duration(cfg.Rate)
+				if interval <= 0 {
+					interval = time.Nanosecond
+				}
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				tick = ticker.C
+			}
+			for time.Now().Before(deadline) {
+				if tick != nil {
+					<-tick
+				}
+				cfg.Logger.Println(payload)
+				atomic.AddUint64(&entries, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	res := StressResult{
+		Entries:    entries,
+		Elapsed:    elapsed,
+		Throughput: float64(entries) / elapsed.Seconds(),
+		Allocs:     after.Mallocs - before.Mallocs,
+		AllocBytes: after.TotalAlloc - before.TotalAlloc,
+	}
+	if d := golog.Drops(); d > drops {
+		res.Drops = d - drops
+	}
+	if counter != nil {
+		res.Delivered = counter.count()
+		// The failed writes are neither delivered nor suppressed.
+		if res.Entries > res.Delivered+res.Drops {
+			res.Suppressed = res.Entries - res.Delivered - res.Drops
+		}
+	}
+	return res, nil
+}
+
+// outputter is implemented by the loggers returning their output.
+type outputter interface {
+	Out() io.Writer
+}
+
+// lineCounter counts the lines that were written to w.
+type lineCounter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	lines uint64
+}
+
+func (c *lineCounter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, err := c.w.Write(p)
+	for _, b := range p[:n] {
+		if b == '\n' {
+			c.lines++
+		}
+	}
+	return n, err
+}
+
+func (c *lineCounter) count() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lines
+}
@@ -0,0 +1,101 @@
+package gologtest
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/jayvib/golog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// errWriter fails every write.
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("unavailable")
+}
+
+func TestStress(t *testing.T) {
+	t.Run("reports the delivered entries", func(t *testing.T) {
+		golog.SetLevel(golog.InfoLevel)
+		res, err := Stress(StressConfig{
+			Logger:      golog.NewStdLogger(golog.InfoLevel),
+			Goroutines:  4,
+			Duration:    50 * time.Millisecond,
+			PayloadSize: 64,
+			Output:      ioutil.Discard,
+		})
+		require.NoError(t, err)
+		assert.NotZero(t, res.Entries)
+		assert.Equal(t, res.Entries, res.Delivered)
+		assert.Zero(t, res.Drops)
+		assert.NotZero(t, res.Throughput)
+	})
+	t.Run("suppressed entries are not counted as drops", func(t *testing.T) {
+		golog.SetLevel(golog.ErrorLevel)
+		defer golog.SetLevel(golog.InfoLevel)
+		res, err := Stress(StressConfig{
+			Logger:   golog.NewStdLogger(golog.DebugLevel),
+			Duration: 20 * time.Millisecond,
+			Output:   ioutil.Discard,
+		})
+		require.NoError(t, err)
+		assert.NotZero(t, res.Entries)
+		assert.Equal(t, res.Entries, res.Suppressed)
+		assert.Zero(t, res.Drops)
+	})
+	t.Run("failed writes are counted as drops", func(t *testing.T) {
+		res, err := Stress(StressConfig{
+			Logger:   golog.NewStdLogger(golog.InfoLevel),
+			Duration: 10 * time.Millisecond,
+			Output:   errWriter{},
+		})
+		require.NoError(t, err)
+		assert.NotZero(t, res.Entries)
+		assert.Zero(t, res.Delivered)
+		assert.Equal(t, res.Entries, res.Drops)
+		assert.Zero(t, res.Suppressed)
+	})
+	t.Run("rate limits the goroutines", func(t *testing.T) {
+		res, err := Stress(StressConfig{
+			Logger:   golog.NewStdLogger(golog.InfoLevel),
+			Rate:     100,
+			Duration: 100 * time.Millisecond,
+			Output:   ioutil.Discard,
+		})
+		require.NoError(t, err)
+		assert.True(t, res.Entries <= 12, "got %d entries", res.Entries)
+	})
+	t.Run("rates above a nanosecond", func(t *testing.T) {
+		res, err := Stress(StressConfig{
+			Logger:   golog.NewStdLogger(golog.InfoLevel),
+			Rate:     2e9,
+			Duration: 10 * time.Millisecond,
+			Output:   ioutil.Discard,
+		})
+		require.NoError(t, err)
+		assert.NotZero(t, res.Entries)
+	})
+	t.Run("restores the output", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := golog.NewStdLogger(golog.InfoLevel)
+		l.SetOutput(&buf)
+		_, err := Stress(StressConfig{
+			Logger:   l,
+			Duration: 10 * time.Millisecond,
+			Output:   ioutil.Discard,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &buf, l.(outputter).Out())
+		l.Info("after")
+		assert.Contains(t, buf.String(), "after")
+	})
+	t.Run("nil logger", func(t *testing.T) {
+		_, err := Stress(StressConfig{})
+		assert.Equal(t, ErrNilLogger, err)
+	})
+}
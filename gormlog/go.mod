@@ -0,0 +1,11 @@
+module github.com/jayvib/golog/gormlog
+
+go 1.14
+
+require (
+	github.com/jayvib/golog v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.7.0
+	gorm.io/gorm v1.22.5
+)
+
+replace github.com/jayvib/golog => ../
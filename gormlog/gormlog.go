@@ -0,0 +1,151 @@
+// Package gormlog provides a GORM logger backed by golog, so the
+// output of the ORM respects golog.SetLevel and shares the outputs,
+// hooks and processors of the golog package level loggers:
+//
+//	db, err := gorm.Open(dialector, &gorm.Config{
+//		Logger: gormlog.New(200 * time.Millisecond),
+//	})
+//
+// The SQL statements are logged at the Trace level, the slow ones at
+// the Warning level and the failed ones at the Error level.
+package gormlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jayvib/golog"
+	"gorm.io/gorm/logger"
+)
+
+// The fields of the entries of the SQL statements.
+const (
+	SQLKey     = "sql"
+	RowsKey    = "sql.rows"
+	ElapsedKey = "sql.elapsed"
+)
+
+// Logger is a logger.Interface writing the entries with golog.Emit.
+type Logger struct {
+	// SlowThreshold is the duration above which a statement is
+	// logged at the Warning level. Zero disables it.
+	SlowThreshold time.Duration
+	// IgnoreRecordNotFound keeps the statements failing with
+	// logger.ErrRecordNotFound at the Trace level.
+	IgnoreRecordNotFound bool
+
+	mode logger.LogLevel
+}
+
+// New returns a Logger with the slow threshold slow.
+func New(slow time.Duration) *Logger {
+	return &Logger{SlowThreshold: slow, mode: logger.Info}
+}
+
+// LogMode implements the logger.Interface interface. The level of
+// GORM only narrows the entries allowed by the global golog level,
+// e.g. logger.Warn drops the statements which are neither slow
+// nor failed, and logger.Silent drops everything.
+func (l *Logger) LogMode(mode logger.LogLevel) logger.Interface {
+	c := *l
+	c.mode = mode
+	return &c
+}
+
+// Info implements the logger.Interface interface.
+func (l *Logger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.allows(logger.Info) {
+		emit(ctx, golog.InfoLevel, fmt.Sprintf(msg, args...), nil)
+	}
+}
+
+// Warn implements the logger.Interface interface.
+func (l *Logger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.allows(logger.Warn) {
+		emit(ctx, golog.WarningLevel, fmt.Sprintf(msg, args...), nil)
+	}
+}
+
+// Error implements the logger.Interface interface.
+func (l *Logger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.allows(logger.Error) {
+		emit(ctx, golog.ErrorLevel, fmt.Sprintf(msg, args...), nil)
+	}
+}
+
+// Trace implements the logger.Interface interface. It logs the
+// statement with its arguments, the rows affected and the time
+// elapsed since begin. fc is only called when the entry is logged.
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	var (
+		lvl  golog.Level
+		mode logger.LogLevel
+		msg  string
+	)
+	switch {
+	case err != nil && !(l.IgnoreRecordNotFound && errors.Is(err, logger.ErrRecordNotFound)):
+		lvl, mode, msg = golog.ErrorLevel, logger.Error, "sql failed"
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold:
+		lvl, mode, msg = golog.WarningLevel, logger.Warn, "slow sql"
+	default:
+		lvl, mode, msg = golog.TraceLevel, logger.Info, "sql"
+	}
+	if !l.allows(mode) || !golog.Enabled(lvl) {
+		return
+	}
+	sql, rows := fc()
+	fields := golog.Fields{SQLKey: sql, ElapsedKey: elapsed}
+	if rows >= 0 {
+		fields[RowsKey] = rows
+	}
+	if err != nil {
+		fields[golog.ErrorKey] = err.Error()
+	}
+	emit(ctx, lvl, msg, fields)
+}
+
+// allows reports whether the GORM level of l allows the
+// entries of mode.
+func (l *Logger) allows(mode logger.LogLevel) bool {
+	return l.mode >= mode
+}
+
+// emit writes msg at lvl with fields and the fields attached
+// to ctx with golog.ContextWithFields.
+func emit(ctx context.Context, lvl golog.Level, msg string, fields golog.Fields) {
+	all := make(golog.Fields, len(fields))
+	for k, v := range golog.ContextFields(ctx) {
+		all[k] = v
+	}
+	for k, v := range fields {
+		all[k] = v
+	}
+	golog.Emit(&golog.Entry{
+		Level:   lvl,
+		Message: msg,
+		Fields:  all,
+		Caller:  caller(),
+	})
+}
+
+// caller returns the first frame outside of GORM and this package,
+// which is the call site of the query.
+func caller() *runtime.Frame {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "gorm.io/") &&
+			!strings.HasPrefix(frame.Function, "github.com/jayvib/golog/gormlog.") {
+			return &frame
+		}
+		if !more {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package gormlog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jayvib/golog"
+	"github.com/jayvib/golog/gormlog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
+)
+
+func TestLogger(t *testing.T) {
+	defer golog.SetOutput(os.Stdout)
+	defer golog.SetFormatter(nil)
+	defer golog.SetLevel(golog.InfoLevel)
+	var out bytes.Buffer
+	golog.SetOutput(&out)
+	golog.SetFormatter(&golog.JSONFormatter{})
+	golog.SetLevel(golog.TraceLevel)
+
+	var l logger.Interface = gormlog.New(100 * time.Millisecond)
+	ctx := golog.ContextWithFields(context.Background(), golog.Fields{"request_id": "42"})
+	query := func() (string, int64) {
+		return "SELECT * FROM users WHERE id = 7", 1
+	}
+	last := func() map[string]interface{} {
+		t.Helper()
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+		out.Reset()
+		return entry
+	}
+
+	l.Trace(ctx, time.Now(), query, nil)
+	entry := last()
+	assert.Equal(t, "trace", entry["level"])
+	assert.Equal(t, "sql", entry["msg"])
+	assert.Equal(t, "SELECT * FROM users WHERE id = 7", entry["sql"])
+	assert.Equal(t, float64(1), entry["sql.rows"])
+	assert.Equal(t, "42", entry["request_id"])
+	assert.Contains(t, entry["caller"], "gormlog_test.go")
+
+	l.Trace(ctx, time.Now().Add(-time.Second), query, nil)
+	entry = last()
+	assert.Equal(t, "warning", entry["level"])
+	assert.Equal(t, "slow sql", entry["msg"])
+
+	l.Trace(ctx, time.Now(), query, errors.New("duplicate key"))
+	entry = last()
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "sql failed", entry["msg"])
+	assert.Equal(t, "duplicate key", entry["error"])
+
+	l.Warn(ctx, "deprecated %s", "option")
+	entry = last()
+	assert.Equal(t, "warning", entry["level"])
+	assert.Equal(t, "deprecated option", entry["msg"])
+
+	t.Run("respects golog.SetLevel", func(t *testing.T) {
+		golog.SetLevel(golog.InfoLevel)
+		defer golog.SetLevel(golog.TraceLevel)
+		called := false
+		l.Trace(ctx, time.Now(), func() (string, int64) {
+			called = true
+			return "SELECT 1", -1
+		}, nil)
+		assert.False(t, called)
+		assert.Empty(t, out.String())
+
+		l.Trace(ctx, time.Now().Add(-time.Second), func() (string, int64) {
+			return "SELECT 1", -1
+		}, nil)
+		entry := last()
+		assert.Equal(t, "slow sql", entry["msg"])
+		assert.NotContains(t, entry, "sql.rows")
+	})
+
+	t.Run("LogMode", func(t *testing.T) {
+		l := l.LogMode(logger.Warn)
+		l.Trace(ctx, time.Now(), query, nil)
+		l.Info(ctx, "hidden")
+		assert.Empty(t, out.String())
+		l.Trace(ctx, time.Now(), query, errors.New("timeout"))
+		assert.Equal(t, "sql failed", last()["msg"])
+
+		l.LogMode(logger.Silent).Error(ctx, "hidden")
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("IgnoreRecordNotFound", func(t *testing.T) {
+		l := gormlog.New(0)
+		l.IgnoreRecordNotFound = true
+		l.Trace(ctx, time.Now(), query, logger.ErrRecordNotFound)
+		entry := last()
+		assert.Equal(t, "trace", entry["level"])
+		assert.Equal(t, "record not found", entry["error"])
+	})
+}
@@ -0,0 +1,154 @@
+package golog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// drops counts the entries that could not be formatted or written.
+var drops uint64
+
+// Drops returns the number of entries that were lost because
+// they could not be formatted or written to their output.
+func Drops() uint64 {
+	return atomic.LoadUint64(&drops)
+}
+
+func countDrop() {
+	atomic.AddUint64(&drops, 1)
+}
+
+// healthBuckets is the number of buckets of the rolling window.
+const healthBuckets = 10
+
+// HealthStatus is a snapshot of the rolling logging health.
+type HealthStatus struct {
+	Entries   uint64
+	Errors    uint64
+	Drops     uint64
+	ErrorRate float64
+	DropRate  float64
+	Healthy   bool
+}
+
+// HealthHook is a Hook that computes the rolling error rate and
+// drop rate of the logging pipeline, so services can flip readiness
+// or page when logging itself degrades:
+//
+//	health := &golog.HealthHook{MaxErrorRate: 0.05, MaxDropRate: 0.01, OnUnhealthy: page}
+//	golog.AddHook(health)
+//
+// The fields must be set before the hook is registered.
+type HealthHook struct {
+	// Window is the duration of the rolling window.
+	// Defaults to one minute.
+	Window time.Duration
+	// MaxErrorRate is the highest healthy fraction of Error entries.
+	// Zero disables the check.
+	MaxErrorRate float64
+	// MaxDropRate is the highest healthy fraction of dropped entries.
+	// Zero disables the check.
+	MaxDropRate float64
+	// OnUnhealthy and OnHealthy are called when the health changes.
+	OnUnhealthy func(HealthStatus)
+	OnHealthy   func(HealthStatus)
+
+	mu        sync.Mutex
+	buckets   [healthBuckets]healthBucket
+	lastDrops uint64
+	unhealthy bool
+}
+
+type healthBucket struct {
+	start   time.Time
+	entries uint64
+	errors  uint64
+	drops   uint64
+}
+
+// Fire implements the Hook interface.
+func (h *HealthHook) Fire(e *Entry) error {
+	h.mu.Lock()
+	b := h.bucket(now())
+	b.entries++
+	if e.Level >= ErrorLevel {
+		b.errors++
+	}
+	status := h.status()
+	var callback func(HealthStatus)
+	if status.Healthy == h.unhealthy {
+		h.unhealthy = !status.Healthy
+		callback = h.OnHealthy
+		if h.unhealthy {
+			callback = h.OnUnhealthy
+		}
+	}
+	h.mu.Unlock()
+	if callback != nil {
+		callback(status)
+	}
+	return nil
+}
+
+// Healthy reports whether the rates of the rolling
+// window are within the thresholds.
+func (h *HealthHook) Healthy() bool {
+	return h.Status().Healthy
+}
+
+// Status returns the health of the rolling window.
+func (h *HealthHook) Status() HealthStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.bucket(now())
+	return h.status()
+}
+
+func (h *HealthHook) window() time.Duration {
+	if h.Window <= 0 {
+		return time.Minute
+	}
+	return h.Window
+}
+
+// bucket returns the bucket of t, resetting it when it belongs
+// to a previous window, and accounts the new drops in it.
+func (h *HealthHook) bucket(t time.Time) *healthBucket {
+	width := h.window() / healthBuckets
+	if width <= 0 {
+		// A window shorter than healthBuckets nanoseconds
+		// still has buckets of a nanosecond.
+		width = time.Nanosecond
+	}
+	start := t.Truncate(width)
+	b := &h.buckets[(start.UnixNano()/int64(width))%healthBuckets]
+	if !b.start.Equal(start) {
+		*b = healthBucket{start: start}
+	}
+	d := Drops()
+	b.drops += d - h.lastDrops
+	h.lastDrops = d
+	return b
+}
+
+func (h *HealthHook) status() HealthStatus {
+	var s HealthStatus
+	oldest := now().Add(-h.window())
+	for _, b := range h.buckets {
+		if b.start.After(oldest) {
+			s.Entries += b.entries
+			s.Errors += b.errors
+			s.Drops += b.drops
+		}
+	}
+	if s.Entries > 0 {
+		s.ErrorRate = float64(s.Errors) / float64(s.Entries)
+	}
+	if total := s.Entries + s.Drops; total > 0 {
+		s.DropRate = float64(s.Drops) / float64(total)
+	}
+	s.Healthy = (h.MaxErrorRate <= 0 || s.ErrorRate <= h.MaxErrorRate) &&
+		(h.MaxDropRate <= 0 || s.DropRate <= h.MaxDropRate)
+	return s
+}
@@ -0,0 +1,67 @@
+package golog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestHealthHook(t *testing.T) {
+	defer func() { now = time.Now }()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	var transitions []bool
+	h := &HealthHook{
+		MaxErrorRate: 0.5,
+		MaxDropRate:  0.2,
+		OnUnhealthy:  func(HealthStatus) { transitions = append(transitions, false) },
+		OnHealthy:    func(HealthStatus) { transitions = append(transitions, true) },
+	}
+	h.lastDrops = Drops()
+
+	info := newStdLogger(InfoLevel, failingWriter{}, 0)
+	info.SetOutput(&syncBuffer{})
+	info.AddHook(h)
+	errs := newStdLogger(ErrorLevel, &syncBuffer{}, 0)
+	errs.AddHook(h)
+
+	info.Print("ok")
+	errs.Print("boom")
+	assert.True(t, h.Healthy())
+	errs.Print("boom")
+	assert.False(t, h.Healthy())
+	assert.Equal(t, []bool{false}, transitions)
+
+	t.Run("rates roll out of the window", func(t *testing.T) {
+		clock = clock.Add(2 * time.Minute)
+		info.Print("ok")
+		assert.True(t, h.Healthy())
+		assert.Equal(t, []bool{false, true}, transitions)
+	})
+	t.Run("drops are accounted", func(t *testing.T) {
+		info.SetOutput(failingWriter{})
+		info.Print("lost")
+		info.Print("lost")
+		s := h.Status()
+		assert.Equal(t, uint64(2), s.Drops)
+		assert.False(t, s.Healthy)
+	})
+	t.Run("window shorter than the buckets", func(t *testing.T) {
+		h := &HealthHook{Window: 5 * time.Nanosecond, MaxErrorRate: 0.5}
+		assert.NotPanics(t, func() {
+			assert.NoError(t, h.Fire(&Entry{Level: ErrorLevel}))
+		})
+		assert.Equal(t, uint64(1), h.Status().Errors)
+	})
+}
@@ -0,0 +1,154 @@
+package golog
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// histogramSubBuckets is the number of buckets per power of two,
+// which bounds the relative error of the quantiles to about 4%.
+const histogramSubBuckets = 8
+
+// histogramBuckets covers every positive time.Duration.
+const histogramBuckets = 64 * histogramSubBuckets
+
+// LatencyHistogram records durations into an exponential streaming
+// histogram and periodically logs their p50, p95 and p99 as a
+// summary entry, giving latency visibility where no metrics
+// infrastructure is available:
+//
+//	h := golog.NewLatencyHistogram(golog.InfoLogger, "db_query", time.Minute)
+//	defer h.Stop()
+//	start := time.Now()
+//	...
+//	h.Observe(time.Since(start))
+type LatencyHistogram struct {
+	logger FieldLogger
+	name   string
+
+	mu     sync.Mutex
+	counts [histogramBuckets]uint64
+	count  uint64
+	sum    time.Duration
+	max    time.Duration
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewLatencyHistogram returns a histogram that logs its summary
+// with l every interval. A zero interval disables the periodic
+// summaries; call Flush to emit them.
+func NewLatencyHistogram(l FieldLogger, name string, interval time.Duration) *LatencyHistogram {
+	h := &LatencyHistogram{
+		logger: l,
+		name:   name,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if interval <= 0 {
+		close(h.done)
+		return h
+	}
+	go h.run(interval)
+	return h
+}
+
+func (h *LatencyHistogram) run(interval time.Duration) {
+	defer close(h.done)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			h.Flush()
+		case <-h.stop:
+			return
+		}
+	}
+}
+
+// Observe records d in the histogram.
+func (h *LatencyHistogram) Observe(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.mu.Lock()
+	h.counts[histogramBucket(d)]++
+	h.count++
+	h.sum += d
+	if d > h.max {
+		h.max = d
+	}
+	h.mu.Unlock()
+}
+
+// Flush logs the summary of the durations observed since the
+// previous flush and resets the histogram. Nothing is logged
+// when no duration was observed.
+func (h *LatencyHistogram) Flush() {
+	h.mu.Lock()
+	if h.count == 0 {
+		h.mu.Unlock()
+		return
+	}
+	fields := Fields{
+		"histogram": h.name,
+		"count":     h.count,
+		"mean":      (h.sum / time.Duration(h.count)).String(),
+		"max":       h.max.String(),
+		"p50":       h.quantile(0.50).String(),
+		"p95":       h.quantile(0.95).String(),
+		"p99":       h.quantile(0.99).String(),
+	}
+	h.counts = [histogramBuckets]uint64{}
+	h.count, h.sum, h.max = 0, 0, 0
+	h.mu.Unlock()
+	h.logger.WithFields(fields).Print("latency summary")
+}
+
+// Stop stops the periodic summaries and flushes
+// the pending durations.
+func (h *LatencyHistogram) Stop() {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+		<-h.done
+		h.Flush()
+	})
+}
+
+// quantile returns the estimated duration of quantile q,
+// capped by the largest observed duration.
+func (h *LatencyHistogram) quantile(q float64) time.Duration {
+	rank := uint64(math.Ceil(q * float64(h.count)))
+	var seen uint64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank && c > 0 {
+			if d := histogramValue(i); d < h.max {
+				return d
+			}
+			return h.max
+		}
+	}
+	return h.max
+}
+
+// histogramBucket returns the bucket of d.
+func histogramBucket(d time.Duration) int {
+	if d < 1 {
+		return 0
+	}
+	i := int(math.Log2(float64(d)) * histogramSubBuckets)
+	if i >= histogramBuckets {
+		return histogramBuckets - 1
+	}
+	return i
+}
+
+// histogramValue returns the geometric middle of bucket i.
+func histogramValue(i int) time.Duration {
+	return time.Duration(math.Exp2((float64(i) + 0.5) / histogramSubBuckets))
+}
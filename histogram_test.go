@@ -0,0 +1,57 @@
+package golog
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	l := newStdLogger(InfoLevel, out, 0)
+	l.SetFormatter(&JSONFormatter{})
+
+	h := NewLatencyHistogram(l, "db_query", 0)
+	for i := 1; i <= 100; i++ {
+		h.Observe(time.Duration(i) * time.Millisecond)
+	}
+	h.Flush()
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(out.String()), &entry))
+	assert.Equal(t, "latency summary", entry["msg"])
+	assert.Equal(t, "db_query", entry["histogram"])
+	assert.Equal(t, float64(100), entry["count"])
+	assert.Equal(t, "100ms", entry["max"])
+	for key, want := range map[string]time.Duration{
+		"p50": 50 * time.Millisecond,
+		"p95": 95 * time.Millisecond,
+		"p99": 99 * time.Millisecond,
+	} {
+		got, err := time.ParseDuration(entry[key].(string))
+		require.NoError(t, err)
+		assert.InEpsilon(t, float64(want), float64(got), 0.05, key)
+	}
+
+	t.Run("flush resets", func(t *testing.T) {
+		out.Reset()
+		h.Flush()
+		assert.Empty(t, out.String())
+	})
+
+	t.Run("periodic summaries", func(t *testing.T) {
+		out.Reset()
+		h := NewLatencyHistogram(l, "periodic", 10*time.Millisecond)
+		h.Observe(time.Second)
+		assert.Eventually(t, func() bool {
+			return len(out.String()) > 0
+		}, time.Second, 5*time.Millisecond)
+		h.Stop()
+		h.Stop()
+	})
+}
@@ -0,0 +1,261 @@
+package golog
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Hook is invoked with every entry before it is formatted. Hooks
+// may enrich the entry, e.g. with the hostname, or ship it elsewhere,
+// e.g. to an error tracker. The Fields of the entry are a copy owned
+// by the entry. An error returned by Fire is reported to the diagnostic
+// handler and doesn't prevent the entry from being written.
+type Hook interface {
+	Fire(e *Entry) error
+}
+
+// HookFunc is an adapter to use a function as a Hook.
+type HookFunc func(e *Entry) error
+
+// Fire calls f(e).
+func (f HookFunc) Fire(e *Entry) error {
+	return f(e)
+}
+
+// AddHook registers h to be fired on every entry emitted by
+// all the loggers, before the hooks of the loggers themselves.
+func AddHook(h Hook) {
+	updateState(func(s *globalState) {
+		hooks := make([]Hook, len(s.hooks), len(s.hooks)+1)
+		copy(hooks, s.hooks)
+		s.hooks = append(hooks, h)
+	})
+}
+
+// ResetHooks removes all the hooks registered with AddHook.
+func ResetHooks() {
+	updateState(func(s *globalState) {
+		s.hooks = nil
+	})
+}
+
+// LevelHook is a Hook only fired on the entries of some levels.
+type LevelHook interface {
+	Hook
+	// Levels returns the levels of the entries the hook is fired on.
+	Levels() []Level
+}
+
+// HookForLevels returns a LevelHook firing h only on
+// the entries of the levels.
+func HookForLevels(h Hook, levels ...Level) LevelHook {
+	return levelHook{Hook: h, levels: levels}
+}
+
+type levelHook struct {
+	Hook
+	levels []Level
+}
+
+func (h levelHook) Levels() []Level {
+	return h.levels
+}
+
+// firesOn reports whether h is fired on the entries of lvl.
+func firesOn(h Hook, lvl Level) bool {
+	lh, ok := h.(LevelHook)
+	if !ok {
+		return true
+	}
+	for _, l := range lh.Levels() {
+		if l == lvl {
+			return true
+		}
+	}
+	return false
+}
+
+// fireHooks fires the hooks in order on e.
+func fireHooks(hooks []Hook, e *Entry) {
+	for _, h := range hooks {
+		if !firesOn(h, e.Level) {
+			continue
+		}
+		if err := h.Fire(e); err != nil {
+			reportf("golog: failed to fire hook: %v", err)
+		}
+	}
+}
+
+var (
+	// ErrHookQueueFull is reported when an entry is dropped
+	// because the queue of an AsyncHook is full.
+	ErrHookQueueFull = errors.New("golog: hook queue full")
+	// ErrHookClosed is returned by the Fire method of
+	// a closed AsyncHook.
+	ErrHookClosed = errors.New("golog: hook closed")
+)
+
+// AsyncHook fires Hook on a pool of workers, so an expensive hook,
+// e.g. shipping the entries to an error tracker, can't block the
+// logging hot path. Fire enqueues a copy of the entry and returns
+// ErrHookQueueFull, without blocking, when the queue is full. The
+// errors of Hook, and its runs exceeding Timeout, are reported to
+// the diagnostic handler. The changes made by Hook to the entries
+// are not seen by the formatters.
+//
+//	h := &golog.AsyncHook{Hook: sentry, Workers: 4, Timeout: 5 * time.Second}
+//	defer h.Close()
+//	golog.AddHook(golog.HookForLevels(h, golog.ErrorLevel))
+//
+// The fields must be set before the first call to Fire.
+type AsyncHook struct {
+	Hook Hook
+	// Workers is the number of goroutines firing Hook.
+	// Defaults to 1, which fires Hook in the order of
+	// the entries. See SetOrderedDelivery.
+	Workers int
+	// QueueSize is the number of pending entries.
+	// Defaults to 1024.
+	QueueSize int
+	// Timeout is the longest run of Hook a worker waits for
+	// before moving to the next entry. The run is abandoned
+	// but not interrupted, and its error is still reported.
+	// Zero means no timeout.
+	Timeout time.Duration
+	// MaxAbandoned is the largest number of abandoned runs
+	// still running. Once reached, the workers wait for the
+	// runs without a timeout, so the queue fills up instead
+	// of the goroutines piling up. Defaults to Workers.
+	MaxAbandoned int
+
+	once      sync.Once
+	mu        sync.RWMutex // protects closed and the sends on queue
+	closed    bool
+	queue     chan *Entry
+	wg        sync.WaitGroup
+	limit     int32
+	abandoned int32 // accessed atomically
+}
+
+// Levels implements the LevelHook interface by forwarding
+// to Hook, which is fired on all the levels by default.
+func (h *AsyncHook) Levels() []Level {
+	if lh, ok := h.Hook.(LevelHook); ok {
+		return lh.Levels()
+	}
+	return []Level{DebugLevel, TraceLevel, InfoLevel, WarningLevel, ErrorLevel}
+}
+
+func (h *AsyncHook) start() {
+	size := h.QueueSize
+	if size <= 0 {
+		size = 1024
+	}
+	workers := h.Workers
+	if workers <= 0 || getState().ordered {
+		workers = 1
+	}
+	h.limit = int32(h.MaxAbandoned)
+	if h.limit <= 0 {
+		h.limit = int32(workers)
+	}
+	h.queue = make(chan *Entry, size)
+	h.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go h.work()
+	}
+}
+
+// Fire implements the Hook interface.
+func (h *AsyncHook) Fire(e *Entry) error {
+	h.once.Do(h.start)
+	entry := *e
+	entry.Fields = copyFields(e.Fields)
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.closed {
+		return ErrHookClosed
+	}
+	select {
+	case h.queue <- &entry:
+		return nil
+	default:
+		return ErrHookQueueFull
+	}
+}
+
+func (h *AsyncHook) work() {
+	defer h.wg.Done()
+	for e := range h.queue {
+		h.fire(e)
+	}
+}
+
+func (h *AsyncHook) fire(e *Entry) {
+	if h.Timeout <= 0 || atomic.LoadInt32(&h.abandoned) >= h.limit {
+		reportHookError(h.Hook.Fire(e))
+		return
+	}
+	// abandoned is set once by either the run, when it
+	// returns in time, or the worker, when it times out.
+	var abandoned int32
+	done := make(chan error, 1)
+	go func() {
+		err := h.Hook.Fire(e)
+		if !atomic.CompareAndSwapInt32(&abandoned, 0, 1) {
+			atomic.AddInt32(&h.abandoned, -1)
+			reportHookError(err)
+			return
+		}
+		done <- err
+	}()
+	t := time.NewTimer(h.Timeout)
+	defer t.Stop()
+	select {
+	case err := <-done:
+		reportHookError(err)
+	case <-t.C:
+		atomic.AddInt32(&h.abandoned, 1)
+		if !atomic.CompareAndSwapInt32(&abandoned, 0, 1) {
+			// The run returned in the meantime.
+			atomic.AddInt32(&h.abandoned, -1)
+			reportHookError(<-done)
+			return
+		}
+		reportf("golog: hook timed out after %v", h.Timeout)
+	}
+}
+
+// Abandoned returns the number of runs of Hook abandoned
+// after Timeout that are still running.
+func (h *AsyncHook) Abandoned() int {
+	return int(atomic.LoadInt32(&h.abandoned))
+}
+
+// reportHookError reports err, if any, to the diagnostic handler.
+func reportHookError(err error) {
+	if err != nil {
+		reportf("golog: failed to fire hook: %v", err)
+	}
+}
+
+// Close stops accepting entries and waits for the
+// pending ones to be fired. It doesn't wait for the
+// abandoned runs of Hook.
+func (h *AsyncHook) Close() error {
+	h.once.Do(h.start)
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	close(h.queue)
+	h.mu.Unlock()
+	h.wg.Wait()
+	return nil
+}
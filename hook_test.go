@@ -0,0 +1,173 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHooks(t *testing.T) {
+	defer ResetHooks()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+
+	var out bytes.Buffer
+	l := newStdLogger(ErrorLevel, &out, 0)
+	parent := l.WithFields(Fields{"request_id": "abc"})
+
+	var fired []string
+	AddHook(HookFunc(func(e *Entry) error {
+		fired = append(fired, "global")
+		e.Fields["hostname"] = "box-1"
+		return nil
+	}))
+	l.AddHook(HookFunc(func(e *Entry) error {
+		fired = append(fired, "logger")
+		assert.Equal(t, ErrorLevel, e.Level)
+		assert.Equal(t, "boom", e.Message)
+		assert.NotNil(t, e.Caller)
+		return errors.New("hook failure is reported but not fatal")
+	}))
+
+	parent.Print("boom")
+	assert.Equal(t, []string{"global", "logger"}, fired)
+	assert.Equal(t, "ERROR: boom hostname=box-1 request_id=abc\n", out.String())
+	assert.NotContains(t, parent.(*stdLogger).fields, "hostname")
+
+	t.Run("suppressed entries are not fired", func(t *testing.T) {
+		fired = nil
+		SetLevel(DisabledLevel)
+		parent.Print("boom")
+		assert.Empty(t, fired)
+	})
+}
+
+func TestLogrus_AddHook(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var out bytes.Buffer
+	l := NewLogrusLogger(InfoLevel)
+	l.SetOutput(&out)
+	l.AddHook(HookFunc(func(e *Entry) error {
+		e.Fields["hostname"] = "box-1"
+		return nil
+	}))
+	l.Print("hello")
+	assert.Contains(t, out.String(), "hostname=box-1")
+}
+
+func TestHookForLevels(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(DebugLevel)
+	var fired []Level
+	h := HookForLevels(HookFunc(func(e *Entry) error {
+		fired = append(fired, e.Level)
+		return nil
+	}), WarningLevel, ErrorLevel)
+
+	var out bytes.Buffer
+	l := newStdLogger(InfoLevel, &out, 0)
+	l.AddHook(h)
+	l.Info("info")
+	l.Warn("warn")
+	l.Error("error")
+	assert.Equal(t, []Level{WarningLevel, ErrorLevel}, fired)
+
+	t.Run("logrus", func(t *testing.T) {
+		fired = nil
+		l := NewLogrusLogger(InfoLevel)
+		l.SetOutput(&out)
+		l.AddHook(h)
+		l.Info("info")
+		l.Error("error")
+		assert.Equal(t, []Level{ErrorLevel}, fired)
+	})
+}
+
+func TestAsyncHook(t *testing.T) {
+	defer SetDiagnosticHandler(nil)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	diags := make(chan error, 16)
+	SetDiagnosticHandler(func(err error) { diags <- err })
+
+	release := make(chan struct{})
+	fired := make(chan *Entry, 16)
+	h := &AsyncHook{
+		Hook: HookFunc(func(e *Entry) error {
+			<-release
+			fired <- e
+			if e.Message == "fail" {
+				return errors.New("webhook unavailable")
+			}
+			return nil
+		}),
+		QueueSize: 1,
+	}
+	var out bytes.Buffer
+	l := newStdLogger(InfoLevel, &out, 0).WithFields(Fields{"k": "v"})
+	l.AddHook(h)
+
+	l.Print("first")
+	// The worker blocks on the first entry, so the second one
+	// fills the queue and the third one is dropped.
+	assert.Eventually(t, func() bool { return len(h.queue) == 0 }, time.Second, time.Millisecond)
+	l.Print("fail")
+	l.Print("dropped")
+	assert.Equal(t, "INFO: first k=v\nINFO: fail k=v\nINFO: dropped k=v\n", out.String())
+	assert.EqualError(t, <-diags, "golog: failed to fire hook: golog: hook queue full")
+
+	close(release)
+	assert.NoError(t, h.Close())
+	assert.Equal(t, "first", (<-fired).Message)
+	e := <-fired
+	assert.Equal(t, "fail", e.Message)
+	assert.Equal(t, Fields{"k": "v"}, e.Fields)
+	assert.EqualError(t, <-diags, "golog: failed to fire hook: webhook unavailable")
+	assert.Equal(t, ErrHookClosed, h.Fire(e))
+
+	t.Run("timeout", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+		h := &AsyncHook{
+			Hook:    HookFunc(func(*Entry) error { <-block; return nil }),
+			Timeout: 10 * time.Millisecond,
+		}
+		assert.NoError(t, h.Fire(&Entry{Message: "slow"}))
+		assert.NoError(t, h.Close())
+		assert.EqualError(t, <-diags, "golog: hook timed out after 10ms")
+	})
+
+	t.Run("abandoned runs are capped", func(t *testing.T) {
+		block := make(chan struct{})
+		var runs int32
+		h := &AsyncHook{
+			Hook: HookFunc(func(*Entry) error {
+				atomic.AddInt32(&runs, 1)
+				<-block
+				return errors.New("late")
+			}),
+			Timeout:      time.Millisecond,
+			MaxAbandoned: 2,
+		}
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, h.Fire(&Entry{Message: "slow"}))
+		}
+		// The third run is waited for once two runs are abandoned.
+		assert.Eventually(t, func() bool { return atomic.LoadInt32(&runs) == 3 }, time.Second, time.Millisecond)
+		assert.Equal(t, 2, h.Abandoned())
+		assert.EqualError(t, <-diags, "golog: hook timed out after 1ms")
+		assert.EqualError(t, <-diags, "golog: hook timed out after 1ms")
+
+		close(block)
+		assert.NoError(t, h.Close())
+		for i := 0; i < 3; i++ {
+			assert.EqualError(t, <-diags, "golog: failed to fire hook: late")
+		}
+		assert.Eventually(t, func() bool { return h.Abandoned() == 0 }, time.Second, time.Millisecond)
+	})
+}
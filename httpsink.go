@@ -0,0 +1,149 @@
+package golog
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// defaultHTTPTimeout is the default timeout of the requests of an
+// HTTPWriter.
+const defaultHTTPTimeout = 10 * time.Second
+
+// HTTPConfig configures the destination of an HTTPWriter.
+type HTTPConfig struct {
+	// URL is the endpoint the entries are posted to.
+	URL string
+	// ContentType is the content type of the requests.
+	// Defaults to "text/plain; charset=utf-8".
+	ContentType string
+	// Header holds additional headers of the requests.
+	Header http.Header
+	// Credentials, when not nil, provide the bearer token of the
+	// Authorization header of every request.
+	Credentials CredentialsProvider
+	// Proxy is the URL of the proxy the requests go through, with
+	// the "http", "https" or "socks5" scheme, e.g.
+	// "http://proxy.corp:3128". When empty, the proxy is taken from
+	// the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+	// "none" disables the proxy.
+	Proxy string
+	// TLSConfig secures the connections to an https URL,
+	// see TLSConfig.Build.
+	TLSConfig *tls.Config
+	// Timeout is the timeout of a request. Defaults to 10s.
+	Timeout time.Duration
+}
+
+// HTTPWriter is a sink posting every entry to an HTTP endpoint,
+// e.g. the ingestion API of a log management service.
+type HTTPWriter struct {
+	url         string
+	contentType string
+	header      http.Header
+	credentials CredentialsProvider
+	client      *http.Client
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewHTTPWriter returns an HTTPWriter posting to the URL of c.
+func NewHTTPWriter(c HTTPConfig) (*HTTPWriter, error) {
+	if _, err := url.Parse(c.URL); err != nil {
+		return nil, fmt.Errorf("golog: http writer: %v", err)
+	}
+	proxy, err := proxyFunc(c.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+	contentType := c.ContentType
+	if contentType == "" {
+		contentType = "text/plain; charset=utf-8"
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxy
+	transport.TLSClientConfig = c.TLSConfig
+	return &HTTPWriter{
+		url:         c.URL,
+		contentType: contentType,
+		header:      c.Header,
+		credentials: c.Credentials,
+		client:      &http.Client{Transport: transport, Timeout: timeout},
+	}, nil
+}
+
+// proxyFunc returns the proxy function of the Proxy of HTTPConfig.
+func proxyFunc(proxy string) (func(*http.Request) (*url.URL, error), error) {
+	switch proxy {
+	case "":
+		return http.ProxyFromEnvironment, nil
+	case "none":
+		return nil, nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("golog: http writer: invalid proxy: %v", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("golog: http writer: unsupported proxy scheme %q", u.Scheme)
+	}
+	return http.ProxyURL(u), nil
+}
+
+// Write posts p in one request. A response status other
+// than 2xx is an error.
+func (w *HTTPWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(p))
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range w.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", w.contentType)
+	if w.credentials != nil {
+		cr, err := w.credentials.Credentials()
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("Authorization", "Bearer "+cr.Token)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, fmt.Errorf("golog: http writer: %s", resp.Status)
+	}
+	return len(p), nil
+}
+
+// Close closes the idle connections. Writes after Close
+// fail with ErrWriterClosed.
+func (w *HTTPWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	w.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,78 @@
+package golog
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPWriter(t *testing.T) {
+	var got syncBuffer
+	var header http.Header
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		if string(b) == "reject\n" {
+			http.Error(w, "rejected", http.StatusBadRequest)
+			return
+		}
+		header = r.Header
+		got.Write(b)
+	}))
+	defer sink.Close()
+
+	w, err := NewHTTPWriter(HTTPConfig{
+		URL:    sink.URL,
+		Header: http.Header{"Authorization": {"Bearer token"}},
+		Proxy:  "none",
+	})
+	require.NoError(t, err)
+	n, err := w.Write([]byte("INFO: hello\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 12, n)
+	assert.Equal(t, "INFO: hello\n", got.String())
+	assert.Equal(t, "Bearer token", header.Get("Authorization"))
+	assert.Equal(t, "text/plain; charset=utf-8", header.Get("Content-Type"))
+
+	_, err = w.Write([]byte("reject\n"))
+	assert.EqualError(t, err, "golog: http writer: 400 Bad Request")
+
+	require.NoError(t, w.Close())
+	_, err = w.Write([]byte("INFO: closed\n"))
+	assert.Equal(t, ErrWriterClosed, err)
+
+	t.Run("proxy", func(t *testing.T) {
+		var proxied string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = r.URL.String()
+		}))
+		defer proxy.Close()
+		w, err := NewHTTPWriter(HTTPConfig{URL: "http://logs.example.com/ingest", Proxy: proxy.URL})
+		require.NoError(t, err)
+		defer w.Close()
+		_, err = w.Write([]byte("INFO: proxied\n"))
+		require.NoError(t, err)
+		assert.Equal(t, "http://logs.example.com/ingest", proxied)
+	})
+
+	t.Run("invalid proxies", func(t *testing.T) {
+		_, err := NewHTTPWriter(HTTPConfig{URL: sink.URL, Proxy: "ftp://proxy"})
+		assert.EqualError(t, err, `golog: http writer: unsupported proxy scheme "ftp"`)
+		_, err = NewHTTPWriter(HTTPConfig{URL: sink.URL, Proxy: "http://%zz"})
+		assert.Error(t, err)
+		_, err = NewHTTPWriter(HTTPConfig{URL: sink.URL, Proxy: "socks5://127.0.0.1:1080"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		fn, err := proxyFunc("")
+		require.NoError(t, err)
+		assert.NotNil(t, fn)
+		fn, err = proxyFunc("none")
+		require.NoError(t, err)
+		assert.Nil(t, fn)
+	})
+}
@@ -0,0 +1,53 @@
+package golog
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Ingest runs an entry produced elsewhere, e.g. parsed from the
+// output of a child process, through the pipeline of golog: the
+// samplers, processors and hooks, then the formatter and output of
+// the package level logger of its level. Unlike Emit, the entry
+// keeps its Template and is not counted by the escalation rules, as
+// it may be backfilled. Its Time is kept, and replaced by the current
+// time when zero. The level state and the mute rules apply.
+func Ingest(entry Entry) {
+	l := packageLogger(entry.Level)
+	if l == nil {
+		return
+	}
+	st := getState()
+	if !st.enabled(entry.Level, entry.Fields) {
+		return
+	}
+	if st.isMuted(entry.Level, func() string { return entry.Message }) {
+		return
+	}
+	entry.Message = strings.TrimSuffix(entry.Message, "\n")
+	attachGlobalFields(st, &entry)
+	l.emitOrdered(st, &entry, 0)
+}
+
+// IngestLines ingests the lines read from r until EOF, parsed into
+// entries by parse. The lines for which parse returns false are
+// skipped. A nil parse ingests every line as the message of an
+// InfoLevel entry.
+//
+//	cmd.Stdout = pw
+//	go golog.IngestLines(pr, parseChildLine)
+func IngestLines(r io.Reader, parse func(line string) (Entry, bool)) error {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 4096), maxLineSize)
+	for s.Scan() {
+		if parse == nil {
+			Ingest(Entry{Level: InfoLevel, Message: s.Text()})
+			continue
+		}
+		if entry, ok := parse(s.Text()); ok {
+			Ingest(entry)
+		}
+	}
+	return s.Err()
+}
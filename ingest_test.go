@@ -0,0 +1,49 @@
+package golog
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngest(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer func() {
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+	defer ResetProcessors()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetFormatter(&JSONFormatter{})
+	AddProcessor(func(e *Entry) { e.Fields["source"] = "child" })
+
+	ts := time.Date(2020, 5, 17, 10, 30, 0, 0, time.UTC)
+	Ingest(Entry{Time: ts, Level: WarningLevel, Message: "disk almost full\n", Fields: Fields{"pid": 42}})
+	Ingest(Entry{Time: ts, Level: DebugLevel, Message: "hidden"})
+	assert.Equal(t, `{"time":"2020-05-17T10:30:00Z","level":"warning","msg":"disk almost full","pid":42,"source":"child"}`+"\n", out.String())
+
+	t.Run("lines", func(t *testing.T) {
+		out.Reset()
+		parse := func(line string) (Entry, bool) {
+			if line == "" {
+				return Entry{}, false
+			}
+			lvl, msg := InfoLevel, line
+			if strings.HasPrefix(line, "E ") {
+				lvl, msg = ErrorLevel, line[2:]
+			}
+			return Entry{Time: ts, Level: lvl, Message: msg}, true
+		}
+		require.NoError(t, IngestLines(strings.NewReader("started\n\nE failed\n"), parse))
+		assert.Equal(t, `{"time":"2020-05-17T10:30:00Z","level":"info","msg":"started","source":"child"}`+"\n"+
+			`{"time":"2020-05-17T10:30:00Z","level":"error","msg":"failed","source":"child"}`+"\n", out.String())
+	})
+}
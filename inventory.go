@@ -0,0 +1,150 @@
+package golog
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// Component describes a part of the logging pipeline of the
+// binary, as listed by Inventory.
+type Component struct {
+	// Kind is "backend", "output", "formatter", "sink", "hook",
+	// "processor" or "sampler".
+	Kind string `json:"kind"`
+	// Type is the Go type of the component, or the name of the
+	// function of the processors.
+	Type string `json:"type"`
+	// Name identifies the component further, e.g. the level of
+	// an output or the path of a file.
+	Name string `json:"name,omitempty"`
+	// Module and Version are the module providing the component
+	// and its version, when the binary has build information.
+	// The standard library has no module.
+	Module  string `json:"module,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// Inventory returns the components of the logging pipeline: the
+// backends, the outputs and formatters of the package level loggers,
+// the buffering sinks, and the global hooks, processors and samplers,
+// with the module and version they come from, so the operators can
+// audit what a binary contains. See LogInventory.
+func Inventory() []Component {
+	bi, _ := debug.ReadBuildInfo()
+	var cs []Component
+	add := func(kind, typ, name, pkg string) {
+		c := Component{Kind: kind, Type: typ, Name: name}
+		c.Module, c.Version = moduleOf(bi, pkg)
+		cs = append(cs, c)
+	}
+	addValue := func(kind, name string, v interface{}) {
+		add(kind, fmt.Sprintf("%T", v), name, typePackage(v))
+	}
+
+	add("backend", "golog", "", "github.com/jayvib/golog")
+	add("backend", "logrus", "", "github.com/sirupsen/logrus")
+	for _, l := range packageLoggers() {
+		l.out.mu.Lock()
+		w, f := l.out.w, l.out.formatter
+		l.out.mu.Unlock()
+		name := l.level.name()
+		if file, ok := w.(*os.File); ok {
+			name += " " + file.Name()
+		}
+		addValue("output", name, w)
+		addValue("formatter", l.level.name(), f)
+	}
+	sinksMu.Lock()
+	var sinkTypes []interface{}
+	for s := range sinks {
+		sinkTypes = append(sinkTypes, s)
+	}
+	sinksMu.Unlock()
+	sort.Slice(sinkTypes, func(i, j int) bool {
+		return fmt.Sprintf("%T", sinkTypes[i]) < fmt.Sprintf("%T", sinkTypes[j])
+	})
+	for _, s := range sinkTypes {
+		addValue("sink", "", s)
+	}
+	st := getState()
+	for _, h := range st.hooks {
+		addValue("hook", "", h)
+	}
+	for _, p := range st.processors {
+		fn := runtime.FuncForPC(reflect.ValueOf(p).Pointer()).Name()
+		add("processor", fn, "", packagePath(fn))
+	}
+	for _, s := range st.samplers {
+		addValue("sampler", "", s)
+	}
+	return cs
+}
+
+// LogInventory logs the components of Inventory with InfoLogger,
+// one entry per component, e.g. at startup.
+func LogInventory() {
+	for _, c := range Inventory() {
+		fields := Fields{"component.kind": c.Kind, "component.type": c.Type}
+		if c.Name != "" {
+			fields["component.name"] = c.Name
+		}
+		if c.Module != "" {
+			fields["component.module"] = c.Module
+			fields["component.version"] = c.Version
+		}
+		InfoLogger.WithFields(fields).Info("logging component")
+	}
+}
+
+// typePackage returns the import path of the package
+// defining the type of v.
+func typePackage(v interface{}) string {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return ""
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath()
+}
+
+// moduleOf returns the module of bi providing the package
+// pkg and its version.
+func moduleOf(bi *debug.BuildInfo, pkg string) (path, version string) {
+	if bi == nil || pkg == "" {
+		return "", ""
+	}
+	if first := strings.SplitN(pkg, "/", 2)[0]; !strings.Contains(first, ".") {
+		// The standard library, or the package main.
+		if pkg == "main" {
+			return bi.Main.Path, bi.Main.Version
+		}
+		return "", ""
+	}
+	best := &bi.Main
+	match := within(pkg, bi.Main.Path)
+	for _, m := range bi.Deps {
+		if within(pkg, m.Path) && (!match || len(m.Path) > len(best.Path)) {
+			best, match = m, true
+		}
+	}
+	if !match {
+		return "", ""
+	}
+	if best.Replace != nil {
+		best = best.Replace
+	}
+	return best.Path, best.Version
+}
+
+// within reports whether the package pkg belongs to
+// the module path mod.
+func within(pkg, mod string) bool {
+	return mod != "" && (pkg == mod || strings.HasPrefix(pkg, mod+"/"))
+}
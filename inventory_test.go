@@ -0,0 +1,78 @@
+package golog
+
+import (
+	"os"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInventory(t *testing.T) {
+	defer ResetProcessors()
+	defer ResetSamplers()
+	defer SetOutput(os.Stdout)
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetLevelOutput(ErrorLevel, os.Stderr)
+	AddProcessor(IPAnonymizer{Fields: []string{"ip"}}.Process)
+	AddSampler(&MessageSampler{})
+	bw := NewBufferedWriter(out, 0, 0)
+	defer bw.Close()
+
+	cs := Inventory()
+	has := func(want Component) {
+		for _, c := range cs {
+			if c.Kind == want.Kind && c.Type == want.Type && c.Name == want.Name {
+				return
+			}
+		}
+		t.Errorf("missing %+v in %+v", want, cs)
+	}
+	has(Component{Kind: "backend", Type: "golog"})
+	has(Component{Kind: "backend", Type: "logrus"})
+	has(Component{Kind: "output", Type: "*golog.syncBuffer", Name: "info"})
+	has(Component{Kind: "output", Type: "*os.File", Name: "error /dev/stderr"})
+	has(Component{Kind: "formatter", Type: "*golog.TextFormatter", Name: "info"})
+	has(Component{Kind: "sink", Type: "*golog.BufferedWriter"})
+	has(Component{Kind: "processor", Type: "github.com/jayvib/golog.IPAnonymizer.Process-fm"})
+	has(Component{Kind: "sampler", Type: "*golog.MessageSampler"})
+
+	t.Run("logged", func(t *testing.T) {
+		defer SetLevel(InfoLevel)
+		SetLevel(InfoLevel)
+		out.Reset()
+		LogInventory()
+		assert.Regexp(t, `INFO: .* logging component component.kind=backend .*component.type=logrus`, out.String())
+	})
+}
+
+func TestModuleOf(t *testing.T) {
+	bi := &debug.BuildInfo{
+		Main: debug.Module{Path: "github.com/acme/app", Version: "(devel)"},
+		Deps: []*debug.Module{
+			{Path: "github.com/jayvib/golog", Version: "v1.2.0"},
+			{Path: "github.com/acme/app/plugins", Version: "v0.3.0"},
+			{Path: "github.com/sirupsen/logrus", Version: "v1.4.2", Replace: &debug.Module{Path: "github.com/fork/logrus", Version: "v1.4.3"}},
+		},
+	}
+	cases := []struct {
+		pkg, path, version string
+	}{
+		{"github.com/jayvib/golog", "github.com/jayvib/golog", "v1.2.0"},
+		{"github.com/acme/app/internal/log", "github.com/acme/app", "(devel)"},
+		{"github.com/acme/app/plugins/sentry", "github.com/acme/app/plugins", "v0.3.0"},
+		{"github.com/sirupsen/logrus", "github.com/fork/logrus", "v1.4.3"},
+		{"main", "github.com/acme/app", "(devel)"},
+		{"os", "", ""},
+		{"github.com/unknown/pkg", "", ""},
+	}
+	for _, c := range cases {
+		path, version := moduleOf(bi, c.pkg)
+		assert.Equal(t, c.path, path, c.pkg)
+		assert.Equal(t, c.version, version, c.pkg)
+	}
+	path, _ := moduleOf(nil, "github.com/jayvib/golog")
+	require.Empty(t, path)
+}
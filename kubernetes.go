@@ -0,0 +1,54 @@
+package golog
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// serviceAccountNamespaceFile is mounted in every pod that
+// has a service account token.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// kubernetesEnv maps the field keys to the downward API environment
+// variables, in order of preference.
+var kubernetesEnv = []struct {
+	key  string
+	envs []string
+}{
+	{key: "pod_name", envs: []string{"POD_NAME", "K8S_POD_NAME"}},
+	{key: "pod_namespace", envs: []string{"POD_NAMESPACE", "NAMESPACE", "K8S_NAMESPACE"}},
+	{key: "node_name", envs: []string{"NODE_NAME", "K8S_NODE_NAME"}},
+	{key: "pod_ip", envs: []string{"POD_IP"}},
+}
+
+// KubernetesFields returns the pod metadata exposed by the downward
+// API environment variables (POD_NAME, POD_NAMESPACE or NAMESPACE,
+// NODE_NAME and POD_IP). The namespace falls back to the service
+// account namespace file. Outside of Kubernetes the returned Fields
+// are empty, so it is safe to call unconditionally:
+//
+//	logger = logger.WithFields(golog.KubernetesFields())
+func KubernetesFields() Fields {
+	return kubernetesFields(os.Getenv, ioutil.ReadFile)
+}
+
+func kubernetesFields(getenv func(string) string, readFile func(string) ([]byte, error)) Fields {
+	fields := Fields{}
+	for _, e := range kubernetesEnv {
+		for _, env := range e.envs {
+			if v := strings.TrimSpace(getenv(env)); v != "" {
+				fields[e.key] = v
+				break
+			}
+		}
+	}
+	if _, ok := fields["pod_namespace"]; !ok {
+		if b, err := readFile(serviceAccountNamespaceFile); err == nil {
+			if ns := strings.TrimSpace(string(b)); ns != "" {
+				fields["pod_namespace"] = ns
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,41 @@
+package golog
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubernetesFields(t *testing.T) {
+	noFile := func(string) ([]byte, error) { return nil, os.ErrNotExist }
+	env := func(m map[string]string) func(string) string {
+		return func(k string) string { return m[k] }
+	}
+
+	t.Run("outside of kubernetes", func(t *testing.T) {
+		assert.Empty(t, kubernetesFields(env(nil), noFile))
+	})
+	t.Run("downward api environment variables", func(t *testing.T) {
+		got := kubernetesFields(env(map[string]string{
+			"POD_NAME":  "api-7d9f",
+			"NAMESPACE": "prod",
+			"NODE_NAME": "node-1",
+			"POD_IP":    "10.0.0.7",
+		}), noFile)
+		assert.Equal(t, Fields{
+			"pod_name":      "api-7d9f",
+			"pod_namespace": "prod",
+			"node_name":     "node-1",
+			"pod_ip":        "10.0.0.7",
+		}, got)
+	})
+	t.Run("namespace falls back to the service account file", func(t *testing.T) {
+		readFile := func(name string) ([]byte, error) {
+			assert.Equal(t, serviceAccountNamespaceFile, name)
+			return []byte("staging\n"), nil
+		}
+		got := kubernetesFields(env(map[string]string{"POD_NAME": "api"}), readFile)
+		assert.Equal(t, Fields{"pod_name": "api", "pod_namespace": "staging"}, got)
+	})
+}
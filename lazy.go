@@ -0,0 +1,72 @@
+package golog
+
+// DebugFn logs the message returned by fn at DebugLevel. fn is only
+// called when the level is enabled, so building an expensive message,
+// e.g. dumping a large struct, costs nothing when it is not:
+//
+//	golog.DebugFn(func() string { return spew.Sdump(req) })
+func DebugFn(fn func() string) {
+	if DebugLogger.isPrint() {
+		DebugLogger.Output(stdCallDepth, fn())
+	}
+}
+
+// TraceFn logs the message returned by fn at TraceLevel.
+// fn is only called when the level is enabled.
+func TraceFn(fn func() string) {
+	if TraceLogger.isPrint() {
+		TraceLogger.Output(stdCallDepth, fn())
+	}
+}
+
+// LogFn logs the message returned by fn at lvl with the package
+// logger of lvl. fn is only called when the level is enabled.
+func LogFn(lvl Level, fn func() string) {
+	if l := packageLogger(lvl); l != nil && l.isPrint() {
+		l.Output(stdCallDepth, fn())
+	}
+}
+
+// DebugFn logs the message returned by fn at DebugLevel.
+// fn is only called when the level is enabled.
+func (l *stdLogger) DebugFn(fn func() string) {
+	if l.isPrintLevel(DebugLevel) {
+		l.outputLevel(stdCallDepth, DebugLevel, fn(), "")
+	}
+}
+
+// TraceFn logs the message returned by fn at TraceLevel.
+// fn is only called when the level is enabled.
+func (l *stdLogger) TraceFn(fn func() string) {
+	if l.isPrintLevel(TraceLevel) {
+		l.outputLevel(stdCallDepth, TraceLevel, fn(), "")
+	}
+}
+
+// LogFn logs the message returned by fn at lvl.
+// fn is only called when the level is enabled.
+func (l *stdLogger) LogFn(lvl Level, fn func() string) {
+	if lvl < DisabledLevel && l.isPrintLevel(lvl) {
+		l.outputLevel(stdCallDepth, lvl, fn(), "")
+	}
+}
+
+// DebugFn logs the message returned by fn at DebugLevel.
+// fn is only called when the level is enabled.
+func (l *Logrus) DebugFn(fn func() string) {
+	l.logLevel(DebugLevel, "", fn)
+}
+
+// TraceFn logs the message returned by fn at TraceLevel.
+// fn is only called when the level is enabled.
+func (l *Logrus) TraceFn(fn func() string) {
+	l.logLevel(TraceLevel, "", fn)
+}
+
+// LogFn logs the message returned by fn at lvl.
+// fn is only called when the level is enabled.
+func (l *Logrus) LogFn(lvl Level, fn func() string) {
+	if lvl < DisabledLevel {
+		l.logLevel(lvl, "", fn)
+	}
+}
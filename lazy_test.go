@@ -0,0 +1,65 @@
+package golog
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugFn(t *testing.T) {
+	defer SetLevel(InfoLevel)
+
+	t.Run("logger", func(t *testing.T) {
+		SetLevel(TraceLevel)
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0)
+		calls := 0
+		fn := func(msg string) func() string {
+			return func() string {
+				calls++
+				return msg
+			}
+		}
+		l.DebugFn(fn("hidden"))
+		l.TraceFn(fn("trace"))
+		l.LogFn(WarningLevel, fn("warning"))
+		l.LogFn(DisabledLevel, fn("disabled"))
+		assert.Equal(t, "TRACE: trace\nWARNING: warning\n", out.String())
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("package level", func(t *testing.T) {
+		SetLevel(DebugLevel)
+		var out syncBuffer
+		DebugLogger.SetOutput(&out)
+		defer DebugLogger.SetOutput(os.Stdout)
+		DebugLogger.SetFormatter(&TextFormatter{})
+		defer DebugLogger.SetFormatter(&TextFormatter{Flags: defaultFlags(DebugLevel)})
+		DebugFn(func() string { return "dump" })
+		LogFn(DebugLevel, func() string { return "log" })
+		LogFn(Level(42), func() string { panic("not called") })
+		assert.Equal(t, "DEBUG: dump\nDEBUG: log\n", out.String())
+	})
+
+	t.Run("logrus", func(t *testing.T) {
+		SetLevel(InfoLevel)
+		var out syncBuffer
+		l := NewLogrusLogger(InfoLevel)
+		l.SetOutput(&out)
+		l.SetFormatter(&TextFormatter{})
+		l.DebugFn(func() string { panic("not called") })
+		l.LogFn(ErrorLevel, func() string { return "failed" })
+		assert.Equal(t, "ERROR: failed\n", out.String())
+	})
+
+	t.Run("no allocation when disabled", func(t *testing.T) {
+		SetLevel(InfoLevel)
+		v := make([]int, 1000)
+		allocs := testing.AllocsPerRun(100, func() {
+			DebugFn(func() string { return fmt.Sprint(v) })
+		})
+		assert.Zero(t, allocs)
+	})
+}
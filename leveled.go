@@ -0,0 +1,93 @@
+package golog
+
+import "fmt"
+
+// BadKey is the key of a trailing value without its key in the
+// key/value pairs of a LeveledLogger.
+const BadKey = "!BADKEY"
+
+// LeveledLogger adapts the package level loggers to the LeveledLogger
+// interface of hashicorp/go-retryablehttp and the libraries sharing
+// its shape, which log a message with alternating keys and values:
+//
+//	client := retryablehttp.NewClient()
+//	client.Logger = golog.NewLeveledLogger("component", "http")
+//
+// The pairs become the fields of the entries. The keys which are not
+// strings are formatted with fmt.Sprint.
+type LeveledLogger struct {
+	fields Fields
+}
+
+// NewLeveledLogger returns a LeveledLogger adding the fields of the
+// pairs keysAndValues to all its entries.
+func NewLeveledLogger(keysAndValues ...interface{}) *LeveledLogger {
+	return &LeveledLogger{fields: keyValueFields(nil, keysAndValues)}
+}
+
+// With returns a LeveledLogger adding the fields of the pairs
+// keysAndValues to the ones of l.
+func (l *LeveledLogger) With(keysAndValues ...interface{}) *LeveledLogger {
+	return &LeveledLogger{fields: keyValueFields(l.fields, keysAndValues)}
+}
+
+// Debug logs msg with the fields of keysAndValues at DebugLevel.
+func (l *LeveledLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.log(DebugLevel, msg, keysAndValues)
+}
+
+// Info logs msg with the fields of keysAndValues at InfoLevel.
+func (l *LeveledLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.log(InfoLevel, msg, keysAndValues)
+}
+
+// Warn logs msg with the fields of keysAndValues at WarningLevel.
+func (l *LeveledLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.log(WarningLevel, msg, keysAndValues)
+}
+
+// Error logs msg with the fields of keysAndValues at ErrorLevel.
+func (l *LeveledLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.log(ErrorLevel, msg, keysAndValues)
+}
+
+// log logs msg at lvl with the package level logger of lvl. It must
+// be called directly by the logging methods of LeveledLogger.
+func (l *LeveledLogger) log(lvl Level, msg string, keysAndValues []interface{}) {
+	pl := packageLogger(lvl)
+	if pl == nil {
+		return
+	}
+	if !pl.isPrint() && !getState().gatesOnFields() {
+		return
+	}
+	fields := keyValueFields(l.fields, keysAndValues)
+	if !pl.isPrintCall(lvl, fields) {
+		return
+	}
+	pl.write(stdCallDepth, lvl, msg, "", writeDefault, fields)
+}
+
+// keyValueFields returns a copy of fields with the fields of the
+// pairs keysAndValues, or nil when there is none.
+func keyValueFields(fields Fields, keysAndValues []interface{}) Fields {
+	if len(fields) == 0 && len(keysAndValues) == 0 {
+		return nil
+	}
+	m := make(Fields, len(fields)+(len(keysAndValues)+1)/2)
+	for k, v := range fields {
+		m[k] = v
+	}
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 == len(keysAndValues) {
+			m[BadKey] = keysAndValues[i]
+			break
+		}
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		m[key] = keysAndValues[i+1]
+	}
+	return m
+}
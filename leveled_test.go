@@ -0,0 +1,62 @@
+package golog
+
+import (
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// retryableLeveledLogger is the LeveledLogger interface
+// of hashicorp/go-retryablehttp.
+type retryableLeveledLogger interface {
+	Error(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Debug(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+}
+
+func TestLeveledLogger(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer func() {
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+
+	var l retryableLeveledLogger = NewLeveledLogger("component", "http")
+	l.Debug("performing request", "method", "GET")
+	l.Info("performing request", "method", "GET", "url", "http://example.com")
+	assert.Equal(t, "INFO: leveled_test.go:37: performing request component=http method=GET url=http://example.com\n", out.String())
+
+	out.Reset()
+	l.Error("request failed", "error", errors.New("connection refused"), 3, "retries", "dangling")
+	assert.Equal(t, "ERROR: leveled_test.go:41: request failed !BADKEY=dangling 3=retries component=http error=\"connection refused\"\n", out.String())
+
+	t.Run("With", func(t *testing.T) {
+		out.Reset()
+		w := NewLeveledLogger("component", "http").With("component", "db", "attempt", 2)
+		w.Warn("retrying")
+		assert.Contains(t, out.String(), "retrying attempt=2 component=db\n")
+		NewLeveledLogger().Warn("no fields")
+		assert.Contains(t, out.String(), ": no fields\n")
+	})
+
+	t.Run("verbose captures", func(t *testing.T) {
+		defer CaptureVerbose("request_id", "r1", time.Minute)()
+		out.Reset()
+		l := NewLeveledLogger()
+		l.Debug("captured", "request_id", "r1")
+		l.Debug("other request", "request_id", "r2")
+		assert.Contains(t, out.String(), ": captured request_id=r1\n")
+		assert.NotContains(t, out.String(), "other request")
+	})
+}
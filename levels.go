@@ -0,0 +1,267 @@
+package golog
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SetLoggerLevel sets the level of the named logger, the loggers
+// whose LoggerKey field is name, overriding the global level. The
+// level of a name also applies to the names it prefixes, up to a
+// '/' or '.', e.g. "github.com/acme/mono/billing" applies to
+// "github.com/acme/mono/billing/invoice", and the longest matching
+// name wins. The verbose captures still enable all the levels.
+// The entries named after their package by SetLoggerNames are
+// gated by the global level first, so their named level can only
+// restrict them.
+func SetLoggerLevel(name string, lvl Level) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	cancelOverride(name)
+	setLoggerLevel(name, lvl)
+	levelsChanged()
+}
+
+func setLoggerLevel(name string, lvl Level) {
+	updateState(func(s *globalState) {
+		levels := make(map[string]Level, len(s.loggerLevels)+1)
+		for k, v := range s.loggerLevels {
+			levels[k] = v
+		}
+		levels[name] = lvl
+		s.loggerLevels = levels
+	})
+}
+
+// ResetLoggerLevel removes the level of the named logger,
+// which follows the global level again.
+func ResetLoggerLevel(name string) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	cancelOverride(name)
+	resetLoggerLevel(name)
+	levelsChanged()
+}
+
+func resetLoggerLevel(name string) {
+	updateState(func(s *globalState) {
+		levels := make(map[string]Level, len(s.loggerLevels))
+		for k, v := range s.loggerLevels {
+			if k != name {
+				levels[k] = v
+			}
+		}
+		s.loggerLevels = levels
+	})
+}
+
+// levelOverride restores a level changed for a while.
+type levelOverride struct {
+	timer *time.Timer
+	// prev is the level to restore, and set reports whether
+	// the named logger had a level.
+	prev Level
+	set  bool
+}
+
+var (
+	// overridesMu serializes the level changes with the
+	// restorations of the overrides.
+	overridesMu sync.Mutex
+	// overrides are the pending overrides by logger name,
+	// the empty name being the global level.
+	overrides = make(map[string]*levelOverride)
+)
+
+// SetLevelFor sets the global level for the duration ttl, after
+// which the previous level is restored, so a temporary debug setting
+// can't be forgotten. A change of the global level made in the
+// meantime cancels the restoration; another override extends it
+// and keeps the level to restore. A non-positive ttl sets the
+// level like SetLevel.
+func SetLevelFor(lvl Level, ttl time.Duration) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	override("", GetLevel(), true, ttl)
+	setGlobalStateLevel(lvl)
+	levelsChanged()
+}
+
+// SetLoggerLevelFor sets the level of the named logger for the
+// duration ttl, like SetLevelFor. See SetLoggerLevel.
+func SetLoggerLevelFor(name string, lvl Level, ttl time.Duration) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+	prev, set := getState().loggerLevels[name]
+	override(name, prev, set, ttl)
+	setLoggerLevel(name, lvl)
+	levelsChanged()
+}
+
+// override schedules the restoration of the level of name. It
+// must be called with overridesMu held.
+func override(name string, prev Level, set bool, ttl time.Duration) {
+	if ttl <= 0 {
+		cancelOverride(name)
+		return
+	}
+	if o, ok := overrides[name]; ok {
+		o.timer.Stop()
+		prev, set = o.prev, o.set
+	}
+	o := &levelOverride{prev: prev, set: set}
+	o.timer = time.AfterFunc(ttl, func() {
+		overridesMu.Lock()
+		defer overridesMu.Unlock()
+		if overrides[name] != o {
+			return
+		}
+		delete(overrides, name)
+		switch {
+		case name == "":
+			setGlobalStateLevel(o.prev)
+		case o.set:
+			setLoggerLevel(name, o.prev)
+		default:
+			resetLoggerLevel(name)
+		}
+		levelsChanged()
+	})
+	overrides[name] = o
+}
+
+// cancelOverride cancels the restoration of the level of name.
+// It must be called with overridesMu held.
+func cancelOverride(name string) {
+	if o, ok := overrides[name]; ok {
+		o.timer.Stop()
+		delete(overrides, name)
+	}
+}
+
+// LoggerLevels returns the levels of the named loggers.
+func LoggerLevels() map[string]Level {
+	levels := make(map[string]Level, len(getState().loggerLevels))
+	for k, v := range getState().loggerLevels {
+		levels[k] = v
+	}
+	return levels
+}
+
+// loggerLevel returns the level of the logger name.
+func (s *globalState) loggerLevel(name string) (Level, bool) {
+	for {
+		if lvl, ok := s.loggerLevels[name]; ok {
+			return lvl, true
+		}
+		i := strings.LastIndexAny(name, "/.")
+		if i < 0 {
+			return 0, false
+		}
+		name = name[:i]
+	}
+}
+
+// enabled reports whether the entries of lvl with fields are
+// logged, according to the global level, the level of the named
+// logger and the verbose captures.
+func (s *globalState) enabled(lvl Level, fields Fields) bool {
+	threshold := s.currentLevel
+	if len(s.loggerLevels) > 0 {
+		if name, ok := fields[LoggerKey].(string); ok {
+			if nl, ok := s.loggerLevel(name); ok {
+				threshold = nl
+			}
+		}
+	}
+	if lvl >= threshold {
+		return true
+	}
+	return s.isCaptured(fields)
+}
+
+// gatesOnFields reports whether enabled depends on the fields,
+// through the named levels or the verbose captures.
+func (s *globalState) gatesOnFields() bool {
+	return len(s.loggerLevels) > 0 || len(s.captures) > 0
+}
+
+// levelState is the JSON representation of the levels
+// served by LevelHandler.
+type levelState struct {
+	Level   string            `json:"level"`
+	Loggers map[string]string `json:"loggers"`
+}
+
+// levelRequest is the body of a PUT to LevelHandler.
+type levelRequest struct {
+	Logger string `json:"logger"`
+	Level  string `json:"level"`
+	TTL    string `json:"ttl"`
+}
+
+// LevelHandler returns an http.Handler to change the levels at
+// runtime. GET returns the global level and the levels of the
+// named loggers as JSON:
+//
+//	{"level":"info","loggers":{"db":"debug"}}
+//
+// PUT sets the global level, or the level of the named logger
+// when the logger is given, from a JSON body or from the query
+// parameters, and an empty level resets the level of the named
+// logger. With a ttl, the previous level is restored after it,
+// see SetLevelFor:
+//
+//	PUT /debug/level {"level":"debug"}
+//	PUT /debug/level {"logger":"db","level":"error"}
+//	PUT /debug/level?level=debug&ttl=15m
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req levelRequest
+			if q := r.URL.Query(); len(q) > 0 {
+				req = levelRequest{Logger: q.Get("logger"), Level: q.Get("level"), TTL: q.Get("ttl")}
+			} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid JSON body", http.StatusBadRequest)
+				return
+			}
+			if req.Logger != "" && req.Level == "" {
+				ResetLoggerLevel(req.Logger)
+				break
+			}
+			lvl, err := ParseLevel(req.Level)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var ttl time.Duration
+			if req.TTL != "" {
+				if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+					http.Error(w, "ttl must be a positive duration", http.StatusBadRequest)
+					return
+				}
+			}
+			if req.Logger == "" {
+				SetLevelFor(lvl, ttl)
+			} else {
+				SetLoggerLevelFor(req.Logger, lvl, ttl)
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		st := getState()
+		resp := levelState{Level: st.currentLevel.name(), Loggers: make(map[string]string, len(st.loggerLevels))}
+		for k, v := range st.loggerLevels {
+			resp.Loggers[k] = v.name()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,136 @@
+package golog
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetLoggerLevel(t *testing.T) {
+	defer func() {
+		for name := range LoggerLevels() {
+			ResetLoggerLevel(name)
+		}
+	}()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var out bytes.Buffer
+	l := newStdLogger(InfoLevel, &out, 0)
+	db := l.WithFields(Fields{LoggerKey: "github.com/acme/mono/db/postgres"})
+	web := l.WithFields(Fields{LoggerKey: "github.com/acme/mono/http"})
+
+	SetLoggerLevel("github.com/acme/mono", ErrorLevel)
+	SetLoggerLevel("github.com/acme/mono/db", DebugLevel)
+	assert.Equal(t, map[string]Level{"github.com/acme/mono": ErrorLevel, "github.com/acme/mono/db": DebugLevel}, LoggerLevels())
+
+	db.Debug("query")
+	web.Warn("slow request")
+	web.Error("failed request")
+	l.Debug("hidden")
+	l.Info("plain")
+	assert.Equal(t, "DEBUG: query logger=github.com/acme/mono/db/postgres\n"+
+		"ERROR: failed request logger=github.com/acme/mono/http\n"+
+		"INFO: plain\n", out.String())
+
+	t.Run("reset follows the global level", func(t *testing.T) {
+		out.Reset()
+		ResetLoggerLevel("github.com/acme/mono")
+		web.Warn("slow request")
+		assert.Contains(t, out.String(), "slow request")
+	})
+	t.Run("package level functions", func(t *testing.T) {
+		defer SetOutput(os.Stdout)
+		defer SetLoggerNames(false)
+		pkg := &syncBuffer{}
+		SetOutput(pkg)
+		SetLoggerNames(true)
+		SetLoggerLevel("github.com/jayvib", ErrorLevel)
+		Info("hidden")
+		Error("shown")
+		assert.NotContains(t, pkg.String(), "hidden")
+		assert.Contains(t, pkg.String(), "shown")
+	})
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer ResetLoggerLevel("db")
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	h := LevelHandler()
+	do := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, "/", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"info","loggers":{}}`, rec.Body.String())
+
+	rec = do(http.MethodPut, `{"level":"debug"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, DebugLevel, GetLevel())
+
+	rec = do(http.MethodPut, `{"logger":"db","level":"warn"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"debug","loggers":{"db":"warning"}}`, rec.Body.String())
+
+	rec = do(http.MethodPut, `{"logger":"db"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"debug","loggers":{}}`, rec.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, `{"level":"loud"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPut, `level=debug`).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, do(http.MethodPost, "").Code)
+}
+
+func TestSetLevelFor(t *testing.T) {
+	defer ResetLoggerLevel("db")
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	eventually := func(cond func() bool) {
+		assert.Eventually(t, cond, time.Second, 5*time.Millisecond)
+	}
+
+	t.Run("restores the previous level", func(t *testing.T) {
+		SetLevelFor(DebugLevel, 20*time.Millisecond)
+		SetLevelFor(TraceLevel, 20*time.Millisecond)
+		assert.Equal(t, TraceLevel, GetLevel())
+		eventually(func() bool { return GetLevel() == InfoLevel })
+	})
+	t.Run("explicit changes cancel the restoration", func(t *testing.T) {
+		SetLevelFor(DebugLevel, 20*time.Millisecond)
+		SetLevel(ErrorLevel)
+		time.Sleep(40 * time.Millisecond)
+		assert.Equal(t, ErrorLevel, GetLevel())
+		SetLevel(InfoLevel)
+	})
+	t.Run("named loggers", func(t *testing.T) {
+		SetLoggerLevelFor("db", DebugLevel, 20*time.Millisecond)
+		assert.Equal(t, map[string]Level{"db": DebugLevel}, LoggerLevels())
+		eventually(func() bool { return len(LoggerLevels()) == 0 })
+
+		SetLoggerLevel("db", ErrorLevel)
+		SetLoggerLevelFor("db", DebugLevel, 20*time.Millisecond)
+		eventually(func() bool { return LoggerLevels()["db"] == ErrorLevel })
+	})
+	t.Run("handler", func(t *testing.T) {
+		h := LevelHandler()
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/?level=debug&ttl=20ms", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, DebugLevel, GetLevel())
+		eventually(func() bool { return GetLevel() == InfoLevel })
+
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/", strings.NewReader(`{"level":"debug","ttl":"-1s"}`)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,77 @@
+package golog
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"fmt"
+)
+
+var (
+	_ flag.Value               = (*Level)(nil)
+	_ encoding.TextMarshaler   = Level(0)
+	_ encoding.TextUnmarshaler = (*Level)(nil)
+	_ json.Marshaler           = Level(0)
+	_ json.Unmarshaler         = (*Level)(nil)
+)
+
+// Set sets l to the level named s, see ParseLevel. It implements
+// the flag.Value interface, so a level can be a command line flag:
+//
+//	lvl := golog.InfoLevel
+//	flag.Var(&lvl, "level", "the log level")
+func (l *Level) Set(s string) error {
+	lvl, err := ParseLevel(s)
+	if err != nil {
+		return err
+	}
+	*l = lvl
+	return nil
+}
+
+// Type returns the type of the level flags, as reported
+// by the usage of the pflag package.
+func (l *Level) Type() string {
+	return "level"
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (l Level) MarshalText() ([]byte, error) {
+	name := l.name()
+	if name == "" {
+		return nil, fmt.Errorf("golog: unknown level %d", int(l))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (l *Level) UnmarshalText(text []byte) error {
+	return l.Set(string(text))
+}
+
+// MarshalJSON encodes l as its name, e.g. "debug".
+func (l Level) MarshalJSON() ([]byte, error) {
+	text, err := l.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes a level from its name, or from its number
+// as encoded before the levels were encoded as names.
+func (l *Level) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		return l.Set(name)
+	}
+	var n int
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("golog: invalid level %s", b)
+	}
+	if lvl := Level(n); lvl.name() != "" {
+		*l = lvl
+		return nil
+	}
+	return fmt.Errorf("golog: unknown level %d", n)
+}
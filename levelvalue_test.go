@@ -0,0 +1,89 @@
+package golog
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevel_String(t *testing.T) {
+	for _, lvl := range []Level{DebugLevel, TraceLevel, InfoLevel, WarningLevel, ErrorLevel, DisabledLevel} {
+		parsed, err := ParseLevel(lvl.String())
+		require.NoError(t, err)
+		assert.Equal(t, lvl, parsed)
+	}
+	assert.Equal(t, "warning", WarningLevel.String())
+	assert.Equal(t, "WARNING: ", WarningLevel.prefix())
+}
+
+func TestLevel_Set(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	lvl := InfoLevel
+	fs.Var(&lvl, "level", "the log level")
+	require.NoError(t, fs.Parse([]string{"-level", "WARN"}))
+	assert.Equal(t, WarningLevel, lvl)
+	assert.EqualError(t, fs.Parse([]string{"-level", "loud"}),
+		`invalid value "loud" for flag -level: golog: unknown level "loud"`)
+	assert.Equal(t, WarningLevel, lvl)
+	assert.Equal(t, "level", lvl.Type())
+}
+
+func TestLevel_MarshalText(t *testing.T) {
+	b, err := ErrorLevel.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "error", string(b))
+	_, err = Level(42).MarshalText()
+	assert.EqualError(t, err, "golog: unknown level 42")
+
+	var lvl Level
+	require.NoError(t, lvl.UnmarshalText([]byte("trace")))
+	assert.Equal(t, TraceLevel, lvl)
+
+	m := map[Level]int{DebugLevel: 1}
+	b, err = json.Marshal(m)
+	require.NoError(t, err)
+	assert.Equal(t, `{"debug":1}`, string(b))
+}
+
+func TestLevel_MarshalJSON(t *testing.T) {
+	type config struct {
+		Level Level `json:"level"`
+	}
+	b, err := json.Marshal(config{Level: WarningLevel})
+	require.NoError(t, err)
+	assert.Equal(t, `{"level":"warning"}`, string(b))
+
+	tests := []struct {
+		in   string
+		want Level
+		err  string
+	}{
+		{`{"level":"debug"}`, DebugLevel, ""},
+		{`{"level":"Info"}`, InfoLevel, ""},
+		{`{"level":4}`, ErrorLevel, ""},
+		{`{"level":"loud"}`, InfoLevel, `golog: unknown level "loud"`},
+		{`{"level":9}`, InfoLevel, "golog: unknown level 9"},
+		{`{"level":true}`, InfoLevel, "golog: invalid level true"},
+	}
+	for _, tt := range tests {
+		c := config{Level: InfoLevel}
+		err := json.Unmarshal([]byte(tt.in), &c)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, tt.in)
+		} else {
+			assert.NoError(t, err, tt.in)
+		}
+		assert.Equal(t, tt.want, c.Level, tt.in)
+	}
+
+	t.Run("mute rules", func(t *testing.T) {
+		var r MuteRule
+		require.NoError(t, json.Unmarshal([]byte(`{"level":3,"start":"02:00","end":"03:00"}`), &r))
+		assert.Equal(t, WarningLevel, r.Level)
+	})
+}
@@ -0,0 +1,54 @@
+package golog
+
+import "time"
+
+// LogfmtFormatter formats an entry as a logfmt line of key=value
+// pairs, the time, level, caller and msg keys followed by the fields:
+//
+//	time=2020-03-04T05:06:07.891011Z level=warning msg="disk almost full" used=93
+//
+// The nested fields are flattened into dotted keys. Fields that collide
+// with the keys of the formatter are prefixed with "fields.", as by
+// the JSONFormatter.
+type LogfmtFormatter struct {
+	// TimeFormat is the layout of the time.
+	// Defaults to time.RFC3339Nano.
+	TimeFormat string
+}
+
+// Format implements the Formatter interface.
+func (f *LogfmtFormatter) Format(e *Entry) ([]byte, error) {
+	return f.appendFormat(make([]byte, 0, 128+len(e.Message)), e)
+}
+
+// appendFormat appends the formatted entry e to b.
+func (f *LogfmtFormatter) appendFormat(b []byte, e *Entry) ([]byte, error) {
+	esc := getEscaper()
+	layout := f.TimeFormat
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	b = append(b, "time="...)
+	if isFastTimeLayout(layout) {
+		b = appendTime(b, e.Time, layout)
+	} else {
+		b = esc.AppendLogfmt(b, e.Time.Format(layout))
+	}
+	b = append(b, " level="...)
+	b = append(b, e.Level.name()...)
+	if e.Caller != nil {
+		b = append(b, " caller="...)
+		b = esc.AppendLogfmt(b, string(appendCaller(nil, e.Caller, CallerShortFile)))
+	}
+	b = append(b, " msg="...)
+	b = esc.AppendLogfmt(b, e.Message)
+	if e.Template != "" {
+		b = append(b, " "+MessageTemplateKey+"="...)
+		b = esc.AppendLogfmt(b, e.Template)
+	}
+	reserved := func(k string) bool { return reservedKeys[k] }
+	for _, k := range e.Fields.sortedKeys() {
+		b = appendTextField(b, fieldKey(k, e.Fields, reserved), e.Fields[k])
+	}
+	return append(b, '\n'), nil
+}
@@ -0,0 +1,31 @@
+package golog
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogfmtFormatter(t *testing.T) {
+	entry := &Entry{
+		Time:     time.Date(2020, 3, 4, 5, 6, 7, 0, time.UTC),
+		Level:    WarningLevel,
+		Message:  "disk almost full",
+		Template: "disk %s",
+		Fields:   Fields{"used": 93, "error": errors.New("no space"), "msg": "collides", "disk": Fields{"name": "sda1"}},
+		Caller:   &runtime.Frame{File: "/src/app/main.go", Line: 42},
+	}
+	got, err := (&LogfmtFormatter{}).Format(entry)
+	require.NoError(t, err)
+	assert.Equal(t, `time=2020-03-04T05:06:07Z level=warning caller=main.go:42 msg="disk almost full" msg_template="disk %s" disk.name=sda1 error="no space" fields.msg=collides used=93`+"\n", string(got))
+
+	t.Run("custom time format", func(t *testing.T) {
+		got, err := (&LogfmtFormatter{TimeFormat: time.ANSIC}).Format(&Entry{Time: entry.Time, Level: InfoLevel})
+		require.NoError(t, err)
+		assert.Equal(t, `time="Wed Mar  4 05:06:07 2020" level=info msg=""`+"\n", string(got))
+	})
+}
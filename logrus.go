@@ -1,92 +1,426 @@
 package golog
 
 import (
+	"fmt"
 	"github.com/sirupsen/logrus"
 	"io"
-	"os"
+	"strings"
+	"sync"
 )
 
-type Formatter logrus.Formatter
-type Fields logrus.Fields
+// logrusFormatter adapts a Formatter to logrus, so the
+// golog formatters can be used by the Logrus adapter.
+type logrusFormatter struct {
+	f Formatter
+}
+
+func (lf logrusFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	entry := &Entry{
+		Time:    e.Time,
+		Level:   fromLogrusLevel(e.Level),
+		Message: strings.TrimSuffix(e.Message, "\n"),
+		Fields:  Fields(e.Data),
+		Caller:  e.Caller,
+	}
+	b, err := safeFormat(lf.f, entry)
+	if err != nil {
+		reportf("golog: failed to format entry: %v", err)
+		return fallbackFormat(entry, err), nil
+	}
+	return b, nil
+}
+
+// logrusHook adapts a Hook to logrus. The changes made by
+// the hook to the message and the fields are kept.
+type logrusHook struct {
+	h Hook
+}
 
-var _ Formatter = &JSONFormatter{}
+func (lh logrusHook) Levels() []logrus.Level {
+	h, ok := lh.h.(LevelHook)
+	if !ok {
+		return logrus.AllLevels
+	}
+	levels := make([]logrus.Level, 0, len(h.Levels()))
+	for _, lvl := range h.Levels() {
+		levels = append(levels, toLogrusLevel(lvl))
+	}
+	return levels
+}
 
-type JSONFormatter struct {
-	*logrus.JSONFormatter
+func (lh logrusHook) Fire(e *logrus.Entry) error {
+	entry := &Entry{
+		Time:    e.Time,
+		Level:   fromLogrusLevel(e.Level),
+		Message: e.Message,
+		Fields:  copyFields(Fields(e.Data)),
+		Caller:  e.Caller,
+	}
+	err := lh.h.Fire(entry)
+	e.Message = entry.Message
+	e.Data = logrus.Fields(entry.Fields)
+	return err
+}
+
+func fromLogrusLevel(lvl logrus.Level) Level {
+	switch lvl {
+	case logrus.TraceLevel:
+		return TraceLevel
+	case logrus.DebugLevel:
+		return DebugLevel
+	case logrus.InfoLevel:
+		return InfoLevel
+	case logrus.WarnLevel:
+		return WarningLevel
+	}
+	return ErrorLevel
 }
 
 func NewLogrusLogger(level Level) *Logrus {
 	l := logrus.New()
 	l.SetLevel(logrus.TraceLevel)
+	l.SetOutput(sinkCounter{l.Out})
 
-	var llevel logrus.Level
+	return &Logrus{
+		logger:      l,
+		level:       level,
+		logrusLevel: toLogrusLevel(level),
+		cfg:         &logrusConfig{},
+	}
+}
 
+func toLogrusLevel(level Level) logrus.Level {
 	switch level {
 	case DebugLevel:
-		llevel = logrus.DebugLevel
+		return logrus.DebugLevel
 	case TraceLevel:
-		llevel = logrus.TraceLevel
-	case InfoLevel:
-		llevel = logrus.InfoLevel
+		return logrus.TraceLevel
 	case WarningLevel:
-		llevel = logrus.WarnLevel
+		return logrus.WarnLevel
 	case ErrorLevel:
-		llevel = logrus.ErrorLevel
-	case DisabledLevel:
-		llevel = logrus.InfoLevel
-	}
-
-	return &Logrus{
-		logger:      l,
-		level:       level,
-		logrusLevel: llevel,
+		return logrus.ErrorLevel
 	}
+	return logrus.InfoLevel
 }
 
 type Logrus struct {
 	logger      *logrus.Logger
 	level       Level
 	logrusLevel logrus.Level
+	fields      Fields
+	ctxFields   Fields
+	stackLevel  *Level
+	counters    levelCounters
+	cfg         *logrusConfig
 }
 
-func (l *Logrus) Printf(format string, v ...interface{}) {}
+// logrusConfig is the configuration of a Logrus adapter kept
+// outside of the logrus logger. It is shared by the loggers
+// derived with WithFields.
+type logrusConfig struct {
+	clock    clockValue
+	exitMu   sync.Mutex
+	exitFunc func(int)
+
+	samplersMu sync.Mutex
+	samplers   []Sampler
+
+	pauseMu sync.Mutex
+	paused  *logrusPause
+}
+
+func (c *logrusConfig) getSamplers() []Sampler {
+	c.samplersMu.Lock()
+	defer c.samplersMu.Unlock()
+	return c.samplers
+}
+
+func (l *Logrus) Printf(format string, v ...interface{}) {
+	if l.isEnabled() {
+		l.log(1, l.level, fmt.Sprintf(format, v...), format, false, nil)
+	}
+}
 func (l *Logrus) Print(v ...interface{}) {
 	if l.isEnabled() {
-		l.logger.Log(l.logrusLevel, v...)
+		l.log(1, l.level, fmt.Sprint(v...), "", false, nil)
 	}
-	return
 }
 func (l *Logrus) Println(v ...interface{}) {
 	if l.isEnabled() {
-		l.logger.Log(l.logrusLevel, v...)
+		l.log(1, l.level, fmt.Sprint(v...), "", false, nil)
 	}
 }
 func (l *Logrus) Fatal(v ...interface{}) {
 	if l.isEnabled() {
-		l.logger.Log(l.logrusLevel, v...)
-		os.Exit(1)
+		if l.log(1, l.level, fmt.Sprint(v...), "", true, nil) {
+			l.exit()
+		}
 	}
-	return
 }
 func (l *Logrus) Fatalf(format string, v ...interface{}) {
 	if l.isEnabled() {
-		l.logger.Logf(l.logrusLevel, format, v...)
+		if l.log(1, l.level, fmt.Sprintf(format, v...), format, true, nil) {
+			l.exit()
+		}
+	}
+}
+func (l *Logrus) Panic(v ...interface{}) {
+	s := fmt.Sprint(v...)
+	if l.isEnabledLevel(ErrorLevel) {
+		l.log(1, ErrorLevel, s, "", false, nil)
+	}
+	panic(s)
+}
+func (l *Logrus) Panicf(format string, v ...interface{}) {
+	s := fmt.Sprintf(format, v...)
+	if l.isEnabledLevel(ErrorLevel) {
+		l.log(1, ErrorLevel, s, format, false, nil)
+	}
+	panic(s)
+}
+func (l *Logrus) Debug(v ...interface{}) {
+	l.logLevel(DebugLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+func (l *Logrus) Debugf(format string, v ...interface{}) {
+	l.logLevel(DebugLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+func (l *Logrus) Trace(v ...interface{}) {
+	l.logLevel(TraceLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+func (l *Logrus) Tracef(format string, v ...interface{}) {
+	l.logLevel(TraceLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+func (l *Logrus) Info(v ...interface{}) {
+	l.logLevel(InfoLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+func (l *Logrus) Infof(format string, v ...interface{}) {
+	l.logLevel(InfoLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+func (l *Logrus) Warn(v ...interface{}) {
+	l.logLevel(WarningLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+func (l *Logrus) Warnf(format string, v ...interface{}) {
+	l.logLevel(WarningLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+func (l *Logrus) Error(v ...interface{}) {
+	l.logLevel(ErrorLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+func (l *Logrus) Errorf(format string, v ...interface{}) {
+	l.logLevel(ErrorLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+func (l *Logrus) Log(lvl Level, v ...interface{}) {
+	if lvl < DisabledLevel {
+		l.logLevel(lvl, "", func() string { return fmt.Sprintln(v...) })
+	}
+}
+func (l *Logrus) Logf(lvl Level, format string, v ...interface{}) {
+	if lvl < DisabledLevel {
+		l.logLevel(lvl, format, func() string { return fmt.Sprintf(format, v...) })
+	}
+}
+
+// logLevel logs the message returned by msg at lvl. The
+// format of Printf-style calls is recorded when templates
+// are enabled.
+func (l *Logrus) logLevel(lvl Level, format string, msg func() string) {
+	if l.isEnabledLevel(lvl) {
+		l.log(2, lvl, msg(), format, false, nil)
 	}
 }
+
+// log runs the entry msg of lvl, with the fields of the call, through
+// the stages of the pipeline shared with the stdLogger, then encodes
+// and writes it with logrus, and reports whether the entry was kept.
+// The callers check the level. calldepth is the number of frames
+// between log and the call site. fatal tells the entries of the Fatal
+// calls.
+func (l *Logrus) log(calldepth int, lvl Level, msg, template string, fatal bool, call Fields) bool {
+	st := getState()
+	if st.isMuted(lvl, func() string { return msg }) {
+		return false
+	}
+	e := &Entry{
+		Time:    l.cfg.clock.now(),
+		Level:   lvl,
+		Message: strings.TrimSuffix(msg, "\n"),
+		Fields:  l.entryFields(st, call),
+		fatal:   fatal,
+	}
+	if st.messageTemplate {
+		e.Template = template
+	}
+	if !admit(st, e, l.cfg.getSamplers(), &l.counters) {
+		return false
+	}
+	threshold := st.stackLevel
+	if l.stackLevel != nil {
+		threshold = *l.stackLevel
+	}
+	if captureStack(threshold, lvl, fatal) {
+		addCallField(e, StacktraceKey, stacktrace(calldepth+1))
+	}
+	process(st, e, nil)
+
+	entry := logrus.NewEntry(l.logger).WithTime(e.Time)
+	fields := logrus.Fields(e.Fields)
+	if e.Template != "" {
+		fields = logrus.Fields(copyFields(e.Fields))
+		fields[MessageTemplateKey] = e.Template
+	}
+	if len(fields) > 0 {
+		entry = entry.WithFields(fields)
+	}
+	entry.Log(toLogrusLevel(lvl), e.Message)
+	escalate(st.escalations, e)
+	return true
+}
 func (l *Logrus) SetOutput(w io.Writer) {
-	l.logger.SetOutput(w)
+	l.cfg.pauseMu.Lock()
+	defer l.cfg.pauseMu.Unlock()
+	if p := l.cfg.paused; p != nil {
+		p.mu.Lock()
+		p.target = sinkCounter{w}
+		p.mu.Unlock()
+		return
+	}
+	l.logger.SetOutput(sinkCounter{w})
+}
+
+// Out returns the output of the logger, where the entries
+// go once resumed when the logger is paused.
+func (l *Logrus) Out() io.Writer {
+	l.cfg.pauseMu.Lock()
+	defer l.cfg.pauseMu.Unlock()
+	w := l.logger.Out
+	if p := l.cfg.paused; p != nil {
+		p.mu.Lock()
+		w = p.target
+		p.mu.Unlock()
+	}
+	if c, ok := w.(sinkCounter); ok {
+		w = c.w
+	}
+	return w
 }
 func (l *Logrus) SetFormatter(formatter Formatter) {
-	l.logger.SetFormatter(formatter)
+	l.logger.SetFormatter(logrusFormatter{formatter})
+}
+func (l *Logrus) SetClock(c Clock) {
+	l.cfg.clock.set(c)
 }
-func (l *Logrus) WithFields(fields Fields) Logger {
+func (l *Logrus) AddHook(h Hook) {
+	l.logger.AddHook(logrusHook{h})
+}
+
+// AddSampler registers s to be consulted on every entry of l
+// and of the loggers derived with WithFields.
+func (l *Logrus) AddSampler(s Sampler) {
+	l.cfg.samplersMu.Lock()
+	defer l.cfg.samplersMu.Unlock()
+	samplers := make([]Sampler, len(l.cfg.samplers), len(l.cfg.samplers)+1)
+	copy(samplers, l.cfg.samplers)
+	l.cfg.samplers = append(samplers, s)
+}
+
+// WithFields returns a logger that shares the level, output and
+// configuration of l and attaches fields, merged with the fields
+// of l, to every entry. The global level gate still applies.
+func (l *Logrus) WithFields(fields Fields) FieldLogger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	c := l.clone()
+	c.fields = merged
+	return c
+}
+
+// withContextFields returns a logger like l with the fields of
+// the context merged with the context fields of l.
+func (l *Logrus) withContextFields(fields Fields) FieldLogger {
+	c := l.clone()
+	c.ctxFields = mergeFields(SpecificFieldsWin, l.ctxFields, fields)
+	return c
+}
+
+// AddStacktrace returns a logger like l whose entries of lvl and
+// above capture the stack of the goroutine logging them, overriding
+// the level of SetStacktraceLevel.
+func (l *Logrus) AddStacktrace(lvl Level) FieldLogger {
+	c := l.clone()
+	c.stackLevel = &lvl
+	return c
+}
+
+// clone returns a logger sharing the configuration
+// of l with counters of its own.
+func (l *Logrus) clone() *Logrus {
+	return &Logrus{
+		logger:      l.logger,
+		level:       l.level,
+		logrusLevel: l.logrusLevel,
+		fields:      l.fields,
+		ctxFields:   l.ctxFields,
+		stackLevel:  l.stackLevel,
+		cfg:         l.cfg,
+	}
+}
+
+// AddCallerSkip returns l, as the Logrus adapter
+// doesn't report the caller.
+func (l *Logrus) AddCallerSkip(n int) FieldLogger {
 	return l
 }
 
+// With is a shorthand for WithFields.
+func (l *Logrus) With(fields Fields) FieldLogger {
+	return l.WithFields(fields)
+}
+
+// Named returns a logger like l whose name is name, appended
+// with a dot to the name of l.
+func (l *Logrus) Named(name string) FieldLogger {
+	return l.WithFields(Fields{LoggerKey: childName(l.fields, name)})
+}
+
+// Counters returns the number of entries emitted by
+// the logger per level.
+func (l *Logrus) Counters() map[Level]uint64 {
+	return l.counters.snapshot()
+}
+
+// entryFields returns the fields of l merged with the fields of its
+// context, the global fields and the fields of the call according to
+// the field precedence.
+func (l *Logrus) entryFields(st *globalState, call Fields) Fields {
+	if len(st.globalFields) == 0 && len(l.ctxFields) == 0 && len(call) == 0 {
+		return l.fields
+	}
+	return mergeFields(st.fieldPrecedence, st.globalFields, l.ctxFields, l.fields, call)
+}
+
 func (l *Logrus) isEnabled() bool {
-	gstate := getState()
-	if l.level < gstate.currentLevel {
-		return false
+	return l.isEnabledLevel(l.level)
+}
+
+func (l *Logrus) isEnabledLevel(lvl Level) bool {
+	return l.isEnabledCall(lvl, nil)
+}
+
+// isEnabledCall reports whether the entries of l at lvl with
+// the fields of the call are enabled, or kept by the context
+// bundling.
+func (l *Logrus) isEnabledCall(lvl Level, call Fields) bool {
+	st := getState()
+	fields := l.fields
+	if st.gatesOnFields() {
+		fields = l.entryFields(st, call)
 	}
-	return true
+	if st.enabled(lvl, fields) {
+		return true
+	}
+	return st.bundle != nil && st.bundle.keeps(lvl, l.fields, l.ctxFields, call)
 }
@@ -2,13 +2,15 @@ package golog
 
 import (
 	"bytes"
-	"github.com/stretchr/testify/assert"
 	"strings"
 	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestLogrus_Print(t *testing.T) {
-	t.Run("Global state level is lower than current level", func(t *testing.T){
+	t.Run("Global state level is lower than current level", func(t *testing.T) {
 		var out bytes.Buffer
 		SetLevel(DebugLevel)
 		l := NewLogrusLogger(InfoLevel)
@@ -19,7 +21,7 @@ func TestLogrus_Print(t *testing.T) {
 		assert.True(t, strings.Contains(out.String(), "info"))
 	})
 
-	t.Run("Global state level is higher than the current level", func(t *testing.T){
+	t.Run("Global state level is higher than the current level", func(t *testing.T) {
 		var out bytes.Buffer
 		SetLevel(DisabledLevel)
 		l := NewLogrusLogger(InfoLevel)
@@ -29,3 +31,63 @@ func TestLogrus_Print(t *testing.T) {
 	})
 }
 
+func TestLogrus_Printf(t *testing.T) {
+	t.Run("message is interpolated", func(t *testing.T) {
+		var out bytes.Buffer
+		SetLevel(InfoLevel)
+		l := NewLogrusLogger(InfoLevel)
+		l.logger.SetOutput(&out)
+		l.Printf("hello %s", "world")
+		assert.Contains(t, out.String(), "hello world")
+		assert.NotContains(t, out.String(), MessageTemplateKey)
+	})
+
+	t.Run("message template is recorded when enabled", func(t *testing.T) {
+		var out bytes.Buffer
+		SetLevel(InfoLevel)
+		SetMessageTemplate(true)
+		defer SetMessageTemplate(false)
+		l := NewLogrusLogger(InfoLevel)
+		l.logger.SetOutput(&out)
+		l.logger.SetFormatter(&logrus.JSONFormatter{})
+		l.Printf("user %d logged in", 42)
+		assert.Contains(t, out.String(), `"msg":"user 42 logged in"`)
+		assert.Contains(t, out.String(), `"msg_template":"user %d logged in"`)
+	})
+}
+
+func TestLogrus_SetFormatter(t *testing.T) {
+	var out bytes.Buffer
+	SetLevel(InfoLevel)
+	l := NewLogrusLogger(WarningLevel)
+	l.SetOutput(&out)
+	l.SetFormatter(&JSONFormatter{})
+	l.Print("hello world")
+	assert.Contains(t, out.String(), `"level":"warning"`)
+	assert.Contains(t, out.String(), `"msg":"hello world"`)
+}
+
+func TestLogrus_WithFields(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var out bytes.Buffer
+	l := NewLogrusLogger(InfoLevel)
+	l.SetOutput(&out)
+	l.SetFormatter(&TextFormatter{})
+
+	child := l.WithFields(Fields{"request_id": "abc"})
+	grandchild := child.WithFields(Fields{"user": "alice", "request_id": "def"})
+	child.Printf("hello %s", "world")
+	grandchild.Print("bye")
+	l.Print("plain")
+	assert.Equal(t, "INFO: hello world request_id=abc\n"+
+		"INFO: bye request_id=def user=alice\n"+
+		"INFO: plain\n", out.String())
+
+	t.Run("honors the global level", func(t *testing.T) {
+		out.Reset()
+		SetLevel(ErrorLevel)
+		child.Print("hidden")
+		assert.Empty(t, out.String())
+	})
+}
@@ -0,0 +1,196 @@
+package golog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// now returns the current time. It is a variable
+// so tests can replace it.
+var now = time.Now
+
+// MuteRule suppresses entries during a daily time window.
+//
+// For example, to suppress a known nightly-maintenance warning:
+//
+//	MuteRule{Level: WarningLevel, Match: "replica lag", Start: "02:00", End: "03:00"}
+//
+// or to drop Debug entries during business hours:
+//
+//	MuteRule{Level: DebugLevel, Start: "09:00", End: "17:00", Weekdays: []time.Weekday{time.Monday, ...}}
+type MuteRule struct {
+	// Level is the highest level muted by the rule.
+	Level Level `json:"level"`
+	// Match, when not empty, restricts the rule to the messages
+	// that contain it.
+	Match string `json:"match,omitempty"`
+	// Start and End delimit the window in "15:04" format. A window
+	// whose End is before its Start spans midnight.
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Weekdays, when not empty, restricts the rule to these days.
+	Weekdays []time.Weekday `json:"weekdays,omitempty"`
+	// Location is the time zone of the window, encoded in JSON
+	// by its name, e.g. "Europe/Paris". Defaults to time.Local.
+	Location *time.Location `json:"-"`
+}
+
+// jsonMuteRule is the JSON encoding of a MuteRule,
+// whose Location is replaced by its name.
+type jsonMuteRule struct {
+	muteRuleFields
+	Location string `json:"location,omitempty"`
+}
+
+// muteRuleFields has the fields of a MuteRule
+// but not its JSON methods.
+type muteRuleFields MuteRule
+
+// MarshalJSON implements the json.Marshaler interface.
+func (r MuteRule) MarshalJSON() ([]byte, error) {
+	j := jsonMuteRule{muteRuleFields: muteRuleFields(r)}
+	if r.Location != nil {
+		j.Location = r.Location.String()
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (r *MuteRule) UnmarshalJSON(b []byte) error {
+	var j jsonMuteRule
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	rule := MuteRule(j.muteRuleFields)
+	if j.Location != "" {
+		loc, err := loadLocation(j.Location)
+		if err != nil {
+			return err
+		}
+		rule.Location = loc
+	}
+	*r = rule
+	return nil
+}
+
+// loadLocation returns the time zone of the given name.
+func loadLocation(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("golog: invalid location %q: %v", name, err)
+	}
+	return loc, nil
+}
+
+// muteRule is a MuteRule with its window parsed
+// into minutes since midnight.
+type muteRule struct {
+	MuteRule
+	start, end int
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("golog: invalid clock time %q, want HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (r muteRule) matches(lvl Level, msg string, t time.Time) bool {
+	if lvl > r.Level {
+		return false
+	}
+	if r.Match != "" && !strings.Contains(msg, r.Match) {
+		return false
+	}
+	loc := r.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+	if len(r.Weekdays) > 0 {
+		found := false
+		for _, d := range r.Weekdays {
+			if d == t.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	m := t.Hour()*60 + t.Minute()
+	if r.start <= r.end {
+		return m >= r.start && m < r.end
+	}
+	return m >= r.start || m < r.end
+}
+
+// SetMuteRules replaces the mute rules of the package. It can be
+// called at any time, calling it without rules removes all of them.
+func SetMuteRules(rules ...MuteRule) error {
+	compiled, err := compileMuteRules(rules)
+	if err != nil {
+		return err
+	}
+	updateState(func(s *globalState) {
+		s.muteRules = compiled
+	})
+	return nil
+}
+
+// compileMuteRules parses the windows of the rules. The weekdays
+// are copied, so the caller can't change the rules in effect.
+func compileMuteRules(rules []MuteRule) ([]muteRule, error) {
+	compiled := make([]muteRule, 0, len(rules))
+	for _, r := range rules {
+		start, err := parseClock(r.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(r.End)
+		if err != nil {
+			return nil, err
+		}
+		r.Weekdays = copyWeekdays(r.Weekdays)
+		compiled = append(compiled, muteRule{MuteRule: r, start: start, end: end})
+	}
+	return compiled, nil
+}
+
+// MuteRules returns the mute rules in effect.
+func MuteRules() []MuteRule {
+	rules := getState().muteRules
+	out := make([]MuteRule, len(rules))
+	for i, r := range rules {
+		out[i] = r.MuteRule
+		out[i].Weekdays = copyWeekdays(r.Weekdays)
+	}
+	return out
+}
+
+func copyWeekdays(days []time.Weekday) []time.Weekday {
+	if days == nil {
+		return nil
+	}
+	return append([]time.Weekday(nil), days...)
+}
+
+// isMuted reports whether an entry is suppressed by the mute rules
+// of the state. msg is only called when there are rules to check.
+func (s *globalState) isMuted(lvl Level, msg func() string) bool {
+	if len(s.muteRules) == 0 {
+		return false
+	}
+	m, t := msg(), now()
+	for _, r := range s.muteRules {
+		if r.matches(lvl, m, t) {
+			return true
+		}
+	}
+	return false
+}
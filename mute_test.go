@@ -0,0 +1,138 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMuteRules(t *testing.T) {
+	defer func() { now = time.Now }()
+	defer SetMuteRules()
+	SetLevel(DebugLevel)
+	defer SetLevel(InfoLevel)
+
+	at := func(clock string, day time.Weekday) {
+		c, _ := time.Parse("15:04", clock)
+		// 2020-06-07 is a Sunday
+		d := time.Date(2020, 6, 7+int(day), c.Hour(), c.Minute(), 0, 0, time.UTC)
+		now = func() time.Time { return d }
+	}
+	newLogger := func(lvl Level) (*stdLogger, *bytes.Buffer) {
+		out := &bytes.Buffer{}
+		return newStdLogger(lvl, out, 0), out
+	}
+
+	t.Run("warning matching the message is muted inside the window", func(t *testing.T) {
+		err := SetMuteRules(MuteRule{Level: WarningLevel, Match: "maintenance", Start: "02:00", End: "03:00", Location: time.UTC})
+		assert.NoError(t, err)
+		l, out := newLogger(WarningLevel)
+
+		at("02:30", time.Monday)
+		l.Print("nightly maintenance running")
+		assert.Empty(t, out.String())
+		l.Print("disk almost full")
+		assert.Contains(t, out.String(), "disk almost full")
+
+		out.Reset()
+		at("03:00", time.Monday)
+		l.Print("nightly maintenance running")
+		assert.Contains(t, out.String(), "maintenance")
+	})
+	t.Run("higher levels are not muted", func(t *testing.T) {
+		err := SetMuteRules(MuteRule{Level: WarningLevel, Start: "00:00", End: "23:59", Location: time.UTC})
+		assert.NoError(t, err)
+		l, out := newLogger(ErrorLevel)
+		at("12:00", time.Monday)
+		l.Print("boom")
+		assert.Contains(t, out.String(), "boom")
+	})
+	t.Run("weekdays restrict the rule", func(t *testing.T) {
+		err := SetMuteRules(MuteRule{
+			Level:    TraceLevel,
+			Start:    "09:00",
+			End:      "17:00",
+			Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+			Location: time.UTC,
+		})
+		assert.NoError(t, err)
+		l, out := newLogger(DebugLevel)
+
+		at("10:00", time.Tuesday)
+		l.Print("business hours")
+		assert.Empty(t, out.String())
+
+		at("10:00", time.Saturday)
+		l.Print("weekend")
+		assert.Contains(t, out.String(), "weekend")
+	})
+	t.Run("weekdays are copied", func(t *testing.T) {
+		days := []time.Weekday{time.Tuesday}
+		err := SetMuteRules(MuteRule{Level: InfoLevel, Start: "09:00", End: "17:00", Weekdays: days, Location: time.UTC})
+		assert.NoError(t, err)
+		days[0] = time.Saturday
+		MuteRules()[0].Weekdays[0] = time.Saturday
+		l, out := newLogger(InfoLevel)
+
+		at("10:00", time.Tuesday)
+		l.Print("tuesday")
+		assert.Empty(t, out.String())
+
+		at("10:00", time.Saturday)
+		l.Print("saturday")
+		assert.Contains(t, out.String(), "saturday")
+	})
+	t.Run("window spanning midnight", func(t *testing.T) {
+		err := SetMuteRules(MuteRule{Level: InfoLevel, Start: "22:00", End: "06:00", Location: time.UTC})
+		assert.NoError(t, err)
+		l, out := newLogger(InfoLevel)
+
+		at("23:30", time.Monday)
+		l.Print("late")
+		at("05:59", time.Monday)
+		l.Print("early")
+		assert.Empty(t, out.String())
+
+		at("12:00", time.Monday)
+		l.Print("noon")
+		assert.Contains(t, out.String(), "noon")
+	})
+	t.Run("window in a location", func(t *testing.T) {
+		var rule MuteRule
+		err := json.Unmarshal([]byte(`{"level":"info","start":"09:00","end":"17:00","location":"America/New_York"}`), &rule)
+		require.NoError(t, err)
+		assert.Equal(t, "America/New_York", rule.Location.String())
+		require.NoError(t, SetMuteRules(rule))
+		l, out := newLogger(InfoLevel)
+
+		// 14:00 UTC is 10:00 in New York in June.
+		at("14:00", time.Monday)
+		l.Print("morning")
+		assert.Empty(t, out.String())
+
+		at("08:00", time.Monday)
+		l.Print("night")
+		assert.Contains(t, out.String(), "night")
+
+		b, err := json.Marshal(rule)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"level":"info","start":"09:00","end":"17:00","location":"America/New_York"}`, string(b))
+
+		err = json.Unmarshal([]byte(`{"level":"info","start":"09:00","end":"17:00","location":"Mars/Olympus"}`), &rule)
+		assert.Error(t, err)
+	})
+	t.Run("invalid clock time", func(t *testing.T) {
+		assert.Error(t, SetMuteRules(MuteRule{Start: "2am", End: "03:00"}))
+	})
+	t.Run("rules can be listed and removed", func(t *testing.T) {
+		rule := MuteRule{Level: InfoLevel, Start: "01:00", End: "02:00"}
+		assert.NoError(t, SetMuteRules(rule))
+		assert.Equal(t, []MuteRule{rule}, MuteRules())
+		assert.NoError(t, SetMuteRules())
+		assert.Empty(t, MuteRules())
+	})
+}
@@ -0,0 +1,114 @@
+package golog
+
+import (
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// LoggerKey is the field key holding the name of the
+// logger when logger names are enabled.
+const LoggerKey = "logger"
+
+// SetLoggerNames enables or disables naming the entries of the
+// package level functions after the package calling them. The
+// name is the import path of the package, so in a monorepo it is
+// prefixed by the module path, and is recorded as the LoggerKey
+// field unless the entry already has one. The package main is
+// named after the main module. The names are cached per call site.
+func SetLoggerNames(enabled bool) {
+	updateState(func(s *globalState) {
+		s.loggerNames = enabled
+	})
+}
+
+// childName returns the name of a logger named name derived from
+// a logger with fields: name is appended with a dot to the name of
+// the parent, held by the LoggerKey field. The name is reported as
+// the LoggerKey field and the levels of SetLoggerLevel apply to it.
+func childName(fields Fields, name string) string {
+	if parent, ok := fields[LoggerKey].(string); ok && parent != "" {
+		return parent + "." + name
+	}
+	return name
+}
+
+// loggerNames caches the package names per program counter.
+var loggerNames sync.Map
+
+// callerPackage returns the import path of the package
+// of the function calldepth levels above its caller.
+func callerPackage(calldepth int) string {
+	var pcs [1]uintptr
+	if runtime.Callers(calldepth+2, pcs[:]) == 0 {
+		return ""
+	}
+	if name, ok := loggerNames.Load(pcs[0]); ok {
+		return name.(string)
+	}
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	name := packagePath(frame.Function)
+	loggerNames.Store(pcs[0], name)
+	return name
+}
+
+// packagePath returns the import path of the package of the
+// fully qualified function name fn.
+func packagePath(fn string) string {
+	slash := strings.LastIndexByte(fn, '/')
+	if dot := strings.IndexByte(fn[slash+1:], '.'); dot >= 0 {
+		fn = fn[:slash+1+dot]
+	}
+	if fn == "main" {
+		return mainModule()
+	}
+	return fn
+}
+
+var (
+	mainModuleOnce sync.Once
+	mainModulePath string
+)
+
+// mainModule returns the path of the main module,
+// or "main" when it is unknown.
+func mainModule() string {
+	mainModuleOnce.Do(func() {
+		mainModulePath = "main"
+		if bi, ok := debug.ReadBuildInfo(); ok && bi.Main.Path != "" {
+			mainModulePath = bi.Main.Path
+		}
+	})
+	return mainModulePath
+}
+
+// isPackageLogger reports whether l is a package level logger.
+func (l *stdLogger) isPackageLogger() bool {
+	for _, p := range packageLoggers() {
+		if l == p {
+			return true
+		}
+	}
+	return false
+}
+
+// nameEntry records the package of the caller calldepth levels
+// above the caller of nameEntry as the logger of e.
+func nameEntry(e *Entry, calldepth int) {
+	if _, ok := e.Fields[LoggerKey]; ok {
+		return
+	}
+	var name string
+	if e.Caller != nil {
+		name = packagePath(e.Caller.Function)
+	} else {
+		name = callerPackage(calldepth + 1)
+	}
+	fields := make(Fields, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	fields[LoggerKey] = name
+	e.Fields = fields
+}
@@ -0,0 +1,87 @@
+package golog
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackagePath(t *testing.T) {
+	cases := map[string]string{
+		"github.com/acme/mono/billing.(*Invoice).Send": "github.com/acme/mono/billing",
+		"github.com/acme/mono/billing.Run.func1":       "github.com/acme/mono/billing",
+		"github.com/acme/mono/billing/v2.New[...]":     "github.com/acme/mono/billing/v2",
+		"net/http.(*conn).serve":                       "net/http",
+		"github.com/acme/go.mono/svc.Handler":          "github.com/acme/go.mono/svc",
+		"main.main":                                    mainModule(),
+	}
+	for fn, want := range cases {
+		assert.Equal(t, want, packagePath(fn), fn)
+	}
+}
+
+func TestSetLoggerNames(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer func() {
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+	defer SetLevel(InfoLevel)
+	defer SetLoggerNames(false)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetFormatter(&JSONFormatter{})
+
+	Info("disabled")
+	assert.NotContains(t, out.String(), `"logger"`)
+
+	SetLoggerNames(true)
+	for i := 0; i < 2; i++ {
+		out.Reset()
+		Infof("hello %d", i)
+		assert.Contains(t, out.String(), `"logger":"github.com/jayvib/golog"`)
+	}
+
+	t.Run("only the package level functions are named", func(t *testing.T) {
+		out.Reset()
+		l := newStdLogger(InfoLevel, out, 0)
+		l.SetFormatter(&JSONFormatter{})
+		l.Print("hello")
+		assert.NotContains(t, out.String(), `"logger"`)
+	})
+}
+
+func TestLogger_Named(t *testing.T) {
+	defer ResetLoggerLevel("api.db")
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	loggers := map[string]func(w *syncBuffer) FieldLogger{
+		"std": func(w *syncBuffer) FieldLogger { return newStdLogger(InfoLevel, w, 0) },
+		"logrus": func(w *syncBuffer) FieldLogger {
+			l := NewLogrusLogger(InfoLevel)
+			l.SetOutput(w)
+			l.SetFormatter(&TextFormatter{})
+			return l
+		},
+	}
+	for name, newLogger := range loggers {
+		t.Run(name, func(t *testing.T) {
+			out := &syncBuffer{}
+			api := newLogger(out).Named("api")
+			db := api.With(Fields{"conn": 1}).Named("db")
+			api.Info("started")
+			db.Info("connected")
+			assert.Equal(t, "INFO: started logger=api\nINFO: connected conn=1 logger=api.db\n", out.String())
+
+			out.Reset()
+			SetLoggerLevel("api.db", ErrorLevel)
+			db.Info("hidden")
+			api.Info("shown")
+			assert.Equal(t, "INFO: shown logger=api\n", out.String())
+			ResetLoggerLevel("api.db")
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package golog
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	// changesMu protects changes.
+	changesMu sync.Mutex
+	// changes are the last values logged by OnChange per key.
+	changes = make(map[string]interface{})
+)
+
+// OnChange logs value at InfoLevel, as the key field of the entry
+// "<key> changed", only when it differs from the last value logged
+// for key, suppressing the repeated status lines of reconciliation
+// loops:
+//
+//	golog.OnChange("leader", currentLeader)
+//
+// The previous value is included as the previous field. The values
+// are compared with reflect.DeepEqual. OnChange reports whether the
+// value was logged; a value suppressed by the level is still
+// remembered. The last value of every key is kept in memory, so the
+// keys must not be unbounded.
+func OnChange(key string, value interface{}) bool {
+	changesMu.Lock()
+	prev, seen := changes[key]
+	if seen && reflect.DeepEqual(prev, value) {
+		changesMu.Unlock()
+		return false
+	}
+	changes[key] = value
+	changesMu.Unlock()
+
+	if !InfoLogger.isPrint() {
+		return true
+	}
+	fields := Fields{key: value}
+	if seen {
+		fields["previous"] = prev
+	}
+	InfoLogger.WithFields(fields).(*stdLogger).Output(stdCallDepth, key+" changed")
+	return true
+}
+
+// ForgetChange forgets the last value logged by OnChange for key,
+// so the next value is logged whatever it is.
+func ForgetChange(key string) {
+	changesMu.Lock()
+	defer changesMu.Unlock()
+	delete(changes, key)
+}
@@ -0,0 +1,46 @@
+package golog
+
+import (
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnChange(t *testing.T) {
+	defer ForgetChange("leader")
+	defer InfoLogger.SetOutput(os.Stdout)
+	defer InfoLogger.SetFormatter(&TextFormatter{Flags: defaultFlags(InfoLevel)})
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	InfoLogger.SetOutput(out)
+	InfoLogger.SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+
+	assert.True(t, OnChange("leader", "node-1"))
+	assert.False(t, OnChange("leader", "node-1"))
+	assert.True(t, OnChange("leader", "node-2"))
+	assert.False(t, OnChange("leader", "node-2"))
+	assert.Regexp(t, `^INFO: onchange_test.go:\d+: leader changed leader=node-1
+INFO: onchange_test.go:\d+: leader changed leader=node-2 previous=node-1
+$`, out.String())
+
+	t.Run("compares deeply", func(t *testing.T) {
+		defer ForgetChange("peers")
+		assert.True(t, OnChange("peers", []string{"a", "b"}))
+		assert.False(t, OnChange("peers", []string{"a", "b"}))
+	})
+	t.Run("remembers suppressed values", func(t *testing.T) {
+		out.Reset()
+		SetLevel(ErrorLevel)
+		assert.True(t, OnChange("leader", "node-3"))
+		SetLevel(InfoLevel)
+		assert.False(t, OnChange("leader", "node-3"))
+		assert.Empty(t, out.String())
+	})
+	t.Run("forget", func(t *testing.T) {
+		ForgetChange("leader")
+		assert.True(t, OnChange("leader", "node-3"))
+	})
+}
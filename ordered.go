@@ -0,0 +1,35 @@
+package golog
+
+import "sync"
+
+// orderedMu serializes the emission of all the entries
+// when the ordered delivery is enabled.
+var orderedMu sync.Mutex
+
+// SetOrderedDelivery enables or disables the ordered delivery. When
+// enabled, the entries of all the loggers are emitted by a single
+// serialized dispatcher: their timestamps are taken, and they are
+// processed, fired to the hooks and written to the outputs, in one
+// global order. An AsyncHook started in this mode uses a single
+// worker, so its Hook is fired in the same order. It trades the
+// throughput of concurrent loggers for consumers whose correctness
+// depends on the ordering. Loggers sharing no output are otherwise
+// written concurrently.
+//
+// The Logrus adapter delivers its entries through logrus and is
+// not covered.
+func SetOrderedDelivery(enabled bool) {
+	updateState(func(s *globalState) {
+		s.ordered = enabled
+	})
+}
+
+// lockOrdered takes the dispatcher lock when the ordered delivery
+// is enabled in st and returns the function releasing it.
+func lockOrdered(st *globalState) (unlock func()) {
+	if !st.ordered {
+		return func() {}
+	}
+	orderedMu.Lock()
+	return orderedMu.Unlock
+}
@@ -0,0 +1,60 @@
+package golog
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetOrderedDelivery(t *testing.T) {
+	defer SetOrderedDelivery(false)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	SetOrderedDelivery(true)
+
+	// Every logger has its own output, so only the ordered
+	// delivery keeps the sinks consistent with each other.
+	var mu sync.Mutex
+	var fired []string
+	h := &AsyncHook{
+		Hook: HookFunc(func(e *Entry) error {
+			mu.Lock()
+			defer mu.Unlock()
+			fired = append(fired, e.Message)
+			return nil
+		}),
+		Workers: 4,
+	}
+	var times []time.Time
+	var seen []string
+	record := HookFunc(func(e *Entry) error {
+		times = append(times, e.Time)
+		seen = append(seen, e.Message)
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		l := newStdLogger(InfoLevel, &syncBuffer{}, 0)
+		l.AddHook(record)
+		l.AddHook(h)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Print(strconv.Itoa(i*100 + j))
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.NoError(t, h.Close())
+
+	assert.Len(t, seen, 400)
+	assert.Equal(t, seen, fired)
+	for i := 1; i < len(times); i++ {
+		assert.False(t, times[i].Before(times[i-1]), "entry %d is older than its predecessor", i)
+	}
+}
@@ -0,0 +1,11 @@
+module github.com/jayvib/golog/otellog
+
+go 1.14
+
+require (
+	github.com/jayvib/golog v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/otel/trace v1.0.0
+)
+
+replace github.com/jayvib/golog => ../
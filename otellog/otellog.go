@@ -0,0 +1,45 @@
+// Package otellog correlates the golog entries with the OpenTelemetry
+// traces: once imported, the entries logged with a logger returned by
+// golog.FromContext, and the adapters reading golog.ContextFields, carry
+// the trace_id and span_id fields of the span active in the context, so
+// the APM can jump from a log line to its distributed trace:
+//
+//	import _ "github.com/jayvib/golog/otellog"
+//
+//	func handle(ctx context.Context) {
+//		ctx, span := tracer.Start(ctx, "handle")
+//		defer span.End()
+//		golog.FromContext(ctx).Info("handling the request")
+//	}
+package otellog
+
+import (
+	"context"
+
+	"github.com/jayvib/golog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The fields of the span active in the context.
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+func init() {
+	golog.AddContextExtractor(Fields)
+}
+
+// Fields returns the trace_id and span_id fields of the span active
+// in ctx, or nil when there is no valid span. The IDs are formatted
+// in lowercase hexadecimal, as in the W3C traceparent header.
+func Fields(ctx context.Context) golog.Fields {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+	return golog.Fields{
+		TraceIDKey: sc.TraceID().String(),
+		SpanIDKey:  sc.SpanID().String(),
+	}
+}
@@ -0,0 +1,44 @@
+package otellog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/jayvib/golog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestFields(t *testing.T) {
+	defer golog.SetOutput(os.Stdout)
+	defer golog.SetFormatter(nil)
+	var out bytes.Buffer
+	golog.SetOutput(&out)
+	golog.SetFormatter(&golog.JSONFormatter{})
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	ctx = golog.ContextWithFields(ctx, golog.Fields{"request_id": "42"})
+
+	golog.FromContext(ctx).Info("handling the request")
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry["trace_id"])
+	assert.Equal(t, "00f067aa0ba902b7", entry["span_id"])
+	assert.Equal(t, "42", entry["request_id"])
+
+	t.Run("no span", func(t *testing.T) {
+		assert.Nil(t, Fields(context.Background()))
+		out.Reset()
+		golog.FromContext(context.Background()).Info("no span")
+		assert.NotContains(t, out.String(), "trace_id")
+	})
+}
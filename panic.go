@@ -0,0 +1,95 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxPanicFrames is the deepest stack reported by PanicFields.
+const maxPanicFrames = 64
+
+// PanicFields returns the structured fields describing the recovered
+// panic value r, so the panics can be grouped by type and frame:
+//
+//   - panic.type is the dynamic type of r, e.g. "runtime.boundsError".
+//   - panic.value is the message of r, from its Error or String method
+//     when it has one.
+//   - panic.errors is the chain of the errors wrapped by r, one
+//     "type: message" element per error, when r is an error.
+//   - panic.frame is the function and line that panicked.
+//   - panic.stack is the stack of the panicking goroutine.
+//
+// PanicFields must be called from the deferred function recovering r
+// for the stack to be the one of the panic:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			golog.ErrorLogger.WithFields(golog.PanicFields(r)).Print("recovered")
+//		}
+//	}()
+func PanicFields(r interface{}) Fields {
+	fields := Fields{
+		"panic.type":  fmt.Sprintf("%T", r),
+		"panic.value": panicValue(r),
+	}
+	if err, ok := r.(error); ok {
+		var chain []string
+		for ; err != nil; err = errors.Unwrap(err) {
+			chain = append(chain, fmt.Sprintf("%T: %v", err, err))
+		}
+		fields["panic.errors"] = chain
+	}
+	if stack := panicStack(); len(stack) > 0 {
+		fields["panic.frame"] = stack[0]
+		fields["panic.stack"] = stack
+	}
+	return fields
+}
+
+// panicValue returns the message of the panic value r.
+func panicValue(r interface{}) string {
+	switch v := r.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	case string:
+		return v
+	}
+	return fmt.Sprint(r)
+}
+
+// panicStack returns the frames below the panic, formatted as
+// "function file:line", or the frames of the caller of PanicFields
+// when it isn't called during a panic.
+func panicStack() []string {
+	var pcs [maxPanicFrames]uintptr
+	n := runtime.Callers(3, pcs[:])
+	var all []runtime.Frame
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		all = append(all, frame)
+		if !more {
+			break
+		}
+	}
+	start := 0
+	for i, frame := range all {
+		if frame.Function == "runtime.gopanic" {
+			start = i + 1
+			for start < len(all) && strings.HasPrefix(all[start].Function, "runtime.") {
+				start++
+			}
+			break
+		}
+	}
+	stack := make([]string, 0, len(all)-start)
+	for _, frame := range all[start:] {
+		stack = append(stack, frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line))
+	}
+	return stack
+}
@@ -0,0 +1,63 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type panicStringer struct{}
+
+func (panicStringer) String() string { return "stringer value" }
+
+func recoverFields(fn func()) (fields Fields) {
+	defer func() {
+		fields = PanicFields(recover())
+	}()
+	fn()
+	return nil
+}
+
+//go:noinline
+func panicIndex(s []int, i int) int {
+	return s[i]
+}
+
+func TestPanicFields(t *testing.T) {
+	t.Run("runtime error", func(t *testing.T) {
+		fields := recoverFields(func() { panicIndex(nil, 1) })
+		assert.Equal(t, "runtime.boundsError", fields["panic.type"])
+		assert.Contains(t, fields["panic.value"], "index out of range")
+		assert.Regexp(t, `^github.com/jayvib/golog.panicIndex .*panic_test.go:\d+$`, fields["panic.frame"])
+		stack := fields["panic.stack"].([]string)
+		assert.Equal(t, fields["panic.frame"], stack[0])
+		assert.Contains(t, stack[1], "TestPanicFields")
+	})
+	t.Run("wrapped errors", func(t *testing.T) {
+		base := errors.New("connection refused")
+		fields := recoverFields(func() { panic(fmt.Errorf("dial: %w", base)) })
+		assert.Equal(t, "*fmt.wrapError", fields["panic.type"])
+		assert.Equal(t, "dial: connection refused", fields["panic.value"])
+		assert.Equal(t, []string{
+			"*fmt.wrapError: dial: connection refused",
+			"*errors.errorString: connection refused",
+		}, fields["panic.errors"])
+		assert.Contains(t, fields["panic.frame"], "TestPanicFields")
+	})
+	t.Run("stringer and plain values", func(t *testing.T) {
+		fields := recoverFields(func() { panic(panicStringer{}) })
+		assert.Equal(t, "golog.panicStringer", fields["panic.type"])
+		assert.Equal(t, "stringer value", fields["panic.value"])
+		assert.NotContains(t, fields, "panic.errors")
+
+		fields = recoverFields(func() { panic(42) })
+		assert.Equal(t, "int", fields["panic.type"])
+		assert.Equal(t, "42", fields["panic.value"])
+	})
+	t.Run("outside of a panic", func(t *testing.T) {
+		fields := PanicFields("not panicking")
+		assert.Contains(t, fields["panic.frame"], "TestPanicFields")
+	})
+}
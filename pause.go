@@ -0,0 +1,249 @@
+package golog
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The default limits of the pauses. See SetPauseLimits.
+const (
+	defaultPauseDuration = time.Minute
+	defaultPauseEntries  = 10000
+)
+
+// pendingWrite is an entry held during a pause.
+type pendingWrite struct {
+	o   *output
+	lvl Level
+	b   []byte
+}
+
+var (
+	// pauseActive is set while a pause holds or flushes entries,
+	// so the writes only take pauseMu during the pauses.
+	pauseActive int32
+
+	pauseMu sync.Mutex // protects the variables below
+	// globalPaused holds the entries of all the loggers.
+	globalPaused bool
+	// pausedOutputs hold the entries of some loggers.
+	pausedOutputs = make(map[*output]bool)
+	// resuming holds the new entries until the pending
+	// ones are written, to keep the order.
+	resuming int
+	pending  []pendingWrite
+	// pendingCount is the number of held entries per output.
+	pendingCount  = make(map[*output]int)
+	pauseTimer    *time.Timer
+	maxPause      = defaultPauseDuration
+	maxPauseCount = defaultPauseEntries
+)
+
+// SetPauseLimits sets the longest pause and the number of entries
+// held, after which all the pauses are resumed. The defaults are one
+// minute and 10000 entries.
+func SetPauseLimits(maxDuration time.Duration, maxEntries int) {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	maxPause, maxPauseCount = maxDuration, maxEntries
+}
+
+// Pause holds the entries of all the loggers, instead of writing
+// them, until Resume, e.g. while the downstream sinks are switched
+// during a maintenance window. The entries are formatted when they
+// are logged and written, in order, to the outputs the loggers have
+// when they are resumed. The pauses are bounded: all of them are
+// resumed once they last longer, or hold more entries, than the
+// limits of SetPauseLimits. The Logrus loggers are only paused by
+// their own Pause method.
+func Pause() {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	globalPaused = true
+	startPause()
+}
+
+// Resume writes the entries held by Pause, except the ones of the
+// loggers paused with their own Pause method, and stops holding
+// the new entries.
+func Resume() {
+	pauseMu.Lock()
+	globalPaused = false
+	pauseMu.Unlock()
+	flushPending()
+}
+
+// Pause holds the entries of l, and of the loggers sharing its
+// output, until Resume. See the package level Pause.
+func (l *stdLogger) Pause() {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	pausedOutputs[l.out] = true
+	startPause()
+}
+
+// Resume writes the entries held by Pause.
+func (l *stdLogger) Resume() {
+	pauseMu.Lock()
+	delete(pausedOutputs, l.out)
+	pauseMu.Unlock()
+	flushPending()
+}
+
+// startPause enables the pauses and starts the timer resuming them.
+// It must be called with pauseMu held.
+func startPause() {
+	atomic.StoreInt32(&pauseActive, 1)
+	if pauseTimer == nil {
+		pauseTimer = time.AfterFunc(maxPause, resumeAll)
+	}
+}
+
+// resumeAll resumes all the pauses.
+func resumeAll() {
+	pauseMu.Lock()
+	globalPaused = false
+	pausedOutputs = make(map[*output]bool)
+	pauseMu.Unlock()
+	flushPending()
+}
+
+// holdWrite holds the entry b of lvl, written to o, when o is paused
+// and reports whether it did. It must be called with o.mu held.
+func holdWrite(o *output, lvl Level, b []byte) bool {
+	if atomic.LoadInt32(&pauseActive) == 0 {
+		return false
+	}
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	if !globalPaused && !pausedOutputs[o] && resuming == 0 && pendingCount[o] == 0 {
+		return false
+	}
+	held := make([]byte, len(b))
+	copy(held, b)
+	pending = append(pending, pendingWrite{o: o, lvl: lvl, b: held})
+	pendingCount[o]++
+	if len(pending) == maxPauseCount {
+		reportf("golog: %d entries held by the pauses, resuming", len(pending))
+		go resumeAll()
+	}
+	return true
+}
+
+// flushPending writes, in order, the held entries of the outputs
+// that are not paused anymore.
+func flushPending() {
+	pauseMu.Lock()
+	resuming++
+	pauseMu.Unlock()
+	for {
+		pauseMu.Lock()
+		var batch, kept []pendingWrite
+		for _, p := range pending {
+			if globalPaused || pausedOutputs[p.o] {
+				kept = append(kept, p)
+			} else {
+				batch = append(batch, p)
+				if pendingCount[p.o]--; pendingCount[p.o] == 0 {
+					delete(pendingCount, p.o)
+				}
+			}
+		}
+		pending = kept
+		if len(batch) == 0 {
+			resuming--
+			if resuming == 0 && !globalPaused && len(pausedOutputs) == 0 && len(pending) == 0 {
+				atomic.StoreInt32(&pauseActive, 0)
+				if pauseTimer != nil {
+					pauseTimer.Stop()
+					pauseTimer = nil
+				}
+			}
+			pauseMu.Unlock()
+			return
+		}
+		pauseMu.Unlock()
+		for _, p := range batch {
+			p.o.mu.Lock()
+			n, err := writeLevel(p.o.w, p.lvl, p.b)
+			countBytes(p.o.w, n)
+			if err != nil {
+				countDrop()
+			}
+			p.o.mu.Unlock()
+		}
+	}
+}
+
+// logrusPause holds the entries of a paused Logrus logger.
+type logrusPause struct {
+	mu      sync.Mutex
+	target  io.Writer
+	held    [][]byte
+	resumed bool
+	timer   *time.Timer
+}
+
+// Write holds p until the logger is resumed.
+func (p *logrusPause) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.resumed {
+		return p.target.Write(b)
+	}
+	held := make([]byte, len(b))
+	copy(held, b)
+	p.held = append(p.held, held)
+	if len(p.held) == maxPauseEntries() {
+		reportf("golog: %d entries held by the pause, resuming", len(p.held))
+		p.timer.Reset(0)
+	}
+	return len(b), nil
+}
+
+func maxPauseEntries() int {
+	pauseMu.Lock()
+	defer pauseMu.Unlock()
+	return maxPauseCount
+}
+
+// Pause holds the entries of l, and of the loggers derived with
+// WithFields, until Resume. See the package level Pause.
+func (l *Logrus) Pause() {
+	l.cfg.pauseMu.Lock()
+	defer l.cfg.pauseMu.Unlock()
+	if l.cfg.paused != nil {
+		return
+	}
+	p := &logrusPause{target: l.logger.Out}
+	pauseMu.Lock()
+	p.timer = time.AfterFunc(maxPause, l.Resume)
+	pauseMu.Unlock()
+	l.cfg.paused = p
+	l.logger.SetOutput(p)
+}
+
+// Resume writes the entries held by Pause.
+func (l *Logrus) Resume() {
+	l.cfg.pauseMu.Lock()
+	defer l.cfg.pauseMu.Unlock()
+	p := l.cfg.paused
+	if p == nil {
+		return
+	}
+	l.cfg.paused = nil
+	p.timer.Stop()
+	p.mu.Lock()
+	for _, b := range p.held {
+		if _, err := p.target.Write(b); err != nil {
+			countDrop()
+		}
+	}
+	p.held = nil
+	p.resumed = true
+	target := p.target
+	p.mu.Unlock()
+	l.logger.SetOutput(target)
+}
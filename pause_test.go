@@ -0,0 +1,93 @@
+package golog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPause(t *testing.T) {
+	defer SetPauseLimits(defaultPauseDuration, defaultPauseEntries)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+
+	t.Run("entries are written on resume to the new output", func(t *testing.T) {
+		old, switched, other := &syncBuffer{}, &syncBuffer{}, &syncBuffer{}
+		l := newStdLogger(InfoLevel, old, 0)
+		child := l.WithFields(Fields{"k": "v"})
+		Pause()
+		l.Print("one")
+		child.Print("two")
+		l.SetOutput(switched)
+		assert.Empty(t, old.String())
+		assert.Empty(t, switched.String())
+
+		Resume()
+		l.Print("three")
+		assert.Equal(t, switched, l.Out())
+		assert.Empty(t, old.String())
+		assert.Equal(t, "INFO: one\nINFO: two k=v\nINFO: three\n", switched.String())
+
+		newStdLogger(InfoLevel, other, 0).Print("direct")
+		assert.Equal(t, "INFO: direct\n", other.String())
+	})
+	t.Run("logger pause", func(t *testing.T) {
+		paused, other := &syncBuffer{}, &syncBuffer{}
+		l := newStdLogger(InfoLevel, paused, 0)
+		l.Pause()
+		l.Print("held")
+		newStdLogger(InfoLevel, other, 0).Print("direct")
+		Resume()
+		assert.Empty(t, paused.String())
+		assert.Equal(t, "INFO: direct\n", other.String())
+
+		l.Resume()
+		assert.Equal(t, "INFO: held\n", paused.String())
+	})
+	t.Run("bounded by the number of entries", func(t *testing.T) {
+		SetPauseLimits(time.Hour, 3)
+		defer SetPauseLimits(defaultPauseDuration, defaultPauseEntries)
+		var errs syncBuffer
+		SetDiagnosticHandler(func(err error) { errs.Write([]byte(err.Error())) })
+		defer SetDiagnosticHandler(nil)
+		out := &syncBuffer{}
+		l := newStdLogger(InfoLevel, out, 0)
+		Pause()
+		l.Print("one")
+		l.Print("two")
+		l.Print("three")
+		assert.Eventually(t, func() bool { return out.String() == "INFO: one\nINFO: two\nINFO: three\n" }, time.Second, 5*time.Millisecond)
+		assert.Contains(t, errs.String(), "3 entries held by the pauses")
+		l.Print("four")
+		assert.Contains(t, out.String(), "four")
+	})
+	t.Run("bounded by the duration", func(t *testing.T) {
+		SetPauseLimits(20*time.Millisecond, defaultPauseEntries)
+		defer SetPauseLimits(defaultPauseDuration, defaultPauseEntries)
+		out := &syncBuffer{}
+		l := newStdLogger(InfoLevel, out, 0)
+		Pause()
+		l.Print("held")
+		assert.Empty(t, out.String())
+		assert.Eventually(t, func() bool { return out.String() == "INFO: held\n" }, time.Second, 5*time.Millisecond)
+	})
+	t.Run("logrus", func(t *testing.T) {
+		old, switched := &syncBuffer{}, &syncBuffer{}
+		l := NewLogrusLogger(InfoLevel)
+		l.SetOutput(old)
+		l.SetFormatter(&TextFormatter{})
+		l.Pause()
+		l.Print("one")
+		l.SetOutput(switched)
+		assert.Equal(t, switched, l.Out())
+		l.WithFields(Fields{"k": "v"}).Print("two")
+		assert.Empty(t, old.String())
+		assert.Empty(t, switched.String())
+		l.Resume()
+		l.Print("three")
+		assert.Equal(t, switched, l.Out())
+		assert.Empty(t, old.String())
+		assert.Equal(t, "INFO: one\nINFO: two k=v\nINFO: three\n", switched.String())
+	})
+}
@@ -0,0 +1,128 @@
+package golog
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// persistedLevels is the content of the state file of PersistLevels.
+type persistedLevels struct {
+	levelState
+	Expires time.Time `json:"expires"`
+}
+
+// levelPersister saves the levels to a state file.
+type levelPersister struct {
+	path string
+	ttl  time.Duration
+}
+
+var (
+	persisterMu sync.Mutex
+	persister   *levelPersister
+)
+
+// PersistLevels saves the global level and the levels of the named
+// loggers to the state file at path on every change, and restores
+// them from it, unless they expired, so a temporary debug setting
+// survives a restart. The saved levels expire ttl after the change.
+// An unreadable or invalid state file is reported to the diagnostic
+// handler and ignored. stop stops saving the changes.
+//
+//	stop := golog.PersistLevels("/var/lib/app/levels.json", time.Hour)
+//	defer stop()
+func PersistLevels(path string, ttl time.Duration) (stop func()) {
+	restoreLevels(path)
+	p := &levelPersister{path: path, ttl: ttl}
+	persisterMu.Lock()
+	persister = p
+	persisterMu.Unlock()
+	return func() {
+		persisterMu.Lock()
+		if persister == p {
+			persister = nil
+		}
+		persisterMu.Unlock()
+	}
+}
+
+func restoreLevels(path string) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return
+	}
+	if err != nil {
+		reportf("golog: failed to restore the levels: %v", err)
+		return
+	}
+	var saved persistedLevels
+	if err := json.Unmarshal(b, &saved); err != nil {
+		reportf("golog: failed to restore the levels: %v", err)
+		return
+	}
+	if !now().Before(saved.Expires) {
+		return
+	}
+	lvl, err := ParseLevel(saved.Level)
+	if err != nil {
+		reportf("golog: failed to restore the levels: %v", err)
+		return
+	}
+	loggers := make(map[string]Level, len(saved.Loggers))
+	for name, s := range saved.Loggers {
+		if loggers[name], err = ParseLevel(s); err != nil {
+			reportf("golog: failed to restore the levels: %v", err)
+			return
+		}
+	}
+	updateState(func(s *globalState) {
+		s.currentLevel = lvl
+		s.loggerLevels = loggers
+	})
+}
+
+// levelsChanged saves the levels when they are persisted.
+func levelsChanged() {
+	persisterMu.Lock()
+	defer persisterMu.Unlock()
+	if persister == nil {
+		return
+	}
+	if err := persister.save(); err != nil {
+		reportf("golog: failed to persist the levels: %v", err)
+	}
+}
+
+// save atomically replaces the state file.
+func (p *levelPersister) save() error {
+	st := getState()
+	saved := persistedLevels{
+		levelState: levelState{Level: st.currentLevel.name(), Loggers: make(map[string]string, len(st.loggerLevels))},
+		Expires:    now().Add(p.ttl),
+	}
+	for k, v := range st.loggerLevels {
+		saved.Loggers[k] = v.name()
+	}
+	b, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(p.path), filepath.Base(p.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), p.path)
+}
@@ -0,0 +1,60 @@
+package golog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistLevels(t *testing.T) {
+	defer func() { now = time.Now }()
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer ResetLoggerLevel("db")
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "levels.json")
+
+	stop := PersistLevels(path, time.Hour)
+	SetLevel(DebugLevel)
+	SetLoggerLevel("db", ErrorLevel)
+	stop()
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"level":"debug","loggers":{"db":"error"},"expires":"2020-01-01T01:00:00Z"}`, string(b))
+
+	t.Run("restored on startup", func(t *testing.T) {
+		SetLevel(InfoLevel)
+		ResetLoggerLevel("db")
+		clock = clock.Add(30 * time.Minute)
+		PersistLevels(path, time.Hour)()
+		assert.Equal(t, DebugLevel, GetLevel())
+		assert.Equal(t, map[string]Level{"db": ErrorLevel}, LoggerLevels())
+	})
+	t.Run("expired levels are ignored", func(t *testing.T) {
+		SetLevel(InfoLevel)
+		ResetLoggerLevel("db")
+		clock = clock.Add(time.Hour)
+		PersistLevels(path, time.Hour)()
+		assert.Equal(t, InfoLevel, GetLevel())
+		assert.Empty(t, LoggerLevels())
+	})
+	t.Run("invalid state file is reported", func(t *testing.T) {
+		var errs []error
+		SetDiagnosticHandler(func(err error) { errs = append(errs, err) })
+		defer SetDiagnosticHandler(nil)
+		require.NoError(t, ioutil.WriteFile(path, []byte("{"), 0600))
+		PersistLevels(path, time.Hour)()
+		require.Len(t, errs, 1)
+		assert.Contains(t, errs[0].Error(), "golog: failed to restore the levels")
+		assert.Equal(t, InfoLevel, GetLevel())
+	})
+}
@@ -0,0 +1,94 @@
+package golog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which a formatting buffer
+// is not pooled, so a single huge entry doesn't stay in memory.
+const maxPooledBuffer = 64 << 10
+
+var (
+	// bufferPool holds the *[]byte buffers formatting the entries.
+	bufferPool = sync.Pool{
+		New: func() interface{} {
+			b := make([]byte, 0, 512)
+			return &b
+		},
+	}
+	// entryPool holds the *Entry of the calls of the loggers.
+	entryPool = sync.Pool{
+		New: func() interface{} { return new(Entry) },
+	}
+)
+
+// appendFormatter is implemented by the formatters that can format
+// an entry into a buffer of the caller, which is pooled by emit.
+type appendFormatter interface {
+	appendFormat(b []byte, e *Entry) ([]byte, error)
+}
+
+var (
+	_ appendFormatter = (*TextFormatter)(nil)
+	_ appendFormatter = (*JSONFormatter)(nil)
+	_ appendFormatter = (*ConsoleFormatter)(nil)
+	_ appendFormatter = (*SyslogFormatter)(nil)
+	_ appendFormatter = (*LogfmtFormatter)(nil)
+	_ appendFormatter = (*ECSFormatter)(nil)
+	_ appendFormatter = (*GCPFormatter)(nil)
+)
+
+// format formats e with f, into a pooled buffer when f supports it,
+// in which case buf must be released with releaseBuffer once b is
+// written.
+func format(f Formatter, e *Entry) (b []byte, buf *[]byte, err error) {
+	af, ok := f.(appendFormatter)
+	if !ok {
+		b, err = f.Format(e)
+		return b, nil, err
+	}
+	buf = bufferPool.Get().(*[]byte)
+	b, err = af.appendFormat((*buf)[:0], e)
+	if err != nil {
+		bufferPool.Put(buf)
+		return nil, nil, err
+	}
+	return b, buf, nil
+}
+
+// releaseBuffer puts buf, grown into b, back into the pool.
+func releaseBuffer(buf *[]byte, b []byte) {
+	if buf != nil && cap(b) <= maxPooledBuffer {
+		*buf = b[:0]
+		bufferPool.Put(buf)
+	}
+}
+
+// getEntry returns a zeroed Entry from the pool.
+func getEntry() *Entry {
+	return entryPool.Get().(*Entry)
+}
+
+// releaseEntry zeroes e and puts it back into the pool, unless it
+// was passed to the hooks. e must not be used anymore.
+func releaseEntry(e *Entry) {
+	if !e.shared {
+		*e = Entry{}
+		entryPool.Put(e)
+	}
+}
+
+// sprintln returns the message of the arguments v of a Println-style
+// call. The newline appended by fmt.Sprintln is trimmed by write, so a
+// lone string not ending with a newline is returned as is instead of
+// being copied.
+func sprintln(v []interface{}) string {
+	if len(v) == 1 {
+		if s, ok := v[0].(string); ok && !strings.HasSuffix(s, "\n") {
+			return s
+		}
+	}
+	return fmt.Sprintln(v...)
+}
@@ -0,0 +1,94 @@
+package golog
+
+import (
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat_Pooled(t *testing.T) {
+	e := &Entry{
+		Time:    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Level:   WarningLevel,
+		Message: "disk almost full",
+		Fields:  Fields{"free": "1%"},
+	}
+	formatters := []Formatter{
+		&TextFormatter{Flags: log.LstdFlags},
+		&JSONFormatter{},
+		&ConsoleFormatter{DisableColors: true},
+		&SyslogFormatter{AppName: "app", Hostname: "host"},
+	}
+	for _, f := range formatters {
+		want, err := f.Format(e)
+		require.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			b, buf, err := format(f, e)
+			require.NoError(t, err)
+			require.NotNil(t, buf, "%T", f)
+			assert.Equal(t, string(want), string(b), "%T", f)
+			releaseBuffer(buf, b)
+		}
+	}
+
+	t.Run("other formatters are not pooled", func(t *testing.T) {
+		f, err := NewTemplateFormatter("{{.Message}}", nil)
+		require.NoError(t, err)
+		b, buf, err := format(f, e)
+		require.NoError(t, err)
+		assert.Nil(t, buf)
+		assert.Equal(t, "disk almost full\n", string(b))
+	})
+}
+
+func TestWrite_Pooling(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+
+	t.Run("consecutive entries", func(t *testing.T) {
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0)
+		l.Info("first", 1)
+		l.Info("second")
+		l.Info("third\n")
+		assert.Equal(t, "INFO: first 1\nINFO: second\nINFO: third\n\n", out.String())
+	})
+
+	t.Run("the entries of the hooks are not reused", func(t *testing.T) {
+		var fired []*Entry
+		l := newStdLogger(InfoLevel, ioutil.Discard, 0)
+		l.AddHook(HookFunc(func(e *Entry) error {
+			fired = append(fired, e)
+			return nil
+		}))
+		l.Info("first")
+		l.Info("second")
+		require.Len(t, fired, 2)
+		assert.Equal(t, "first", fired[0].Message)
+		assert.Equal(t, "second", fired[1].Message)
+	})
+
+	t.Run("allocations", func(t *testing.T) {
+		l := newStdLogger(InfoLevel, ioutil.Discard, 0)
+		l.Info("warm up")
+		allocs := testing.AllocsPerRun(100, func() {
+			l.Info("hello")
+		})
+		// The pools may be emptied by a collection during the run.
+		assert.True(t, allocs < 1, "%v allocations", allocs)
+	})
+}
+
+func BenchmarkInfo(b *testing.B) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	l := newStdLogger(InfoLevel, ioutil.Discard, log.LstdFlags).WithFields(Fields{"user": "alice"})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Info("hello")
+	}
+}
@@ -0,0 +1,153 @@
+package golog
+
+// FieldPrecedence is the order in which the sources of the fields
+// of an entry override each other when they share a key. The
+// sources are the global fields set with SetGlobalFields, the
+// fields of the context attached by FromContext, the fields of the
+// logger attached with WithFields and the fields of the call.
+type FieldPrecedence int
+
+const (
+	// SpecificFieldsWin lets the most specific source win: the fields
+	// of the call override the fields of the logger, which override
+	// the fields of the context, which override the global fields.
+	SpecificFieldsWin FieldPrecedence = iota
+	// GlobalFieldsWin reverses the order: the global fields override
+	// the fields of the context, which override the fields of the
+	// logger, which override the fields of the call. It keeps the
+	// fields set by the program, e.g. the service name, from being
+	// overwritten by the libraries.
+	GlobalFieldsWin
+)
+
+// SetGlobalFields sets the fields attached to every entry of the
+// loggers, replacing the previous ones. Nil removes them.
+func SetGlobalFields(fields Fields) {
+	if len(fields) > 0 {
+		fields = copyFields(fields)
+	} else {
+		fields = nil
+	}
+	updateState(func(s *globalState) {
+		s.globalFields = fields
+	})
+}
+
+// GlobalFields returns the fields set with SetGlobalFields.
+// The returned Fields must not be modified.
+func GlobalFields() Fields {
+	return getState().globalFields
+}
+
+// SetFieldPrecedence sets the order in which the sources of the
+// fields override each other. The default is SpecificFieldsWin.
+func SetFieldPrecedence(p FieldPrecedence) {
+	updateState(func(s *globalState) {
+		s.fieldPrecedence = p
+	})
+}
+
+// fieldSources are the fields of an entry not merged yet into its
+// Fields, which hold the fields of the call until then.
+type fieldSources struct {
+	global     Fields
+	context    Fields
+	logger     Fields
+	precedence FieldPrecedence
+}
+
+// ResolvedFields returns the fields of e merged from all their
+// sources according to the field precedence. The entries passed to
+// the processors, hooks and formatters are resolved already, so it
+// returns their Fields. The returned Fields must not be modified.
+func (e *Entry) ResolvedFields() Fields {
+	s := e.sources
+	if s == nil {
+		return e.Fields
+	}
+	return mergeFields(s.precedence, s.global, s.context, s.logger, e.Fields)
+}
+
+// resolveFields merges the sources of the fields of e into its Fields.
+func resolveFields(e *Entry) {
+	if e.sources != nil {
+		e.Fields = e.ResolvedFields()
+		e.sources = nil
+	}
+}
+
+// mergeFields merges the fields of the sources, from the least to
+// the most specific, according to p.
+func mergeFields(p FieldPrecedence, sources ...Fields) Fields {
+	n := 0
+	for _, f := range sources {
+		n += len(f)
+	}
+	merged := make(Fields, n)
+	for i := range sources {
+		if p == GlobalFieldsWin {
+			i = len(sources) - 1 - i
+		}
+		for k, v := range sources[i] {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// attachFields attaches the fields of l and of its context to e,
+// whose Fields are the fields of the call, along with the global
+// fields of st.
+func (l *stdLogger) attachFields(st *globalState, e *Entry) {
+	if len(st.globalFields) == 0 && len(l.ctxFields) == 0 {
+		if len(e.Fields) == 0 {
+			e.Fields = l.fields
+			return
+		}
+		if len(l.fields) == 0 {
+			return
+		}
+	}
+	e.sources = &fieldSources{
+		global:     st.globalFields,
+		context:    l.ctxFields,
+		logger:     l.fields,
+		precedence: st.fieldPrecedence,
+	}
+}
+
+// gateFields returns the fields of the entries of l with the fields
+// call, which the level gates of st depend on: the fields of l alone
+// unless st has named levels or verbose captures, which may match
+// the global fields, the fields of the context or of the call too.
+func (l *stdLogger) gateFields(st *globalState, call Fields) Fields {
+	if !st.gatesOnFields() || len(st.globalFields)+len(l.ctxFields)+len(call) == 0 {
+		return l.fields
+	}
+	return mergeFields(st.fieldPrecedence, st.globalFields, l.ctxFields, l.fields, call)
+}
+
+// attachGlobalFields attaches the global fields of st to e,
+// whose Fields are the fields of the call.
+func attachGlobalFields(st *globalState, e *Entry) {
+	if len(st.globalFields) > 0 {
+		e.sources = &fieldSources{
+			global:     st.globalFields,
+			precedence: st.fieldPrecedence,
+		}
+	}
+}
+
+// contextLogger is implemented by the loggers that keep the fields
+// of the context apart from their own fields.
+type contextLogger interface {
+	withContextFields(fields Fields) FieldLogger
+}
+
+// withContextFields returns a logger like l with the fields of
+// the context merged with the context fields of l.
+func (l *stdLogger) withContextFields(fields Fields) FieldLogger {
+	c := l.clone()
+	c.ctxFields = mergeFields(SpecificFieldsWin, l.ctxFields, fields)
+	return c
+}
@@ -0,0 +1,101 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldPrecedence(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	defer SetGlobalFields(nil)
+	defer SetFieldPrecedence(SpecificFieldsWin)
+	SetLevel(InfoLevel)
+
+	var out bytes.Buffer
+	l := newStdLogger(InfoLevel, &out, 0)
+	logger := l.WithFields(Fields{"source": "logger", "user": "alice"})
+	ctx := ContextWithFields(context.Background(), Fields{"source": "context", "request_id": "abc"})
+	ctx = WithContext(ctx, logger)
+
+	t.Run("specific fields win by default", func(t *testing.T) {
+		out.Reset()
+		SetGlobalFields(Fields{"source": "global", "service": "api"})
+		FromContext(ctx).Print("hello")
+		assert.Equal(t, "INFO: hello request_id=abc service=api source=logger user=alice\n", out.String())
+	})
+
+	t.Run("global fields win", func(t *testing.T) {
+		out.Reset()
+		SetFieldPrecedence(GlobalFieldsWin)
+		defer SetFieldPrecedence(SpecificFieldsWin)
+		FromContext(ctx).Print("hello")
+		assert.Equal(t, "INFO: hello request_id=abc service=api source=global user=alice\n", out.String())
+	})
+
+	t.Run("context fields override the global fields", func(t *testing.T) {
+		out.Reset()
+		SetGlobalFields(Fields{"request_id": "none"})
+		FromContext(ctx).Print("hello")
+		assert.Equal(t, "INFO: hello request_id=abc source=logger user=alice\n", out.String())
+	})
+
+	t.Run("fields derived from a context logger keep the context", func(t *testing.T) {
+		out.Reset()
+		SetGlobalFields(nil)
+		FromContext(ctx).WithFields(Fields{"request_id": "def"}).Print("hello")
+		assert.Equal(t, "INFO: hello request_id=def source=logger user=alice\n", out.String())
+	})
+
+	t.Run("global fields are copied", func(t *testing.T) {
+		fields := Fields{"service": "api"}
+		SetGlobalFields(fields)
+		defer SetGlobalFields(nil)
+		fields["service"] = "web"
+		assert.Equal(t, Fields{"service": "api"}, GlobalFields())
+	})
+
+	t.Run("the fields of the call override the global fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		defer InfoLogger.SetOutput(os.Stdout)
+		InfoLogger.SetOutput(&buf)
+		SetGlobalFields(Fields{"service": "api", "component": "main"})
+		defer SetGlobalFields(nil)
+		Emit(&Entry{Level: InfoLevel, Message: "hello", Fields: Fields{"component": "http"}})
+		assert.Contains(t, buf.String(), "hello component=http service=api\n")
+	})
+
+	t.Run("logrus", func(t *testing.T) {
+		var buf bytes.Buffer
+		lr := NewLogrusLogger(InfoLevel)
+		lr.SetOutput(&buf)
+		lr.SetFormatter(&TextFormatter{})
+		SetGlobalFields(Fields{"source": "global", "service": "api"})
+		defer SetGlobalFields(nil)
+		ctx := WithContext(ContextWithFields(context.Background(), Fields{"source": "context"}), lr)
+		FromContext(ctx).Print("hello")
+		assert.Equal(t, "INFO: hello service=api source=context\n", buf.String())
+	})
+}
+
+func TestEntry_ResolvedFields(t *testing.T) {
+	e := &Entry{
+		Fields: Fields{"a": "call"},
+		sources: &fieldSources{
+			global:  Fields{"a": "global", "b": "global", "c": "global"},
+			context: Fields{"b": "context"},
+			logger:  Fields{"a": "logger", "c": "logger"},
+		},
+	}
+	assert.Equal(t, Fields{"a": "call", "b": "context", "c": "logger"}, e.ResolvedFields())
+
+	e.sources.precedence = GlobalFieldsWin
+	assert.Equal(t, Fields{"a": "global", "b": "global", "c": "global"}, e.ResolvedFields())
+
+	resolveFields(e)
+	assert.Nil(t, e.sources)
+	assert.Equal(t, e.Fields, e.ResolvedFields())
+}
@@ -0,0 +1,30 @@
+package golog
+
+// Processor transforms an entry before it is formatted. Processors
+// may modify the entry, including its Fields which are a copy owned
+// by the entry, but must not retain it.
+type Processor func(e *Entry)
+
+// AddProcessor registers p to be run, in registration order, on
+// every entry emitted by all the loggers, before their hooks.
+func AddProcessor(p Processor) {
+	updateState(func(s *globalState) {
+		procs := make([]Processor, len(s.processors), len(s.processors)+1)
+		copy(procs, s.processors)
+		s.processors = append(procs, p)
+	})
+}
+
+// ResetProcessors removes all the registered processors.
+func ResetProcessors() {
+	updateState(func(s *globalState) {
+		s.processors = nil
+	})
+}
+
+// runProcessors runs procs in order on e.
+func runProcessors(procs []Processor, e *Entry) {
+	for _, p := range procs {
+		p(e)
+	}
+}
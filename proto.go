@@ -0,0 +1,233 @@
+package golog
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"runtime"
+	"time"
+)
+
+// ProtoFormatter encodes entries with the protocol buffers schema
+// of proto/entry.proto. The output is not delimited, so it is meant
+// for framed sinks such as the SocketWriter. Use DecodeProtoEntry
+// to decode it.
+type ProtoFormatter struct{}
+
+var _ Formatter = ProtoFormatter{}
+
+// Protocol buffers wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// Format implements the Formatter interface.
+func (ProtoFormatter) Format(e *Entry) ([]byte, error) {
+	b := make([]byte, 0, 64+len(e.Message))
+	if !e.Time.IsZero() {
+		b = appendProtoVarint(b, 1, uint64(e.Time.UnixNano()))
+	}
+	b = appendProtoVarint(b, 2, uint64(e.Level+1))
+	b = appendProtoString(b, 3, e.Message)
+	b = appendProtoString(b, 4, e.Template)
+	for _, k := range e.Fields.sortedKeys() {
+		var kv []byte
+		kv = appendProtoString(kv, 1, k)
+		kv = appendProtoBytes(kv, 2, appendProtoValue(nil, e.Fields[k]))
+		b = appendProtoBytes(b, 5, kv)
+	}
+	if e.Caller != nil {
+		var c []byte
+		c = appendProtoString(c, 1, e.Caller.File)
+		c = appendProtoVarint(c, 2, uint64(e.Caller.Line))
+		c = appendProtoString(c, 3, e.Caller.Function)
+		b = appendProtoBytes(b, 6, c)
+	}
+	return b, nil
+}
+
+func appendProtoValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return appendProtoBytes(b, 1, []byte(v))
+	case int:
+		return appendProtoVarint(b, 2, uint64(v))
+	case int32:
+		return appendProtoVarint(b, 2, uint64(v))
+	case int64:
+		return appendProtoVarint(b, 2, uint64(v))
+	case uint32:
+		return appendProtoVarint(b, 2, uint64(v))
+	case float32:
+		return appendProtoFixed64(b, 3, math.Float64bits(float64(v)))
+	case float64:
+		return appendProtoFixed64(b, 3, math.Float64bits(v))
+	case bool:
+		n := uint64(0)
+		if v {
+			n = 1
+		}
+		return appendProtoVarint(b, 4, n)
+	}
+	return appendProtoBytes(b, 1, appendTextValue(nil, v))
+}
+
+func appendProtoTag(b []byte, field, wire int) []byte {
+	return appendUvarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendProtoVarint(b []byte, field int, v uint64) []byte {
+	b = appendProtoTag(b, field, wireVarint)
+	return appendUvarint(b, v)
+}
+
+func appendProtoFixed64(b []byte, field int, v uint64) []byte {
+	b = appendProtoTag(b, field, wireFixed64)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func appendProtoBytes(b []byte, field int, p []byte) []byte {
+	b = appendProtoTag(b, field, wireBytes)
+	b = appendUvarint(b, uint64(len(p)))
+	return append(b, p...)
+}
+
+func appendProtoString(b []byte, field int, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = appendProtoTag(b, field, wireBytes)
+	b = appendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// errInvalidProto is returned when decoding malformed entries.
+var errInvalidProto = errors.New("golog: invalid protobuf entry")
+
+// protoField is a decoded field of a protocol buffers message.
+type protoField struct {
+	num   int
+	wire  int
+	value uint64
+	bytes []byte
+}
+
+// readProtoFields decodes the fields of a message,
+// skipping the unknown wire types.
+func readProtoFields(b []byte, fn func(f protoField) error) error {
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return errInvalidProto
+		}
+		b = b[n:]
+		f := protoField{num: int(tag >> 3), wire: int(tag & 7)}
+		switch f.wire {
+		case wireVarint:
+			if f.value, n = binary.Uvarint(b); n <= 0 {
+				return errInvalidProto
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return errInvalidProto
+			}
+			f.value = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case wireBytes:
+			l, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < l {
+				return errInvalidProto
+			}
+			f.bytes = b[n : n+int(l)]
+			b = b[n+int(l):]
+		default:
+			return errInvalidProto
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeProtoEntry decodes an entry encoded by the ProtoFormatter.
+func DecodeProtoEntry(b []byte) (*Entry, error) {
+	e := &Entry{}
+	err := readProtoFields(b, func(f protoField) error {
+		switch f.num {
+		case 1:
+			e.Time = time.Unix(0, int64(f.value))
+		case 2:
+			e.Level = Level(f.value) - 1
+		case 3:
+			e.Message = string(f.bytes)
+		case 4:
+			e.Template = string(f.bytes)
+		case 5:
+			return decodeProtoField(e, f.bytes)
+		case 6:
+			e.Caller = &runtime.Frame{}
+			return readProtoFields(f.bytes, func(f protoField) error {
+				switch f.num {
+				case 1:
+					e.Caller.File = string(f.bytes)
+				case 2:
+					e.Caller.Line = int(f.value)
+				case 3:
+					e.Caller.Function = string(f.bytes)
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func decodeProtoField(e *Entry, b []byte) error {
+	var key string
+	var value interface{}
+	err := readProtoFields(b, func(f protoField) error {
+		switch f.num {
+		case 1:
+			key = string(f.bytes)
+		case 2:
+			return readProtoFields(f.bytes, func(f protoField) error {
+				switch f.num {
+				case 1:
+					value = string(f.bytes)
+				case 2:
+					value = int64(f.value)
+				case 3:
+					value = math.Float64frombits(f.value)
+				case 4:
+					value = f.value != 0
+				}
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if e.Fields == nil {
+		e.Fields = Fields{}
+	}
+	e.Fields[key] = value
+	return nil
+}
@@ -0,0 +1,74 @@
+package golog
+
+import (
+	"io"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtoFormatter(t *testing.T) {
+	entry := &Entry{
+		Time:     time.Date(2020, 3, 4, 5, 6, 7, 8, time.UTC),
+		Level:    WarningLevel,
+		Message:  "disk almost full",
+		Template: "disk %s almost full",
+		Fields: Fields{
+			"free":    0.05,
+			"device":  "sda1",
+			"retries": -3,
+			"alert":   true,
+			"tags":    []string{"a", "b"},
+		},
+		Caller: &runtime.Frame{File: "main.go", Line: 42, Function: "main.main"},
+	}
+	b, err := ProtoFormatter{}.Format(entry)
+	require.NoError(t, err)
+
+	got, err := DecodeProtoEntry(b)
+	require.NoError(t, err)
+	assert.True(t, entry.Time.Equal(got.Time))
+	assert.Equal(t, entry.Level, got.Level)
+	assert.Equal(t, entry.Message, got.Message)
+	assert.Equal(t, entry.Template, got.Template)
+	assert.Equal(t, Fields{
+		"free":    0.05,
+		"device":  "sda1",
+		"retries": int64(-3),
+		"alert":   true,
+		"tags":    "a,b",
+	}, got.Fields)
+	assert.Equal(t, entry.Caller, got.Caller)
+
+	t.Run("debug level is distinct from unspecified", func(t *testing.T) {
+		b, err := ProtoFormatter{}.Format(&Entry{Level: DebugLevel})
+		require.NoError(t, err)
+		got, err := DecodeProtoEntry(b)
+		require.NoError(t, err)
+		assert.Equal(t, DebugLevel, got.Level)
+	})
+	t.Run("truncated entry", func(t *testing.T) {
+		_, err := DecodeProtoEntry(b[:len(b)-3])
+		assert.Equal(t, errInvalidProto, err)
+	})
+	t.Run("through a socket sink", func(t *testing.T) {
+		client, server := net.Pipe()
+		defer server.Close()
+		w := newSocketWriter(func() (io.WriteCloser, error) {
+			return client, nil
+		})
+		defer w.Close()
+		go func() {
+			w.Write(b)
+		}()
+		p, err := readFrame(server)
+		require.NoError(t, err)
+		got, err := DecodeProtoEntry(p)
+		require.NoError(t, err)
+		assert.Equal(t, entry.Message, got.Message)
+	})
+}
@@ -0,0 +1,104 @@
+package golog
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+var (
+	samplingMu sync.Mutex // protects the variables below
+	// samplingSeed seeds samplingRand and the hashes of the
+	// deterministic RandomSamplers.
+	samplingSeed = time.Now().UnixNano()
+	samplingRand = rand.New(rand.NewSource(samplingSeed))
+)
+
+// SetSamplingSeed seeds the pseudo-random decisions of the
+// RandomSamplers, so a test run, or the sampling of an incident, can
+// be reproduced exactly. The seed defaults to the start time of the
+// process. Like the counts of the other samplers, the sequence of the
+// decisions depends on the order of the entries.
+func SetSamplingSeed(seed int64) {
+	samplingMu.Lock()
+	defer samplingMu.Unlock()
+	samplingSeed = seed
+	samplingRand = rand.New(rand.NewSource(seed))
+}
+
+// SamplingSeed returns the seed of the sampling decisions, to be
+// logged at startup so they can be reproduced.
+func SamplingSeed() int64 {
+	samplingMu.Lock()
+	defer samplingMu.Unlock()
+	return samplingSeed
+}
+
+// RandomSampler keeps the entries with a probability of Rate. The
+// decisions are drawn from a PRNG seeded with SetSamplingSeed or, in
+// the Deterministic mode, from a hash of the seed and the fingerprint
+// of the entries, so the entries with the same fingerprint are all
+// kept or all dropped, whatever their order:
+//
+//	golog.AddSampler(&golog.RandomSampler{Level: golog.InfoLevel, Rate: 0.1, Key: "request_id", Deterministic: true})
+//
+// Entries above Level are always kept. The fields must be set
+// before the sampler is used.
+type RandomSampler struct {
+	// Level is the highest level that is sampled.
+	Level Level
+	// Rate is the probability of an entry to be kept,
+	// from 0 to 1.
+	Rate float64
+	// Deterministic makes the decisions depend on the fingerprint
+	// of the entries only.
+	Deterministic bool
+	// Key is the field holding the fingerprint of the entries in
+	// the Deterministic mode, e.g. a request or trace ID. When empty,
+	// or when the field is missing, the message template, or the
+	// message itself, is used instead.
+	Key string
+}
+
+// Sample implements the Sampler interface.
+func (s *RandomSampler) Sample(e *Entry) bool {
+	if e.Level > s.Level {
+		return true
+	}
+	if s.Deterministic {
+		return hashRatio(fingerprint(e, s.Key)) < s.Rate
+	}
+	samplingMu.Lock()
+	defer samplingMu.Unlock()
+	return samplingRand.Float64() < s.Rate
+}
+
+// hashRatio maps the fingerprint fp, hashed with the sampling
+// seed, to a number in [0, 1).
+func hashRatio(fp string) float64 {
+	var seed [8]byte
+	binary.LittleEndian.PutUint64(seed[:], uint64(SamplingSeed()))
+	h := fnv64a(fnv64aOffset, string(seed[:]))
+	h = fnv64a(h, fp)
+	// The finalizer of MurmurHash3 spreads the last bytes of fp,
+	// e.g. sequential IDs, to the high bits.
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return float64(h>>11) / (1 << 53)
+}
+
+const fnv64aOffset = 14695981039346656037
+
+// fnv64a continues the 64-bit FNV-1a hash h with s.
+func fnv64a(h uint64, s string) uint64 {
+	const prime = 1099511628211
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime
+	}
+	return h
+}
@@ -0,0 +1,71 @@
+package golog
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomSampler(t *testing.T) {
+	defer SetSamplingSeed(SamplingSeed())
+
+	decisions := func(s *RandomSampler, ids []int) []bool {
+		out := make([]bool, len(ids))
+		for i, id := range ids {
+			out[i] = s.Sample(&Entry{Level: InfoLevel, Message: "request", Fields: Fields{"request_id": id}})
+		}
+		return out
+	}
+	ids := make([]int, 1000)
+	reversed := make([]int, len(ids))
+	for i := range ids {
+		ids[i], reversed[len(ids)-1-i] = i, i
+	}
+	count := func(d []bool) (n int) {
+		for _, kept := range d {
+			if kept {
+				n++
+			}
+		}
+		return n
+	}
+
+	t.Run("seeded", func(t *testing.T) {
+		s := &RandomSampler{Level: InfoLevel, Rate: 0.25}
+		SetSamplingSeed(42)
+		assert.Equal(t, int64(42), SamplingSeed())
+		first := decisions(s, ids)
+		SetSamplingSeed(42)
+		assert.Equal(t, first, decisions(s, ids))
+		assert.InDelta(t, 250, count(first), 50)
+		SetSamplingSeed(43)
+		assert.NotEqual(t, first, decisions(s, ids))
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		s := &RandomSampler{Level: InfoLevel, Rate: 0.25, Key: "request_id", Deterministic: true}
+		SetSamplingSeed(42)
+		first := decisions(s, ids)
+		assert.InDelta(t, 250, count(first), 50)
+		again := decisions(s, reversed)
+		for i, id := range reversed {
+			assert.Equal(t, first[id], again[i], fmt.Sprint(id))
+		}
+		SetSamplingSeed(43)
+		assert.NotEqual(t, first, decisions(s, ids))
+
+		msg := &RandomSampler{Level: InfoLevel, Rate: 0.5, Deterministic: true}
+		e := &Entry{Level: InfoLevel, Message: "cache miss"}
+		kept := msg.Sample(e)
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, kept, msg.Sample(e))
+		}
+	})
+
+	t.Run("above level", func(t *testing.T) {
+		s := &RandomSampler{Level: InfoLevel}
+		assert.False(t, s.Sample(&Entry{Level: InfoLevel}))
+		assert.True(t, s.Sample(&Entry{Level: WarningLevel}))
+	})
+}
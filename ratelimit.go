@@ -0,0 +1,127 @@
+package golog
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limited logs with the package level loggers when the rate limit
+// that created it allows it, and discards the entries otherwise.
+// See Every and Once.
+type Limited struct {
+	allowed bool
+}
+
+var (
+	// everySites holds the time of the last allowed
+	// call of Every per call site.
+	everySites sync.Map
+	// onceSites holds the call sites of Once that
+	// were allowed.
+	onceSites sync.Map
+)
+
+// Every returns a Limited that logs at most once per d from the
+// call site of Every, for the periodic status messages of loops:
+//
+//	for {
+//		golog.Every(time.Minute).Infof("processed %d jobs", n)
+//	}
+func Every(d time.Duration) Limited {
+	var pcs [1]uintptr
+	if runtime.Callers(2, pcs[:]) == 0 {
+		return Limited{allowed: true}
+	}
+	t := now().UnixNano()
+	v, _ := everySites.LoadOrStore(pcs[0], new(int64))
+	last := v.(*int64)
+	for {
+		prev := atomic.LoadInt64(last)
+		if prev != 0 && t-prev < int64(d) {
+			return Limited{}
+		}
+		if atomic.CompareAndSwapInt64(last, prev, t) {
+			return Limited{allowed: true}
+		}
+	}
+}
+
+// Once returns a Limited that only logs the first time the call
+// site of Once is reached, for the one-time warnings:
+//
+//	golog.Once().Warn("Config.Timeout is deprecated, use Config.Deadline")
+func Once() Limited {
+	var pcs [1]uintptr
+	if runtime.Callers(2, pcs[:]) == 0 {
+		return Limited{allowed: true}
+	}
+	_, loaded := onceSites.LoadOrStore(pcs[0], struct{}{})
+	return Limited{allowed: !loaded}
+}
+
+// logLimited logs the message returned by msg with the package
+// level logger of lvl when the limit is allowed. It must be called
+// directly by the methods of Limited.
+func (r Limited) logLimited(lvl Level, format string, msg func() string) {
+	if !r.allowed {
+		return
+	}
+	l := packageLogger(lvl)
+	if l == nil || !l.isPrint() {
+		return
+	}
+	l.outputTemplate(stdCallDepth+1, msg(), format)
+}
+
+// Debug logs with DebugLogger.
+func (r Limited) Debug(v ...interface{}) {
+	r.logLimited(DebugLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+
+// Debugf logs with DebugLogger.
+func (r Limited) Debugf(format string, v ...interface{}) {
+	r.logLimited(DebugLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+
+// Trace logs with TraceLogger.
+func (r Limited) Trace(v ...interface{}) {
+	r.logLimited(TraceLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+
+// Tracef logs with TraceLogger.
+func (r Limited) Tracef(format string, v ...interface{}) {
+	r.logLimited(TraceLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+
+// Info logs with InfoLogger.
+func (r Limited) Info(v ...interface{}) {
+	r.logLimited(InfoLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+
+// Infof logs with InfoLogger.
+func (r Limited) Infof(format string, v ...interface{}) {
+	r.logLimited(InfoLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+
+// Warn logs with WarningLogger.
+func (r Limited) Warn(v ...interface{}) {
+	r.logLimited(WarningLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+
+// Warnf logs with WarningLogger.
+func (r Limited) Warnf(format string, v ...interface{}) {
+	r.logLimited(WarningLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
+
+// Error logs with ErrorLogger.
+func (r Limited) Error(v ...interface{}) {
+	r.logLimited(ErrorLevel, "", func() string { return fmt.Sprintln(v...) })
+}
+
+// Errorf logs with ErrorLogger.
+func (r Limited) Errorf(format string, v ...interface{}) {
+	r.logLimited(ErrorLevel, format, func() string { return fmt.Sprintf(format, v...) })
+}
@@ -0,0 +1,58 @@
+package golog
+
+import (
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvery(t *testing.T) {
+	defer func() { now = time.Now }()
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer SetOutput(os.Stdout)
+	defer func() {
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 10; j++ {
+			Every(time.Minute).Infof("processed %d jobs", i*10+j)
+			Every(time.Minute).Warn("slow")
+			clock = clock.Add(time.Second)
+		}
+		clock = clock.Add(time.Minute)
+	}
+	assert.Equal(t, 3, strings.Count(out.String(), "slow"))
+	assert.Contains(t, out.String(), "INFO: ratelimit_test.go:31: processed 0 jobs\n")
+	assert.Contains(t, out.String(), "INFO: ratelimit_test.go:31: processed 10 jobs\n")
+	assert.Contains(t, out.String(), "INFO: ratelimit_test.go:31: processed 20 jobs\n")
+	assert.NotContains(t, out.String(), "processed 1 jobs")
+}
+
+func TestOnce(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+
+	for i := 0; i < 3; i++ {
+		Once().Warn("Config.Timeout is deprecated")
+		Once().Errorf("attempt %d", i)
+	}
+	assert.Equal(t, 1, strings.Count(out.String(), "deprecated"))
+	assert.Equal(t, 1, strings.Count(out.String(), "attempt"))
+	assert.Contains(t, out.String(), "attempt 0")
+}
@@ -0,0 +1,122 @@
+// Package reader reads back the logs written by golog, verifying the
+// checksums appended by a golog.ChecksumWriter, so the archived logs
+// can be audited:
+//
+//	r := reader.New(f, golog.ChecksumChain)
+//	for r.Scan() {
+//		fmt.Println(r.Text())
+//	}
+//	if err := r.Err(); err != nil {
+//		log.Fatalf("tampered log: %v", err)
+//	}
+//
+// The rotated logs compressed with gzip are read transparently by Open.
+package reader
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/jayvib/golog"
+)
+
+// maxLineSize is the largest line read, as the largest framed entry.
+const maxLineSize = 1 << 20
+
+// Reader reads the entries of a checksummed log, one per line,
+// without their checksums. It stops at the first entry which fails
+// the verification, and reports golog.ErrChecksumUnsealed when the
+// log does not end with the seal written by ChecksumWriter.Close.
+type Reader struct {
+	s     *bufio.Scanner
+	v     *golog.ChecksumVerifier
+	entry []byte
+	err   error
+	c     io.Closer
+}
+
+// New returns a Reader of the log r checksummed with mode.
+func New(r io.Reader, mode golog.ChecksumMode) *Reader {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxLineSize)
+	return &Reader{s: s, v: golog.NewChecksumVerifier(mode)}
+}
+
+// Open returns a Reader of the log file at path checksummed with
+// mode, decompressing it when its name ends with .gz. Close the
+// Reader to close the file.
+func Open(path string, mode golog.ChecksumMode) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		r = zr
+	}
+	lr := New(r, mode)
+	lr.c = f
+	return lr, nil
+}
+
+// Scan advances to the next verified entry, and reports whether
+// there is one. Err returns the reason when there is none.
+func (r *Reader) Scan() bool {
+	if r.err != nil {
+		return false
+	}
+	for r.s.Scan() {
+		entry, err := r.v.Verify(r.s.Bytes())
+		if err != nil {
+			r.err = err
+			return false
+		}
+		if entry != nil {
+			r.entry = entry
+			return true
+		}
+	}
+	if r.err = r.s.Err(); r.err == nil {
+		r.err = r.v.Close()
+	}
+	return false
+}
+
+// Bytes returns the last entry read by Scan. It is only valid
+// until the next call to Scan.
+func (r *Reader) Bytes() []byte {
+	return r.entry
+}
+
+// Text returns the last entry read by Scan.
+func (r *Reader) Text() string {
+	return string(r.entry)
+}
+
+// Entries returns the number of entries read.
+func (r *Reader) Entries() int {
+	return r.v.Entries()
+}
+
+// Err returns the error which stopped Scan: a *golog.ChecksumError,
+// golog.ErrChecksumUnsealed or the error of the underlying reader.
+// It returns nil once a sealed log is read entirely.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Close closes the file opened by Open.
+func (r *Reader) Close() error {
+	if r.c == nil {
+		return nil
+	}
+	return r.c.Close()
+}
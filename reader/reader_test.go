@@ -0,0 +1,82 @@
+package reader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jayvib/golog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checksummed returns the log of lines checksummed with mode.
+func checksummed(t *testing.T, mode golog.ChecksumMode, lines ...string) []byte {
+	var out bytes.Buffer
+	w := golog.NewChecksumWriter(&out, mode)
+	for _, line := range lines {
+		_, err := w.Write([]byte(line + "\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return out.Bytes()
+}
+
+// readAll returns the entries read by r.
+func readAll(r *Reader) []string {
+	var entries []string
+	for r.Scan() {
+		entries = append(entries, r.Text())
+	}
+	return entries
+}
+
+func TestReader(t *testing.T) {
+	for _, mode := range []golog.ChecksumMode{golog.ChecksumCRC32, golog.ChecksumChain} {
+		log := checksummed(t, mode, "INFO: first", "INFO: second")
+
+		r := New(bytes.NewReader(log), mode)
+		assert.Equal(t, []string{"INFO: first", "INFO: second"}, readAll(r))
+		assert.NoError(t, r.Err())
+		assert.Equal(t, 2, r.Entries())
+
+		tampered := bytes.Replace(log, []byte("second"), []byte("SECOND"), 1)
+		r = New(bytes.NewReader(tampered), mode)
+		assert.Equal(t, []string{"INFO: first"}, readAll(r))
+		assert.Equal(t, &golog.ChecksumError{Line: 2}, r.Err())
+
+		lines := strings.SplitAfter(string(log), "\n")
+		r = New(strings.NewReader(lines[0]), mode)
+		assert.Equal(t, []string{"INFO: first"}, readAll(r))
+		assert.Equal(t, golog.ErrChecksumUnsealed, r.Err())
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	log := checksummed(t, golog.ChecksumChain, "INFO: archived")
+
+	path := filepath.Join(dir, "app.log")
+	require.NoError(t, ioutil.WriteFile(path, log, 0644))
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(log)
+	require.NoError(t, zw.Close())
+	require.NoError(t, ioutil.WriteFile(path+".gz", gz.Bytes(), 0644))
+
+	for _, p := range []string{path, path + ".gz"} {
+		r, err := Open(p, golog.ChecksumChain)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"INFO: archived"}, readAll(r))
+		assert.NoError(t, r.Err())
+		assert.NoError(t, r.Close())
+	}
+	_, err = Open(filepath.Join(dir, "missing.log"), golog.ChecksumChain)
+	assert.Error(t, err)
+}
@@ -0,0 +1,50 @@
+package golog
+
+import "net/http"
+
+// RecoverAndLog recovers a panic and logs it at ErrorLevel with the
+// fields of PanicFields, including the stack of the panic. It must be
+// deferred directly, so the panics of a goroutine don't crash the
+// program:
+//
+//	go func() {
+//		defer golog.RecoverAndLog()
+//		work()
+//	}()
+func RecoverAndLog() {
+	if r := recover(); r != nil {
+		logPanic(ErrorLogger, r)
+	}
+}
+
+// RecoverHandler returns an http.Handler calling next, recovering its
+// panics. A panic is logged at ErrorLevel, with the logger of the
+// context of the request and the fields of PanicFields along with the
+// method and the path of the request, and answered with a 500 status.
+// The http.ErrAbortHandler panics, aborting the response on purpose,
+// are not logged and panic again.
+func RecoverHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			p := recover()
+			if p == nil {
+				return
+			}
+			if p == http.ErrAbortHandler {
+				panic(p)
+			}
+			logPanic(FromContext(r.Context()).WithFields(Fields{
+				"http.method": r.Method,
+				"http.path":   r.URL.Path,
+			}), p)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// logPanic logs the recovered panic value r with l at ErrorLevel.
+// It must be called by the deferred function recovering r.
+func logPanic(l FieldLogger, r interface{}) {
+	l.WithFields(PanicFields(r)).Error("panic: " + panicValue(r))
+}
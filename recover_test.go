@@ -0,0 +1,75 @@
+package golog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecoverAndLog(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var out syncBuffer
+	ErrorLogger.SetOutput(&out)
+	defer ErrorLogger.SetOutput(os.Stdout)
+	ErrorLogger.SetFormatter(&JSONFormatter{})
+	defer ErrorLogger.SetFormatter(&TextFormatter{Flags: defaultFlags(ErrorLevel)})
+
+	require.NotPanics(t, func() {
+		defer RecoverAndLog()
+		panicIndex(nil, 1)
+	})
+	assert.Contains(t, out.String(), `"msg":"panic: runtime error: index out of range [1] with length 0"`)
+	assert.Regexp(t, `"panic.frame":"github.com/jayvib/golog.panicIndex [^"]*panic_test.go:\d+"`, out.String())
+	assert.Contains(t, out.String(), `"panic.stack":[`)
+
+	t.Run("no panic", func(t *testing.T) {
+		out.Reset()
+		func() {
+			defer RecoverAndLog()
+		}()
+		assert.Empty(t, out.String())
+	})
+}
+
+func TestRecoverHandler(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var out syncBuffer
+	l := newStdLogger(InfoLevel, &out, 0)
+	h := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/panic":
+			panic("boom")
+		case "/abort":
+			panic(http.ErrAbortHandler)
+		}
+		w.Write([]byte("ok"))
+	}))
+	serve := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req = req.WithContext(WithContext(context.Background(), l))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve("/panic")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "Internal Server Error\n", rec.Body.String())
+	assert.Regexp(t, `^ERROR: panic: boom http.method=GET http.path=/panic panic.frame=.*TestRecoverHandler.* `+
+		`panic.stack=.* panic.type=string panic.value=boom\n$`, out.String())
+
+	out.Reset()
+	rec = serve("/")
+	assert.Equal(t, "ok", rec.Body.String())
+	assert.Empty(t, out.String())
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() { serve("/abort") })
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,63 @@
+package golog
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Redacted is the replacement of the redacted values.
+const Redacted = "REDACTED"
+
+// SensitiveQueryParams are the substrings that mark a query parameter
+// as token-like. They are matched case-insensitively against the
+// parameter names by RedactURL.
+var SensitiveQueryParams = []string{
+	"token", "secret", "password", "passwd", "pwd", "key",
+	"signature", "sig", "credential", "auth", "session", "code",
+}
+
+// RedactURL returns raw in a form that is safe to log: the userinfo
+// and the fragment are removed, the values of token-like query
+// parameters are replaced with Redacted and the path is cleaned.
+// A URL that cannot be parsed is entirely redacted.
+//
+//	logger.WithFields(golog.Fields{"url": golog.RedactURL(req.URL.String())})
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Redacted
+	}
+	u.User = nil
+	u.Fragment = ""
+	if u.Path != "" {
+		cleaned := path.Clean(u.Path)
+		if strings.HasSuffix(u.Path, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		u.Path = cleaned
+		u.RawPath = ""
+	}
+	if u.RawQuery != "" {
+		q := u.Query()
+		for name := range q {
+			if isSensitiveParam(name) {
+				for i := range q[name] {
+					q[name][i] = Redacted
+				}
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String()
+}
+
+func isSensitiveParam(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range SensitiveQueryParams {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,310 @@
+package golog
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the layout of the time in the
+// name of the rotated files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateOption configures a RotatingFileWriter.
+type RotateOption func(w *RotatingFileWriter)
+
+// RotateMaxSize rotates the file before it exceeds n bytes.
+func RotateMaxSize(n int64) RotateOption {
+	return func(w *RotatingFileWriter) { w.maxSize = n }
+}
+
+// RotateInterval rotates the file when it is older than d.
+func RotateInterval(d time.Duration) RotateOption {
+	return func(w *RotatingFileWriter) { w.interval = d }
+}
+
+// RotateMaxAge removes the rotated files older than d.
+func RotateMaxAge(d time.Duration) RotateOption {
+	return func(w *RotatingFileWriter) { w.maxAge = d }
+}
+
+// RotateMaxBackups keeps at most n rotated files.
+func RotateMaxBackups(n int) RotateOption {
+	return func(w *RotatingFileWriter) { w.maxBackups = n }
+}
+
+// RotateCompress compresses the rotated files with gzip.
+func RotateCompress() RotateOption {
+	return func(w *RotatingFileWriter) { w.compress = true }
+}
+
+// RotatingFileWriter is an io.WriteCloser appending to a file that
+// is rotated by size or time. A rotated file is renamed with the
+// time of the rotation, e.g. app-2020-01-02T15-04-05.000.log, with
+// a counter when the files are rotated within a millisecond, e.g.
+// app-2020-01-02T15-04-05.000.1.log, and optionally compressed. The
+// old rotated files are removed in the background.
+type RotatingFileWriter struct {
+	path       string
+	maxSize    int64
+	interval   time.Duration
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+
+	mu       sync.Mutex
+	f        *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+	wg       sync.WaitGroup
+	cleaning sync.Mutex // serializes the cleanups
+}
+
+// NewRotatingFileWriter opens the file at path for appending,
+// creating it if needed, and returns a writer rotating it
+// according to opts. Without options the file is never rotated.
+func NewRotatingFileWriter(path string, opts ...RotateOption) (*RotatingFileWriter, error) {
+	w := &RotatingFileWriter{path: path}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *RotatingFileWriter) open() error {
+	f, err := os.OpenFile(w.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f, w.size, w.openedAt = f, fi.Size(), now()
+	return nil
+}
+
+// Write appends p to the file, rotating it first when needed.
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RotatingFileWriter) shouldRotate(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.maxSize > 0 && w.size+n > w.maxSize {
+		return true
+	}
+	return w.interval > 0 && now().Sub(w.openedAt) >= w.interval
+}
+
+// Rotate rotates the file immediately.
+func (w *RotatingFileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWriterClosed
+	}
+	return w.rotate()
+}
+
+func (w *RotatingFileWriter) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+	t := now()
+	backup, err := w.backupPath(t)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(w.path, backup); err != nil {
+		return err
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		w.cleanup(t)
+	}()
+	return nil
+}
+
+// backupPath returns the unused name of the file rotated at t. The
+// files rotated in the same millisecond are numbered, so none of them
+// is overwritten.
+func (w *RotatingFileWriter) backupPath(t time.Time) (string, error) {
+	ext := filepath.Ext(w.path)
+	base := strings.TrimSuffix(w.path, ext) + "-" + t.Format(backupTimeFormat)
+	for n := 0; ; n++ {
+		path := base + ext
+		if n > 0 {
+			path = base + "." + strconv.Itoa(n) + ext
+		}
+		// The file may be compressed in the background meanwhile,
+		// but either the file or its compression exists.
+		used, err := exists(path)
+		if err == nil && !used {
+			used, err = exists(path + ".gz")
+		}
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return path, nil
+		}
+	}
+}
+
+// exists reports whether there is a file at path.
+func exists(path string) (bool, error) {
+	_, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// backup is a rotated file.
+type backup struct {
+	path string
+	time time.Time
+	n    int // the counter of the files rotated at time
+}
+
+// backups returns the rotated files, the newest first.
+func (w *RotatingFileWriter) backups() ([]backup, error) {
+	ext := filepath.Ext(w.path)
+	prefix := filepath.Base(strings.TrimSuffix(w.path, ext)) + "-"
+	entries, err := ioutil.ReadDir(filepath.Dir(w.path))
+	if err != nil {
+		return nil, err
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		stamp := strings.TrimSuffix(name, ".gz")
+		if !strings.HasPrefix(stamp, prefix) || !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimPrefix(stamp, prefix), ext)
+		n := 0
+		if len(stamp) > len(backupTimeFormat) && stamp[len(backupTimeFormat)] == '.' {
+			if n, err = strconv.Atoi(stamp[len(backupTimeFormat)+1:]); err != nil || n <= 0 {
+				continue
+			}
+			stamp = stamp[:len(backupTimeFormat)]
+		}
+		t, err := time.Parse(backupTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{filepath.Join(filepath.Dir(w.path), name), t, n})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].time.Equal(backups[j].time) {
+			return backups[i].time.After(backups[j].time)
+		}
+		return backups[i].n > backups[j].n
+	})
+	return backups, nil
+}
+
+// cleanup removes the expired and extra rotated files
+// and compresses the others, after a rotation at t.
+func (w *RotatingFileWriter) cleanup(t time.Time) {
+	w.cleaning.Lock()
+	defer w.cleaning.Unlock()
+	backups, err := w.backups()
+	if err != nil {
+		reportf("golog: rotate: %v", err)
+		return
+	}
+	cutoff := t.Add(-w.maxAge)
+	for i, b := range backups {
+		if (w.maxBackups > 0 && i >= w.maxBackups) || (w.maxAge > 0 && b.time.Before(cutoff)) {
+			if err := os.Remove(b.path); err != nil {
+				reportf("golog: rotate: %v", err)
+			}
+			continue
+		}
+		if w.compress && !strings.HasSuffix(b.path, ".gz") {
+			if err := compressFile(b.path); err != nil {
+				reportf("golog: rotate: %v", err)
+			}
+		}
+	}
+}
+
+// compressFile replaces the file at path with its gzip
+// compression at path.gz.
+func compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(dst.Name())
+		}
+	}()
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Close closes the file and waits for the
+// background cleanups to complete.
+func (w *RotatingFileWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	err := w.f.Close()
+	w.mu.Unlock()
+	w.wg.Wait()
+	return err
+}
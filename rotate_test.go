@@ -0,0 +1,111 @@
+package golog
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listDir returns the sorted names of the files in dir.
+func listDir(t *testing.T, dir string) []string {
+	infos, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, fi := range infos {
+		names = append(names, fi.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotatingFileWriter(t *testing.T) {
+	defer func() { now = time.Now }()
+	clock := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "app.log")
+
+	w, err := NewRotatingFileWriter(path, RotateMaxSize(10), RotateMaxBackups(2))
+	require.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		clock = clock.Add(time.Second)
+		fmt.Fprintf(w, "line %d\n", i)
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{
+		"app-2020-01-02T03-04-08.000.log",
+		"app-2020-01-02T03-04-09.000.log",
+		"app.log",
+	}, listDir(t, dir))
+	b, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "line 3\n", string(b))
+	_, err = w.Write([]byte("closed\n"))
+	assert.Equal(t, ErrWriterClosed, err)
+
+	t.Run("interval, max age and compression", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "golog")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "app.log")
+
+		w, err := NewRotatingFileWriter(path, RotateInterval(time.Hour), RotateMaxAge(30*time.Minute), RotateCompress())
+		require.NoError(t, err)
+		fmt.Fprintln(w, "first")
+		clock = clock.Add(time.Hour)
+		fmt.Fprintln(w, "second")
+		clock = clock.Add(time.Hour)
+		fmt.Fprintln(w, "third")
+		require.NoError(t, w.Close())
+
+		assert.Equal(t, []string{"app-" + clock.Format(backupTimeFormat) + ".log.gz", "app.log"}, listDir(t, dir))
+		f, err := os.Open(filepath.Join(dir, "app-"+clock.Format(backupTimeFormat)+".log.gz"))
+		require.NoError(t, err)
+		defer f.Close()
+		zr, err := gzip.NewReader(f)
+		require.NoError(t, err)
+		b, err := ioutil.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Equal(t, "second\n", string(b))
+	})
+	t.Run("rotations within a millisecond", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "golog")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "app.log")
+
+		w, err := NewRotatingFileWriter(path, RotateMaxSize(10))
+		require.NoError(t, err)
+		for i := 0; i < 20; i++ {
+			fmt.Fprintf(w, "line %d\n", i)
+		}
+		require.NoError(t, w.Close())
+
+		names := listDir(t, dir)
+		require.Len(t, names, 20)
+		stamp := "app-" + clock.Format(backupTimeFormat)
+		assert.Contains(t, names, stamp+".log")
+		assert.Contains(t, names, stamp+".1.log")
+
+		backups, err := w.backups()
+		require.NoError(t, err)
+		require.Len(t, backups, 19)
+		for i, b := range backups {
+			got, err := ioutil.ReadFile(b.path)
+			require.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("line %d\n", 18-i), string(got))
+		}
+	})
+}
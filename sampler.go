@@ -0,0 +1,221 @@
+package golog
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sampler decides whether an entry is emitted. Sample returns
+// false to drop the entry. It must not retain the entry.
+type Sampler interface {
+	Sample(e *Entry) bool
+}
+
+// AddSampler registers s to be consulted on every entry emitted by
+// all the loggers, before their own samplers. An entry is dropped as
+// soon as one of the samplers rejects it.
+func AddSampler(s Sampler) {
+	updateState(func(st *globalState) {
+		samplers := make([]Sampler, len(st.samplers), len(st.samplers)+1)
+		copy(samplers, st.samplers)
+		st.samplers = append(samplers, s)
+	})
+}
+
+// ResetSamplers removes all the registered samplers.
+func ResetSamplers() {
+	updateState(func(st *globalState) {
+		st.samplers = nil
+	})
+}
+
+func sample(samplers []Sampler, e *Entry) bool {
+	for _, s := range samplers {
+		if !s.Sample(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// defaultMaxKeys is the default number of keys
+// tracked by a FieldSampler.
+const defaultMaxKeys = 10000
+
+// FieldSampler caps the number of entries per value of a field, e.g.
+// at most 100 Debug entries per user_id per minute, so a single tenant
+// can't dominate the log volume:
+//
+//	golog.AddSampler(&golog.FieldSampler{Key: "user_id", Level: golog.TraceLevel, Limit: 100, Interval: time.Minute})
+//
+// Entries without the field or above Level are always kept. The
+// fields must be set before the sampler is used.
+type FieldSampler struct {
+	// Key is the field the entries are grouped by.
+	Key string
+	// Level is the highest level that is sampled.
+	Level Level
+	// Limit is the number of entries kept per value and Interval.
+	Limit int
+	// Interval is the duration of the sampling window.
+	// Defaults to one second.
+	Interval time.Duration
+	// MaxKeys is the number of values tracked, the least recently
+	// seen are forgotten first. Defaults to 10000.
+	MaxKeys int
+
+	mu   sync.Mutex
+	lru  *list.List
+	keys map[string]*list.Element
+}
+
+// fieldWindow is the sampling window of one field value.
+type fieldWindow struct {
+	value string
+	start time.Time
+	count int
+}
+
+// Sample implements the Sampler interface.
+func (s *FieldSampler) Sample(e *Entry) bool {
+	if e.Level > s.Level {
+		return true
+	}
+	v, ok := e.Fields[s.Key]
+	if !ok {
+		return true
+	}
+	value := fmt.Sprint(v)
+	t := now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys == nil {
+		s.lru = list.New()
+		s.keys = make(map[string]*list.Element)
+	}
+	var w *fieldWindow
+	if el, ok := s.keys[value]; ok {
+		s.lru.MoveToFront(el)
+		w = el.Value.(*fieldWindow)
+	} else {
+		w = &fieldWindow{value: value, start: t}
+		s.keys[value] = s.lru.PushFront(w)
+		s.evict()
+	}
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if t.Sub(w.start) >= interval {
+		w.start, w.count = t, 0
+	}
+	w.count++
+	return w.count <= s.Limit
+}
+
+// evict forgets the least recently seen values
+// beyond MaxKeys.
+func (s *FieldSampler) evict() {
+	max := s.MaxKeys
+	if max <= 0 {
+		max = defaultMaxKeys
+	}
+	for s.lru.Len() > max {
+		el := s.lru.Back()
+		s.lru.Remove(el)
+		delete(s.keys, el.Value.(*fieldWindow).value)
+	}
+}
+
+// messageSamplerSize is the number of counters per level of
+// a MessageSampler. The messages sharing a counter are sampled
+// together.
+const messageSamplerSize = 4096
+
+// MessageSampler throttles the repeated entries: per level and
+// message, the first First entries of every Interval are kept,
+// then every Thereafter-th. A hot loop logging the same warning
+// millions of times only produces a few entries per second:
+//
+//	golog.AddSampler(&golog.MessageSampler{Level: golog.WarningLevel, First: 100, Thereafter: 100})
+//
+// The messages are counted in a fixed table of hashed counters,
+// so the memory is bounded and rare collisions are sampled
+// together. Entries above Level are always kept. The fields must
+// be set before the sampler is used.
+type MessageSampler struct {
+	// Level is the highest level that is sampled.
+	Level Level
+	// First is the number of entries kept per message
+	// and Interval.
+	First int
+	// Thereafter is the sampling rate after the first entries.
+	// Zero drops all of them.
+	Thereafter int
+	// Interval is the duration of the sampling window.
+	// Defaults to one second.
+	Interval time.Duration
+
+	once     sync.Once
+	counters *[DisabledLevel][messageSamplerSize]sampleCounter
+}
+
+// sampleCounter counts the entries of a sampling window.
+type sampleCounter struct {
+	resetAt int64
+	n       uint64
+}
+
+// Sample implements the Sampler interface.
+func (s *MessageSampler) Sample(e *Entry) bool {
+	if e.Level > s.Level || e.Level >= DisabledLevel {
+		return true
+	}
+	s.once.Do(func() {
+		s.counters = new([DisabledLevel][messageSamplerSize]sampleCounter)
+	})
+	interval := s.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	c := &s.counters[e.Level][fnv32a(e.Message)%messageSamplerSize]
+	n := c.inc(now(), interval)
+	if n <= uint64(s.First) {
+		return true
+	}
+	return s.Thereafter > 0 && (n-uint64(s.First))%uint64(s.Thereafter) == 0
+}
+
+// inc counts an entry at t and returns the number of entries
+// of the current window, which is restarted when it is over.
+func (c *sampleCounter) inc(t time.Time, interval time.Duration) uint64 {
+	tn := t.UnixNano()
+	resetAt := atomic.LoadInt64(&c.resetAt)
+	if resetAt > tn {
+		return atomic.AddUint64(&c.n, 1)
+	}
+	atomic.StoreUint64(&c.n, 1)
+	if !atomic.CompareAndSwapInt64(&c.resetAt, resetAt, tn+int64(interval)) {
+		// Another entry restarted the window.
+		return atomic.AddUint64(&c.n, 1)
+	}
+	return 1
+}
+
+// fnv32a returns the 32-bit FNV-1a hash of s.
+func fnv32a(s string) uint32 {
+	const (
+		offset = 2166136261
+		prime  = 16777619
+	)
+	h := uint32(offset)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= prime
+	}
+	return h
+}
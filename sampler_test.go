@@ -0,0 +1,143 @@
+package golog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldSampler(t *testing.T) {
+	defer func() { now = time.Now }()
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	newSampler := func() *FieldSampler {
+		return &FieldSampler{Key: "user_id", Level: TraceLevel, Limit: 2, Interval: time.Minute}
+	}
+	entry := func(lvl Level, user interface{}) *Entry {
+		e := &Entry{Level: lvl, Fields: Fields{}}
+		if user != nil {
+			e.Fields["user_id"] = user
+		}
+		return e
+	}
+
+	t.Run("caps the entries per value and interval", func(t *testing.T) {
+		s := newSampler()
+		assert.True(t, s.Sample(entry(DebugLevel, 1)))
+		assert.True(t, s.Sample(entry(DebugLevel, 1)))
+		assert.False(t, s.Sample(entry(DebugLevel, 1)))
+		assert.True(t, s.Sample(entry(DebugLevel, 2)))
+
+		clock = clock.Add(time.Minute)
+		assert.True(t, s.Sample(entry(DebugLevel, 1)))
+	})
+	t.Run("zero interval", func(t *testing.T) {
+		s := newSampler()
+		s.Interval = 0
+		assert.True(t, s.Sample(entry(DebugLevel, 1)))
+		assert.True(t, s.Sample(entry(DebugLevel, 1)))
+		assert.False(t, s.Sample(entry(DebugLevel, 1)))
+
+		clock = clock.Add(time.Second)
+		assert.True(t, s.Sample(entry(DebugLevel, 1)))
+	})
+	t.Run("entries above the level or without the field are kept", func(t *testing.T) {
+		s := newSampler()
+		for i := 0; i < 5; i++ {
+			assert.True(t, s.Sample(entry(InfoLevel, 1)))
+			assert.True(t, s.Sample(entry(DebugLevel, nil)))
+		}
+	})
+	t.Run("tracked values are bounded", func(t *testing.T) {
+		s := newSampler()
+		s.MaxKeys = 2
+		s.Sample(entry(DebugLevel, 1))
+		s.Sample(entry(DebugLevel, 1))
+		s.Sample(entry(DebugLevel, 2))
+		s.Sample(entry(DebugLevel, 3))
+		assert.Equal(t, 2, s.lru.Len())
+		// user 1 was forgotten, so its window starts again
+		assert.True(t, s.Sample(entry(DebugLevel, 1)))
+	})
+	t.Run("registered samplers drop entries", func(t *testing.T) {
+		defer ResetSamplers()
+		defer SetLevel(InfoLevel)
+		SetLevel(DebugLevel)
+		AddSampler(newSampler())
+		var out bytes.Buffer
+		l := newStdLogger(DebugLevel, &out, 0)
+		for i := 0; i < 5; i++ {
+			l.WithFields(Fields{"user_id": 42}).Print(fmt.Sprint("entry ", i))
+		}
+		assert.Equal(t, 2, strings.Count(out.String(), "\n"))
+	})
+}
+
+func TestMessageSampler(t *testing.T) {
+	defer func() { now = time.Now }()
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	kept := func(s *MessageSampler, lvl Level, msg string, n int) []int {
+		var got []int
+		for i := 1; i <= n; i++ {
+			if s.Sample(&Entry{Level: lvl, Message: msg}) {
+				got = append(got, i)
+			}
+		}
+		return got
+	}
+
+	t.Run("keeps the first entries then every nth", func(t *testing.T) {
+		s := &MessageSampler{Level: WarningLevel, First: 3, Thereafter: 4}
+		assert.Equal(t, []int{1, 2, 3, 7, 11}, kept(s, WarningLevel, "disk almost full", 12))
+		assert.Equal(t, []int{1, 2, 3}, kept(s, WarningLevel, "queue almost full", 5))
+		assert.Equal(t, []int{1, 2, 3}, kept(s, DebugLevel, "disk almost full", 5))
+
+		clock = clock.Add(time.Second)
+		assert.Equal(t, []int{1, 2, 3}, kept(s, WarningLevel, "disk almost full", 5))
+	})
+	t.Run("zero thereafter drops the rest", func(t *testing.T) {
+		s := &MessageSampler{Level: ErrorLevel, First: 1, Interval: time.Minute}
+		assert.Equal(t, []int{1}, kept(s, ErrorLevel, "failed", 10))
+	})
+	t.Run("entries above the level are kept", func(t *testing.T) {
+		s := &MessageSampler{Level: InfoLevel, First: 1}
+		assert.Len(t, kept(s, WarningLevel, "disk almost full", 5), 5)
+	})
+}
+
+func TestLogger_AddSampler(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	loggers := map[string]func(w *bytes.Buffer) FieldLogger{
+		"std": func(w *bytes.Buffer) FieldLogger { return newStdLogger(WarningLevel, w, 0) },
+		"logrus": func(w *bytes.Buffer) FieldLogger {
+			l := NewLogrusLogger(WarningLevel)
+			l.SetOutput(w)
+			l.SetFormatter(&TextFormatter{})
+			return l
+		},
+	}
+	for name, newLogger := range loggers {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := newLogger(&out)
+			l.AddSampler(&MessageSampler{Level: WarningLevel, First: 2})
+			child := l.WithFields(Fields{"loop": 1})
+			for i := 0; i < 5; i++ {
+				child.Print("retrying")
+				l.Warn("slow")
+			}
+			l.Error("failed")
+			assert.Equal(t, 2, strings.Count(out.String(), "retrying"))
+			assert.Equal(t, 2, strings.Count(out.String(), "slow"))
+			assert.Equal(t, 1, strings.Count(out.String(), "failed"))
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package golog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SelfTestStatus is the outcome of the synthetic entry of a level.
+type SelfTestStatus string
+
+const (
+	// SelfTestOK tells an entry written to its output, and flushed
+	// when the output buffers the entries.
+	SelfTestOK SelfTestStatus = "ok"
+	// SelfTestDisabled tells an entry whose level is disabled.
+	SelfTestDisabled SelfTestStatus = "disabled"
+	// SelfTestDropped tells an entry dropped by a mute rule, a
+	// sampler or the level of the named loggers.
+	SelfTestDropped SelfTestStatus = "dropped"
+	// SelfTestHeld tells an entry held by a paused output.
+	SelfTestHeld SelfTestStatus = "held"
+	// SelfTestFailed tells an entry whose output failed to
+	// write or flush it.
+	SelfTestFailed SelfTestStatus = "failed"
+)
+
+// SelfTestResult is the outcome of the synthetic entry of a level.
+type SelfTestResult struct {
+	Level Level `json:"level"`
+	// Output describes the output of the level, e.g. "*os.File /dev/stdout".
+	Output string         `json:"output"`
+	Status SelfTestStatus `json:"status"`
+	Error  string         `json:"error,omitempty"`
+	// Flushed reports whether the output was flushed,
+	// when it buffers the entries.
+	Flushed bool `json:"flushed"`
+}
+
+// SelfTestReport is the report of SelfTest.
+type SelfTestReport struct {
+	// ID is the value of the selftest.id field of the entries.
+	ID      string           `json:"id"`
+	Results []SelfTestResult `json:"results"`
+}
+
+// OK reports whether no output failed.
+func (r *SelfTestReport) OK() bool {
+	for _, res := range r.Results {
+		if res.Status == SelfTestFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// SelfTest emits a synthetic entry at every level through the
+// configured pipeline of the package level loggers, with the
+// selftest.id and selftest.level fields, and reports what became of
+// them, so the deployment smoke tests can check the logging works.
+// The outputs buffering the entries are flushed to verify they reach
+// their destination. The error is the one of ctx when it is done
+// before the end of the test, along with the partial report.
+func SelfTest(ctx context.Context) (*SelfTestReport, error) {
+	report := &SelfTestReport{ID: fmt.Sprintf("%x", now().UnixNano())}
+	for _, l := range packageLoggers() {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		res := SelfTestResult{Level: l.level, Output: outputName(LevelOutput(l.level))}
+		e := &Entry{
+			Level:   l.level,
+			Message: "golog self-test",
+			Fields:  Fields{"selftest.id": report.ID, "selftest.level": l.level.name()},
+			probe:   &selfTestProbe{},
+		}
+		st := getState()
+		switch {
+		case !st.enabled(e.Level, e.Fields):
+			res.Status = SelfTestDisabled
+		case st.isMuted(e.Level, func() string { return e.Message }):
+			res.Status = SelfTestDropped
+		default:
+			attachGlobalFields(st, e)
+			l.emitOrdered(st, e, 0)
+			if err := e.probe.verify(ctx, &res); err != nil {
+				report.Results = append(report.Results, res)
+				return report, err
+			}
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report, nil
+}
+
+// selfTestProbe records what became of the entry of SelfTest.
+type selfTestProbe struct {
+	w       io.Writer
+	written bool
+	held    bool
+	err     error
+}
+
+// record records the write of the entry to w, held by a pause or
+// failed with err. It is called by emit on every entry.
+func (p *selfTestProbe) record(w io.Writer, held bool, err error) {
+	if p != nil {
+		p.w, p.written, p.held, p.err = w, !held, held, err
+	}
+}
+
+// verify sets the status of res from p, flushing the output
+// of the entry when it buffers the entries.
+func (p *selfTestProbe) verify(ctx context.Context, res *SelfTestResult) error {
+	switch {
+	case p.held:
+		res.Status = SelfTestHeld
+		return nil
+	case !p.written:
+		res.Status = SelfTestDropped
+		return nil
+	case p.err != nil:
+		res.Status, res.Error = SelfTestFailed, p.err.Error()
+		return nil
+	}
+	res.Status = SelfTestOK
+	f, ok := p.w.(flusher)
+	if !ok {
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() { done <- f.Flush() }()
+	select {
+	case err := <-done:
+		res.Flushed = err == nil
+		if err != nil {
+			res.Status, res.Error = SelfTestFailed, err.Error()
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// outputName describes the output w.
+func outputName(w io.Writer) string {
+	name := fmt.Sprintf("%T", w)
+	if f, ok := w.(*os.File); ok {
+		name += " " + f.Name()
+	}
+	return name
+}
@@ -0,0 +1,59 @@
+package golog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	var info, errs syncBuffer
+	buffered := NewBufferedWriter(&errs, 1024, 0)
+	defer buffered.Close()
+	SetLevelOutput(InfoLevel, &info)
+	SetLevelOutput(WarningLevel, failingWriter{})
+	SetLevelOutput(ErrorLevel, buffered)
+	defer SetOutput(os.Stdout)
+
+	report, err := SelfTest(context.Background())
+	require.NoError(t, err)
+	assert.False(t, report.OK())
+	assert.Equal(t, []SelfTestResult{
+		{Level: DebugLevel, Output: "*os.File /dev/stdout", Status: SelfTestDisabled},
+		{Level: TraceLevel, Output: "*os.File /dev/stdout", Status: SelfTestDisabled},
+		{Level: InfoLevel, Output: "*golog.syncBuffer", Status: SelfTestOK},
+		{Level: WarningLevel, Output: "golog.failingWriter", Status: SelfTestFailed, Error: "disk full"},
+		{Level: ErrorLevel, Output: "*golog.BufferedWriter", Status: SelfTestOK, Flushed: true},
+	}, report.Results)
+	assert.Contains(t, info.String(), "golog self-test selftest.id="+report.ID+" selftest.level=info\n")
+	assert.Contains(t, errs.String(), "golog self-test selftest.id="+report.ID+" selftest.level=error\n")
+
+	b, err := json.Marshal(report.Results[3])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"level":"warning","output":"golog.failingWriter","status":"failed","error":"disk full","flushed":false}`, string(b))
+
+	t.Run("dropped", func(t *testing.T) {
+		AddSampler(&FieldSampler{Key: "selftest.level", Level: InfoLevel, Interval: time.Hour})
+		defer ResetSamplers()
+		SetLevelOutput(WarningLevel, &info)
+		report, err := SelfTest(context.Background())
+		require.NoError(t, err)
+		assert.True(t, report.OK())
+		assert.Equal(t, SelfTestDropped, report.Results[2].Status)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		report, err := SelfTest(ctx)
+		assert.Equal(t, context.Canceled, err)
+		assert.Empty(t, report.Results)
+	})
+}
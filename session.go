@@ -0,0 +1,223 @@
+package golog
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSessionSinkClosed is returned by SessionSink.Serve
+// after a call to Close.
+var ErrSessionSinkClosed = errors.New("golog: session sink closed")
+
+// SessionSink is a sink streaming the entries live to the remote
+// sessions attached to it, to debug headless devices in the field.
+// A session is either an established stream, such as an SSH channel,
+// attached with Attach, or a client of a listener served with Serve,
+// e.g. `nc device 7000`, which must send the token on its first line
+// before receiving the entries:
+//
+//	sink := golog.NewSessionSink(os.Getenv("LOG_SESSION_TOKEN"))
+//	go sink.Serve(ln)
+//	golog.SetOutput(io.MultiWriter(os.Stdout, sink))
+//
+// The sessions that can't keep up miss the entries, so a slow
+// session doesn't block the logging calls.
+type SessionSink struct {
+	token string
+	// AuthTimeout is the time a client of Serve has to send the
+	// token. Defaults to 10 seconds.
+	AuthTimeout time.Duration
+	// BufferSize is the number of pending entries per session.
+	// Defaults to 256.
+	BufferSize int
+
+	mu        sync.Mutex
+	sessions  map[*session]struct{}
+	listeners map[net.Listener]struct{}
+	closed    bool
+}
+
+// session is a remote session of a SessionSink.
+type session struct {
+	w     io.WriteCloser
+	queue chan []byte
+	once  sync.Once
+}
+
+// NewSessionSink returns a SessionSink whose listener
+// sessions must authenticate with token.
+func NewSessionSink(token string) *SessionSink {
+	return &SessionSink{
+		token:     token,
+		sessions:  make(map[*session]struct{}),
+		listeners: make(map[net.Listener]struct{}),
+	}
+}
+
+// Write sends a copy of p to the attached sessions.
+// It never fails.
+func (s *SessionSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sessions) == 0 {
+		return len(p), nil
+	}
+	b := make([]byte, len(p))
+	copy(b, p)
+	for sess := range s.sessions {
+		select {
+		case sess.queue <- b:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Attach streams the entries to w until a write fails, the
+// returned detach function is called or the sink is closed.
+// w is closed when the session ends.
+func (s *SessionSink) Attach(w io.WriteCloser) (detach func()) {
+	size := s.BufferSize
+	if size <= 0 {
+		size = 256
+	}
+	sess := &session{w: w, queue: make(chan []byte, size)}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		w.Close()
+		return func() {}
+	}
+	s.sessions[sess] = struct{}{}
+	s.mu.Unlock()
+	go s.stream(sess)
+	return func() { s.detach(sess) }
+}
+
+func (s *SessionSink) stream(sess *session) {
+	for b := range sess.queue {
+		if _, err := sess.w.Write(b); err != nil {
+			s.detach(sess)
+			// Drain the queue until detach closes it.
+			for range sess.queue {
+			}
+			return
+		}
+	}
+}
+
+func (s *SessionSink) detach(sess *session) {
+	s.mu.Lock()
+	delete(s.sessions, sess)
+	s.mu.Unlock()
+	sess.once.Do(func() {
+		close(sess.queue)
+		sess.w.Close()
+	})
+}
+
+// Sessions returns the number of attached sessions.
+func (s *SessionSink) Sessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// Serve accepts connections on ln and attaches the ones sending
+// the token on their first line, until the sink is closed or ln
+// fails. It always returns a non-nil error.
+func (s *SessionSink) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrSessionSinkClosed
+	}
+	s.listeners[ln] = struct{}{}
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			delete(s.listeners, ln)
+			s.mu.Unlock()
+			if closed {
+				return ErrSessionSinkClosed
+			}
+			return err
+		}
+		go s.authenticate(conn)
+	}
+}
+
+func (s *SessionSink) authenticate(conn net.Conn) {
+	timeout := s.AuthTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	token := strings.TrimRight(line, "\r\n")
+	if err != nil || s.token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+		reportf("golog: session sink: authentication failed from %v", conn.RemoteAddr())
+		io.WriteString(conn, "authentication failed\n")
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+	s.Attach(conn)
+}
+
+// Close stops the listeners and ends the sessions.
+func (s *SessionSink) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	for ln := range s.listeners {
+		if cerr := ln.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	sessions := make([]*session, 0, len(s.sessions))
+	for sess := range s.sessions {
+		sessions = append(sessions, sess)
+	}
+	s.mu.Unlock()
+	for _, sess := range sessions {
+		s.detach(sess)
+	}
+	return err
+}
+
+// NewRemoteSessionWriter returns a SocketWriter streaming the
+// entries to a remote listener, e.g. `nc -l 7000` on the laptop of
+// the operator. The token is sent on the first line of every
+// connection to identify the device, and tlsConfig, when not nil,
+// authenticates the listener.
+func NewRemoteSessionWriter(network, addr, token string, tlsConfig *tls.Config) *SocketWriter {
+	w := newSocketWriter(func() (io.WriteCloser, error) {
+		conn, err := dialNetwork(network, addr, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(conn, token+"\n"); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	})
+	w.frame = func(w io.Writer, p []byte) error {
+		_, err := w.Write(p)
+		return err
+	}
+	return w
+}
@@ -0,0 +1,111 @@
+package golog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bufferCloser is a syncBuffer recording its closing.
+type bufferCloser struct {
+	syncBuffer
+	closed chan struct{}
+}
+
+func (b *bufferCloser) Close() error {
+	close(b.closed)
+	return nil
+}
+
+func TestSessionSink(t *testing.T) {
+	t.Run("attached sessions receive the entries", func(t *testing.T) {
+		sink := NewSessionSink("secret")
+		sink.Write([]byte("before\n"))
+		w := &bufferCloser{closed: make(chan struct{})}
+		detach := sink.Attach(w)
+		assert.Equal(t, 1, sink.Sessions())
+
+		l := newStdLogger(InfoLevel, sink, 0)
+		SetLevel(InfoLevel)
+		l.Print("hello")
+		assert.Eventually(t, func() bool { return w.String() == "INFO: hello\n" }, time.Second, 5*time.Millisecond)
+
+		detach()
+		<-w.closed
+		assert.Zero(t, sink.Sessions())
+		require.NoError(t, sink.Close())
+	})
+	t.Run("listener sessions authenticate", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		sink := NewSessionSink("secret")
+		defer sink.Close()
+		served := make(chan error, 1)
+		go func() { served <- sink.Serve(ln) }()
+
+		errs := make(chan error, 1)
+		SetDiagnosticHandler(func(err error) { errs <- err })
+		defer SetDiagnosticHandler(nil)
+		bad, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer bad.Close()
+		io.WriteString(bad, "guess\n")
+		reply, _ := bufio.NewReader(bad).ReadString('\n')
+		assert.Equal(t, "authentication failed\n", reply)
+
+		good, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer good.Close()
+		io.WriteString(good, "secret\r\n")
+		assert.Eventually(t, func() bool { return sink.Sessions() == 1 }, time.Second, 5*time.Millisecond)
+		sink.Write([]byte("hello\n"))
+		line, err := bufio.NewReader(good).ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "hello\n", line)
+
+		require.NoError(t, sink.Close())
+		assert.Equal(t, ErrSessionSinkClosed, <-served)
+		assert.Contains(t, (<-errs).Error(), "authentication failed")
+	})
+}
+
+func TestRemoteSessionWriter(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var lines []string
+		for len(lines) < 2 {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				break
+			}
+			lines = append(lines, line)
+		}
+		received <- strings.Join(lines, "")
+	}()
+
+	w := NewRemoteSessionWriter("tcp", ln.Addr().String(), "device-42", nil)
+	defer w.Close()
+	_, err = w.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	select {
+	case got := <-received:
+		assert.Equal(t, "device-42\nhello\n", got)
+	case <-time.After(time.Second):
+		t.Fatal("entry not received")
+	}
+}
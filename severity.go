@@ -0,0 +1,60 @@
+package golog
+
+// SeverityKey is the field key of the numeric severity
+// added by SeverityScheme.Process.
+const SeverityKey = "severity"
+
+// SeverityScheme is a numbering of the levels, so the entries of
+// services written in other languages can be sorted and filtered
+// consistently by a common pipeline. Its Process method is a
+// Processor adding the severity of the entries as the SeverityKey
+// field:
+//
+//	golog.AddProcessor(golog.OTelSeverity.Process)
+type SeverityScheme int
+
+const (
+	// GologSeverity numbers the levels with their Level value,
+	// from DebugLevel (0) to ErrorLevel (4).
+	GologSeverity SeverityScheme = iota
+	// SyslogSeverityScheme numbers the levels as the syslog
+	// severities of RFC 5424, see SyslogSeverity. Lower is more
+	// severe.
+	SyslogSeverityScheme
+	// OTelSeverity numbers the levels as the SeverityNumber of the
+	// OpenTelemetry logs data model: TRACE (1), DEBUG (5), INFO (9),
+	// WARN (13) and ERROR (17).
+	OTelSeverity
+)
+
+// Severity returns the number of lvl in s.
+func (s SeverityScheme) Severity(lvl Level) int {
+	switch s {
+	case SyslogSeverityScheme:
+		return SyslogSeverity(lvl)
+	case OTelSeverity:
+		return OTelSeverityNumber(lvl)
+	}
+	return int(lvl)
+}
+
+// Process adds the severity of the entry as the SeverityKey field.
+func (s SeverityScheme) Process(e *Entry) {
+	e.Fields[SeverityKey] = s.Severity(e.Level)
+}
+
+// OTelSeverityNumber returns the SeverityNumber of lvl in the
+// OpenTelemetry logs data model.
+func OTelSeverityNumber(lvl Level) int {
+	switch lvl {
+	case TraceLevel:
+		return 1
+	case DebugLevel:
+		return 5
+	case WarningLevel:
+		return 13
+	case ErrorLevel:
+		return 17
+	}
+	return 9
+}
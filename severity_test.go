@@ -0,0 +1,42 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeverityScheme_Severity(t *testing.T) {
+	levels := []Level{DebugLevel, TraceLevel, InfoLevel, WarningLevel, ErrorLevel}
+	tests := []struct {
+		scheme SeverityScheme
+		want   []int
+	}{
+		{GologSeverity, []int{0, 1, 2, 3, 4}},
+		{SyslogSeverityScheme, []int{7, 7, 6, 4, 3}},
+		{OTelSeverity, []int{5, 1, 9, 13, 17}},
+	}
+	for _, tt := range tests {
+		for i, lvl := range levels {
+			assert.Equal(t, tt.want[i], tt.scheme.Severity(lvl), "%d %s", tt.scheme, lvl.name())
+		}
+	}
+}
+
+func TestSeverityScheme_Process(t *testing.T) {
+	defer ResetProcessors()
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	AddProcessor(OTelSeverity.Process)
+
+	var out bytes.Buffer
+	l := newStdLogger(InfoLevel, &out, 0)
+	l.SetFormatter(&JSONFormatter{})
+	l.Warn("slow")
+	l.WithFields(Fields{SeverityKey: "high"}).Error("failed")
+	lines := strings.Split(out.String(), "\n")
+	assert.Contains(t, lines[0], `"msg":"slow","severity":13}`)
+	assert.Contains(t, lines[1], `"msg":"failed","severity":17}`, "the severity overrides the fields")
+}
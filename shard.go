@@ -0,0 +1,176 @@
+package golog
+
+import (
+	"container/list"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// defaultShardMaxOpen is the default number of files
+// kept open by a ShardedFileHook.
+const defaultShardMaxOpen = 64
+
+// ShardOption configures a ShardedFileHook.
+type ShardOption func(h *ShardedFileHook)
+
+// ShardMaxOpen keeps at most n files open. The least recently
+// written file is closed to open another one, and reopened for
+// appending when written again.
+func ShardMaxOpen(n int) ShardOption {
+	return func(h *ShardedFileHook) { h.maxOpen = n }
+}
+
+// ShardFormatter formats the entries with f. Defaults to a
+// TextFormatter with the log.LstdFlags flags.
+func ShardFormatter(f Formatter) ShardOption {
+	return func(h *ShardedFileHook) { h.formatter = f }
+}
+
+// ShardDefault writes the entries without the key to the file
+// of value. By default these entries are not written.
+func ShardDefault(value string) ShardOption {
+	return func(h *ShardedFileHook) { h.fallback = value }
+}
+
+// ShardedFileHook is a Hook writing every entry to a file chosen by
+// the value of one of its fields, e.g. one file per job_id for the
+// batch systems keeping the logs of every run apart:
+//
+//	h, _ := golog.NewShardedFileHook("/var/log/jobs", "job_id")
+//	golog.AddHook(h)
+//	defer h.Close()
+//
+// The file of the value v is named v.log, with the characters other
+// than letters, digits, '.', '-' and '_' replaced by '_'.
+type ShardedFileHook struct {
+	dir       string
+	key       string
+	maxOpen   int
+	formatter Formatter
+	fallback  string
+
+	mu     sync.Mutex
+	files  map[string]*list.Element
+	lru    *list.List // of *shardFile, the most recently used first
+	closed bool
+}
+
+type shardFile struct {
+	name string
+	f    *os.File
+}
+
+// NewShardedFileHook returns a ShardedFileHook writing the entries to
+// files in dir, created if needed, chosen by the value of the field
+// key and configured by opts.
+func NewShardedFileHook(dir, key string, opts ...ShardOption) (*ShardedFileHook, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	h := &ShardedFileHook{
+		dir:       dir,
+		key:       key,
+		maxOpen:   defaultShardMaxOpen,
+		formatter: &TextFormatter{Flags: log.LstdFlags},
+		files:     make(map[string]*list.Element),
+		lru:       list.New(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.maxOpen < 1 {
+		h.maxOpen = 1
+	}
+	return h, nil
+}
+
+// Fire implements the Hook interface.
+func (h *ShardedFileHook) Fire(e *Entry) error {
+	value := h.fallback
+	if v, ok := e.Fields[h.key]; ok {
+		value = fmt.Sprint(v)
+	}
+	if value == "" {
+		return nil
+	}
+	b, err := safeFormat(h.formatter, e)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return ErrWriterClosed
+	}
+	f, err := h.file(shardName(value))
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	return err
+}
+
+// file returns the open file name, opening it and closing the
+// least recently used file when needed.
+func (h *ShardedFileHook) file(name string) (*os.File, error) {
+	if el, ok := h.files[name]; ok {
+		h.lru.MoveToFront(el)
+		return el.Value.(*shardFile).f, nil
+	}
+	for h.lru.Len() >= h.maxOpen {
+		last := h.lru.Back()
+		sf := h.lru.Remove(last).(*shardFile)
+		delete(h.files, sf.name)
+		sf.f.Close()
+	}
+	f, err := os.OpenFile(filepath.Join(h.dir, name), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	h.files[name] = h.lru.PushFront(&shardFile{name: name, f: f})
+	return f, nil
+}
+
+// OpenFiles returns the number of files currently open.
+func (h *ShardedFileHook) OpenFiles() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.lru.Len()
+}
+
+// Close closes the open files. The entries fired after
+// Close are not written.
+func (h *ShardedFileHook) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	var first error
+	for el := h.lru.Front(); el != nil; el = el.Next() {
+		if err := el.Value.(*shardFile).f.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	h.files = make(map[string]*list.Element)
+	h.lru.Init()
+	return first
+}
+
+// shardName returns the name of the file of value.
+func shardName(value string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, value)
+	if name == "." || name == ".." {
+		name = strings.Repeat("_", len(name))
+	}
+	return name + ".log"
+}
@@ -0,0 +1,60 @@
+package golog
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedFileHook(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	h, err := NewShardedFileHook(dir, "job_id", ShardMaxOpen(2), ShardFormatter(&TextFormatter{}))
+	require.NoError(t, err)
+	l := newStdLogger(InfoLevel, ioutil.Discard, 0)
+	l.AddHook(h)
+
+	l.WithFields(Fields{"job_id": "a"}).Print("start a")
+	l.WithFields(Fields{"job_id": 2}).Print("start 2")
+	l.WithFields(Fields{"job_id": "../c"}).Print("start c")
+	assert.Equal(t, 2, h.OpenFiles())
+	l.WithFields(Fields{"job_id": "a"}).Print("end a")
+	l.Print("no job")
+
+	read := func(name string) string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		return string(b)
+	}
+	assert.Equal(t, "INFO: start a job_id=a\nINFO: end a job_id=a\n", read("a.log"))
+	assert.Equal(t, "INFO: start 2 job_id=2\n", read("2.log"))
+	assert.Equal(t, "INFO: start c job_id=../c\n", read(".._c.log"))
+	files, _ := ioutil.ReadDir(dir)
+	assert.Len(t, files, 3)
+
+	t.Run("default file", func(t *testing.T) {
+		h, err := NewShardedFileHook(dir, "job_id", ShardDefault("unknown"))
+		require.NoError(t, err)
+		require.NoError(t, h.Fire(&Entry{Level: InfoLevel, Message: "no job"}))
+		require.NoError(t, h.Close())
+		assert.Equal(t, "INFO: 0001/01/01 00:00:00 no job\n", read("unknown.log"))
+		assert.Equal(t, ErrWriterClosed, h.Fire(&Entry{Level: InfoLevel, Message: "closed"}))
+	})
+
+	require.NoError(t, h.Close())
+	assert.Equal(t, 0, h.OpenFiles())
+}
+
+func TestShardName(t *testing.T) {
+	assert.Equal(t, "tenant-1.log", shardName("tenant-1"))
+	assert.Equal(t, "a_b_c.log", shardName("a/b c"))
+	assert.Equal(t, "__.log", shardName(".."))
+}
@@ -0,0 +1,22 @@
+package golog
+
+// signalLevel changes the global level for a level control
+// signal: down lowers it one notch toward DebugLevel, otherwise
+// base is restored. The change is logged at the new level, so it
+// is always visible.
+func signalLevel(down bool, base Level) {
+	current := GetLevel()
+	lvl := base
+	if down {
+		if lvl = current; lvl > DebugLevel {
+			lvl--
+		}
+	}
+	if lvl == current {
+		return
+	}
+	SetLevel(lvl)
+	if l := packageLogger(lvl); l != nil {
+		l.outputLevel(0, lvl, "log level changed to "+lvl.name(), "")
+	}
+}
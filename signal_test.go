@@ -0,0 +1,42 @@
+//go:build !windows
+// +build !windows
+
+package golog
+
+import (
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableSignalLevelControl(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+
+	stop := EnableSignalLevelControl()
+	defer stop()
+	signal := func(sig syscall.Signal, want Level) {
+		require.NoError(t, syscall.Kill(os.Getpid(), sig))
+		assert.Eventually(t, func() bool { return GetLevel() == want }, time.Second, 5*time.Millisecond)
+	}
+	signal(syscall.SIGUSR1, TraceLevel)
+	signal(syscall.SIGUSR1, DebugLevel)
+	signal(syscall.SIGUSR1, DebugLevel)
+	signal(syscall.SIGUSR2, InfoLevel)
+	assert.Eventually(t, func() bool {
+		return strings.Count(out.String(), "log level changed to") == 3
+	}, time.Second, 5*time.Millisecond)
+	assert.Regexp(t, `TRACE: .* log level changed to trace\n`, out.String())
+	assert.Regexp(t, `INFO: .* log level changed to info\n`, out.String())
+
+	stop()
+	stop()
+}
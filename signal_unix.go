@@ -0,0 +1,43 @@
+//go:build !windows
+// +build !windows
+
+package golog
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// EnableSignalLevelControl lowers the global level one notch toward
+// DebugLevel on every SIGUSR1 and restores the current level on
+// SIGUSR2, so verbose logs can be obtained from a running process:
+//
+//	kill -USR1 $(pidof app)
+//
+// The changes are logged at the new level. stop stops listening
+// to the signals. It is a no-op on Windows.
+func EnableSignalLevelControl() (stop func()) {
+	base := GetLevel()
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGUSR1, syscall.SIGUSR2)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case sig := <-sigs:
+				signalLevel(sig == syscall.SIGUSR1, base)
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(sigs)
+			close(done)
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package golog
+
+// EnableSignalLevelControl is a no-op on Windows, which has
+// no SIGUSR1 and SIGUSR2.
+func EnableSignalLevelControl() (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,148 @@
+package golog
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// entryOverhead is the size of an encoded entry without its
+// message and fields: the time, the level and the separators.
+const entryOverhead = len(`{"time":"2006-01-02T15:04:05.999999999-07:00","level":"warning","msg":""}`) + 1
+
+// EstimatedSize returns an estimate of the size in bytes of e once
+// encoded by the formatters of the package, without encoding it, so
+// the quota-aware components, e.g. a budget enforcer or the billing,
+// can account for the bytes of the entries before they are emitted.
+// The estimate counts the message, the keys and values of the fields
+// and the overhead of the time, the level and the separators. The
+// values which are neither strings, numbers, booleans, times nor
+// errors are formatted with fmt.Sprint to be measured.
+func (e *Entry) EstimatedSize() int {
+	n := entryOverhead + len(e.Message)
+	for k, v := range e.ResolvedFields() {
+		// The quotes, the separator of the key and the value,
+		// and the separator of the fields.
+		n += len(k) + valueSize(v) + 4
+	}
+	return n
+}
+
+// valueSize returns the estimated size of the field value v.
+func valueSize(v interface{}) int {
+	var buf [32]byte
+	switch v := v.(type) {
+	case nil:
+		return len("null")
+	case string:
+		return len(v) + 2
+	case Stacktrace:
+		return len(v) + 2
+	case []byte:
+		return len(v) + 2
+	case bool:
+		return len(strconv.AppendBool(buf[:0], v))
+	case int:
+		return len(strconv.AppendInt(buf[:0], int64(v), 10))
+	case int64:
+		return len(strconv.AppendInt(buf[:0], v, 10))
+	case int32:
+		return len(strconv.AppendInt(buf[:0], int64(v), 10))
+	case uint:
+		return len(strconv.AppendUint(buf[:0], uint64(v), 10))
+	case uint64:
+		return len(strconv.AppendUint(buf[:0], v, 10))
+	case uint32:
+		return len(strconv.AppendUint(buf[:0], uint64(v), 10))
+	case float64:
+		return len(strconv.AppendFloat(buf[:0], v, 'g', -1, 64))
+	case float32:
+		return len(strconv.AppendFloat(buf[:0], float64(v), 'g', -1, 32))
+	case time.Time:
+		return len(time.RFC3339Nano) + 2
+	case time.Duration:
+		return len(v.String()) + 2
+	case error:
+		return len(v.Error()) + 2
+	}
+	return len(fmt.Sprint(v)) + 2
+}
+
+// sinkBytes holds the number of bytes written per sink.
+var sinkBytes sync.Map
+
+// countBytes records n bytes written to the sink w.
+func countBytes(w io.Writer, n int) {
+	if n <= 0 || w == nil {
+		return
+	}
+	key := sinkKey(w)
+	v, ok := sinkBytes.Load(key)
+	if !ok {
+		v, _ = sinkBytes.LoadOrStore(key, new(uint64))
+	}
+	atomic.AddUint64(v.(*uint64), uint64(n))
+}
+
+// sinkKey returns the key of the counter of w, w itself unless it
+// can't be a map key, in which case the sinks of its type share
+// a counter.
+func sinkKey(w io.Writer) interface{} {
+	if reflect.TypeOf(w).Comparable() {
+		return w
+	}
+	return outputName(w)
+}
+
+// BytesWritten returns the number of bytes written to the sink w by
+// all the loggers since the start of the program or the last call
+// to ResetCounters.
+func BytesWritten(w io.Writer) uint64 {
+	if v, ok := sinkBytes.Load(sinkKey(w)); ok {
+		return atomic.LoadUint64(v.(*uint64))
+	}
+	return 0
+}
+
+// SinkBytes returns the number of bytes written per sink by all the
+// loggers since the start of the program or the last call to
+// ResetCounters, keyed by the description of the sinks, e.g.
+// "*os.File /dev/stdout". The sinks with the same description
+// are summed.
+func SinkBytes() map[string]uint64 {
+	m := make(map[string]uint64)
+	sinkBytes.Range(func(k, v interface{}) bool {
+		name, ok := k.(string)
+		if !ok {
+			name = outputName(k.(io.Writer))
+		}
+		m[name] += atomic.LoadUint64(v.(*uint64))
+		return true
+	})
+	return m
+}
+
+// resetBytes removes the byte counters of the sinks, so
+// the sinks replaced since are not retained.
+func resetBytes() {
+	sinkBytes.Range(func(k, _ interface{}) bool {
+		sinkBytes.Delete(k)
+		return true
+	})
+}
+
+// sinkCounter counts the bytes written to the sink w, for the
+// backends writing to their sink themselves, e.g. logrus.
+type sinkCounter struct {
+	w io.Writer
+}
+
+func (c sinkCounter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	countBytes(c.w, n)
+	return n, err
+}
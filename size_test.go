@@ -0,0 +1,59 @@
+package golog
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntry_EstimatedSize(t *testing.T) {
+	entries := []*Entry{
+		{Level: InfoLevel, Message: "hello"},
+		{Level: WarningLevel, Message: "slow request", Fields: Fields{
+			"path":     "/users/42",
+			"status":   503,
+			"elapsed":  1500 * time.Millisecond,
+			"ratio":    0.25,
+			"cached":   false,
+			"error":    errors.New("upstream timeout"),
+			"tags":     []string{"a", "b"},
+			"attempts": int64(3),
+		}},
+		{Level: ErrorLevel, Message: strings.Repeat("x", 4096), Fields: Fields{"user": nil}},
+	}
+	for _, e := range entries {
+		e.Time = time.Date(2020, 1, 2, 3, 4, 5, 123456789, time.UTC)
+		b, err := (&JSONFormatter{}).Format(e)
+		require.NoError(t, err)
+		assert.InEpsilon(t, len(b), e.EstimatedSize(), 0.15, string(b))
+	}
+}
+
+func TestBytesWritten(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	ResetCounters()
+	defer ResetCounters()
+
+	std := &syncBuffer{}
+	l := newStdLogger(InfoLevel, std, 0)
+	l.Info("hello")
+	l.Debug("disabled")
+	assert.Equal(t, uint64(len("INFO: hello\n")), BytesWritten(std))
+
+	lr := NewLogrusLogger(InfoLevel)
+	lr.SetFormatter(&TextFormatter{})
+	other := &syncBuffer{}
+	lr.SetOutput(other)
+	lr.Warn("careful")
+	assert.Equal(t, uint64(len("WARNING: careful\n")), BytesWritten(other))
+	assert.Equal(t, uint64(len("INFO: hello\n")+len("WARNING: careful\n")), SinkBytes()["*golog.syncBuffer"])
+
+	ResetCounters()
+	assert.Zero(t, BytesWritten(std))
+	assert.Empty(t, SinkBytes())
+}
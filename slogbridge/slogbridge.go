@@ -0,0 +1,115 @@
+//go:build go1.21
+
+// Package slogbridge provides a log/slog Handler backed by golog, so
+// code written against the standard structured logger respects
+// golog.SetLevel and shares the outputs, hooks and processors of the
+// golog package level loggers:
+//
+//	slog.SetDefault(slog.New(slogbridge.NewHandler()))
+package slogbridge
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/jayvib/golog"
+)
+
+// Handler is a slog.Handler writing the records with golog.Emit.
+type Handler struct {
+	fields golog.Fields
+	prefix string
+}
+
+// NewHandler returns a Handler without attributes.
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// Level returns the golog level of the slog level l. The levels
+// between two slog levels are rounded down.
+func Level(l slog.Level) golog.Level {
+	switch {
+	case l >= slog.LevelError:
+		return golog.ErrorLevel
+	case l >= slog.LevelWarn:
+		return golog.WarningLevel
+	case l >= slog.LevelInfo:
+		return golog.InfoLevel
+	}
+	return golog.DebugLevel
+}
+
+// Enabled implements the slog.Handler interface.
+func (h *Handler) Enabled(_ context.Context, l slog.Level) bool {
+	return golog.Enabled(Level(l))
+}
+
+// Handle implements the slog.Handler interface. The fields
+// attached to ctx with golog.ContextWithFields are included.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make(golog.Fields, len(h.fields)+r.NumAttrs())
+	for k, v := range golog.ContextFields(ctx) {
+		fields[k] = v
+	}
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(fields, h.prefix, a)
+		return true
+	})
+	e := &golog.Entry{
+		Time:    r.Time,
+		Level:   Level(r.Level),
+		Message: r.Message,
+		Fields:  fields,
+	}
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		e.Caller = &frame
+	}
+	golog.Emit(e)
+	return nil
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(golog.Fields, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	for _, a := range attrs {
+		addAttr(fields, h.prefix, a)
+	}
+	return &Handler{fields: fields, prefix: h.prefix}
+}
+
+// WithGroup implements the slog.Handler interface. The keys of
+// the attributes added afterwards are prefixed with the name
+// of the group and a dot.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &Handler{fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+// addAttr adds a to fields, flattening the groups into dotted keys.
+func addAttr(fields golog.Fields, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range v.Group() {
+			addAttr(fields, prefix, ga)
+		}
+		return
+	}
+	if a.Key == "" {
+		return
+	}
+	fields[prefix+a.Key] = v.Any()
+}
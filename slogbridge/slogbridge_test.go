@@ -0,0 +1,58 @@
+//go:build go1.21
+
+package slogbridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/jayvib/golog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	defer golog.SetOutput(os.Stdout)
+	defer golog.SetFormatter(nil)
+	defer golog.SetLevel(golog.InfoLevel)
+	var out bytes.Buffer
+	golog.SetOutput(&out)
+	golog.SetFormatter(&golog.JSONFormatter{})
+	golog.SetLevel(golog.InfoLevel)
+
+	logger := slog.New(NewHandler()).With("service", "api").WithGroup("req")
+	ctx := golog.ContextWithFields(context.Background(), golog.Fields{"request_id": "42"})
+
+	logger.DebugContext(ctx, "hidden")
+	assert.Empty(t, out.String())
+
+	logger.WarnContext(ctx, "slow request", "path", "/users", slog.Group("db", "rows", 3))
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	assert.Equal(t, "warning", entry["level"])
+	assert.Equal(t, "slow request", entry["msg"])
+	assert.Equal(t, "api", entry["service"])
+	assert.Equal(t, "42", entry["request_id"])
+	assert.Equal(t, "/users", entry["req.path"])
+	assert.Equal(t, float64(3), entry["req.db.rows"])
+	assert.Contains(t, entry["caller"], "slogbridge_test.go")
+
+	t.Run("respects golog.SetLevel", func(t *testing.T) {
+		out.Reset()
+		golog.SetLevel(golog.DebugLevel)
+		logger.Debug("visible")
+		assert.Contains(t, out.String(), "visible")
+	})
+}
+
+func TestLevel(t *testing.T) {
+	assert.Equal(t, golog.DebugLevel, Level(slog.LevelDebug))
+	assert.Equal(t, golog.InfoLevel, Level(slog.LevelInfo))
+	assert.Equal(t, golog.InfoLevel, Level(slog.LevelInfo+2))
+	assert.Equal(t, golog.WarningLevel, Level(slog.LevelWarn))
+	assert.Equal(t, golog.ErrorLevel, Level(slog.LevelError+4))
+}
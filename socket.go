@@ -0,0 +1,156 @@
+package golog
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrWriterClosed is returned when writing to a closed sink.
+var ErrWriterClosed = errors.New("golog: writer closed")
+
+// defaultRedialInterval is the minimum time between two
+// connection attempts of a SocketWriter.
+const defaultRedialInterval = time.Second
+
+// SocketWriter is a sink that writes every entry as a length-prefixed
+// frame to a local socket or named pipe, as expected by the Forwarder.
+// The connection is established lazily and re-established when a write
+// fails, so the collector on the other side can be restarted.
+type SocketWriter struct {
+	dial func() (io.WriteCloser, error)
+	// frame writes one entry to the connection.
+	// Defaults to a length-prefixed frame.
+	frame func(w io.Writer, p []byte) error
+
+	mu       sync.Mutex
+	conn     io.WriteCloser
+	zw       compressor // compresses the frames written to conn
+	lastDial time.Time
+	closed   bool
+	// RedialInterval is the minimum time between two connection attempts.
+	RedialInterval time.Duration
+	// Compression is the compression of the connections, announced
+	// to the Forwarder when connecting, which accepts compressed and
+	// plain connections alike. The writes are compressed as a stream,
+	// so a BufferedWriter or a CoalescingWriter in front of the
+	// SocketWriter batches the entries for a better compression.
+	// The syslog and session writers, with their own framing, are
+	// not compressed.
+	Compression Compression
+}
+
+// NewUnixSocketWriter returns a SocketWriter connected
+// to the unix domain socket at path.
+func NewUnixSocketWriter(path string) *SocketWriter {
+	return newSocketWriter(func() (io.WriteCloser, error) {
+		return net.Dial("unix", path)
+	})
+}
+
+// NewNamedPipeWriter returns a SocketWriter that writes to the named
+// pipe at path, e.g. `\\.\pipe\golog` on Windows or a FIFO created
+// with mkfifo elsewhere. On unix systems opening the FIFO blocks
+// until a reader opens it.
+func NewNamedPipeWriter(path string) *SocketWriter {
+	return newSocketWriter(func() (io.WriteCloser, error) {
+		return os.OpenFile(path, os.O_WRONLY, 0)
+	})
+}
+
+func newSocketWriter(dial func() (io.WriteCloser, error)) *SocketWriter {
+	return &SocketWriter{
+		dial:           dial,
+		RedialInterval: defaultRedialInterval,
+	}
+}
+
+// Write writes p as one frame. When the connection is broken it
+// reconnects and retries once.
+func (w *SocketWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+		if err := w.writeFrame(p); err != nil {
+			if err == ErrFrameTooLarge {
+				return 0, err
+			}
+			w.conn.Close()
+			w.conn, w.zw = nil, nil
+			// Allow an immediate reconnection after a broken connection.
+			w.lastDial = time.Time{}
+			continue
+		}
+		return len(p), nil
+	}
+	return 0, errors.New("golog: socket writer: connection lost")
+}
+
+func (w *SocketWriter) writeFrame(p []byte) error {
+	if w.frame != nil {
+		return w.frame(w.conn, p)
+	}
+	if w.zw == nil {
+		return writeFrame(w.conn, p)
+	}
+	if err := writeFrame(w.zw, p); err != nil {
+		return err
+	}
+	return w.zw.Flush()
+}
+
+// connect dials when there is no connection, at most
+// once per RedialInterval.
+func (w *SocketWriter) connect() error {
+	if w.conn != nil {
+		return nil
+	}
+	if !w.lastDial.IsZero() && time.Since(w.lastDial) < w.RedialInterval {
+		return errors.New("golog: socket writer: waiting to reconnect")
+	}
+	w.lastDial = time.Now()
+	conn, err := w.dial()
+	if err != nil {
+		return err
+	}
+	if w.frame == nil {
+		zw, err := newCompressor(conn, w.Compression)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+		w.zw = zw
+	}
+	w.conn = conn
+	return nil
+}
+
+// Close closes the connection. Writes after Close fail
+// with ErrWriterClosed.
+func (w *SocketWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	var err error
+	if w.zw != nil {
+		// Terminate the compressed stream.
+		err = w.zw.Close()
+	}
+	if cerr := w.conn.Close(); err == nil {
+		err = cerr
+	}
+	w.conn, w.zw = nil, nil
+	return err
+}
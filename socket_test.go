@@ -0,0 +1,81 @@
+package golog
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocketWriter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	addr := filepath.Join(dir, "golog.sock")
+
+	serve := func(sink *syncBuffer) *Forwarder {
+		ln, err := net.Listen("unix", addr)
+		require.NoError(t, err)
+		f := NewForwarder(sink)
+		go f.Serve(ln)
+		return f
+	}
+	lines := func(b *syncBuffer) int { return strings.Count(b.String(), "\n") }
+
+	t.Run("entries reach the forwarder", func(t *testing.T) {
+		sink := &syncBuffer{}
+		f := serve(sink)
+		defer f.Close()
+
+		w := NewUnixSocketWriter(addr)
+		defer w.Close()
+		l := newStdLogger(InfoLevel, w, 0)
+		SetLevel(InfoLevel)
+		l.Println("Hello World")
+		l.Println("Hello Again")
+
+		assert.Eventually(t, func() bool { return lines(sink) == 2 }, time.Second, 10*time.Millisecond)
+		assert.Equal(t, "INFO: Hello World\nINFO: Hello Again\n", sink.String())
+	})
+	t.Run("reconnects after the collector restarts", func(t *testing.T) {
+		sink := &syncBuffer{}
+		f := serve(sink)
+		w := NewUnixSocketWriter(addr)
+		defer w.Close()
+
+		_, err := w.Write([]byte("first\n"))
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool { return lines(sink) == 1 }, time.Second, 10*time.Millisecond)
+		f.Close()
+		os.Remove(addr)
+
+		f = serve(sink)
+		defer f.Close()
+		// The first write after the restart may be lost in the
+		// kernel buffer of the broken connection.
+		assert.Eventually(t, func() bool {
+			w.Write([]byte("second\n"))
+			return strings.Contains(sink.String(), "second")
+		}, time.Second, 10*time.Millisecond)
+	})
+	t.Run("closed writer", func(t *testing.T) {
+		w := NewUnixSocketWriter(addr)
+		require.NoError(t, w.Close())
+		_, err := w.Write([]byte("x"))
+		assert.Equal(t, ErrWriterClosed, err)
+	})
+	t.Run("dial failures are rate limited", func(t *testing.T) {
+		w := NewUnixSocketWriter(filepath.Join(dir, "missing.sock"))
+		w.RedialInterval = time.Hour
+		_, err := w.Write([]byte("x"))
+		assert.Error(t, err)
+		_, err = w.Write([]byte("x"))
+		assert.Contains(t, err.Error(), "waiting to reconnect")
+	})
+}
@@ -0,0 +1,130 @@
+package golog
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StacktraceKey is the field key holding the stack trace of the
+// entries captured with SetStacktraceLevel and AddStacktrace.
+const StacktraceKey = "stacktrace"
+
+// Stacktrace is the stack of the goroutine logging an entry, one
+// "function\n\tfile:line" element per frame from the call site up,
+// as printed by a panic. The TextFormatter renders the Stacktrace
+// fields as indented blocks below the entry, and the JSONFormatter
+// as string fields.
+type Stacktrace string
+
+// SetStacktraceLevel makes the entries of lvl and above capture the
+// stack of the goroutine logging them, e.g. ErrorLevel to attach a
+// stack trace to the Error, Fatal and Panic entries. The entries of
+// Fatal capture it whatever their level. DisabledLevel, the default,
+// disables the stack traces.
+func SetStacktraceLevel(lvl Level) {
+	updateState(func(s *globalState) {
+		s.stackLevel = lvl
+	})
+}
+
+// AddStacktrace returns a logger like l whose entries of lvl and
+// above capture the stack of the goroutine logging them, overriding
+// the level of SetStacktraceLevel.
+func (l *stdLogger) AddStacktrace(lvl Level) FieldLogger {
+	c := l.clone()
+	c.stackLevel = &lvl
+	return c
+}
+
+// stackThreshold returns the level from which the
+// entries of l capture a stack trace.
+func (l *stdLogger) stackThreshold(st *globalState) Level {
+	if l.stackLevel != nil {
+		return *l.stackLevel
+	}
+	return st.stackLevel
+}
+
+// captureStack reports whether the entries of lvl capture a stack
+// trace with the threshold, always true for the fatal entries
+// unless stack traces are disabled.
+func captureStack(threshold, lvl Level, fatal bool) bool {
+	return threshold < DisabledLevel && (fatal || lvl >= threshold)
+}
+
+// stacktrace returns the stack from the function calldepth levels
+// above the caller of stacktrace, with the same meaning as the
+// argument of runtime.Caller.
+func stacktrace(calldepth int) Stacktrace {
+	var pcs [maxPanicFrames]uintptr
+	n := runtime.Callers(calldepth+2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	var b []byte
+	for {
+		frame, more := frames.Next()
+		if frame.Function == "runtime.goexit" {
+			break
+		}
+		if len(b) > 0 {
+			b = append(b, '\n')
+		}
+		b = append(b, frame.Function...)
+		b = append(b, "\n\t"...)
+		b = append(b, frame.File...)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(frame.Line), 10)
+		if !more {
+			break
+		}
+	}
+	return Stacktrace(b)
+}
+
+// addCallField adds the field key to the fields of the call of e.
+// The fields of e are copied, as they may be shared with a logger.
+func addCallField(e *Entry, key string, v interface{}) {
+	fields := make(Fields, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		fields[k] = v
+	}
+	fields[key] = v
+	e.Fields = fields
+}
+
+// splitStacktraces returns fields without the Stacktrace values,
+// and these values ordered by key.
+func splitStacktraces(fields Fields) (Fields, []Stacktrace) {
+	n := 0
+	for _, v := range fields {
+		if _, ok := v.(Stacktrace); ok {
+			n++
+		}
+	}
+	if n == 0 {
+		return fields, nil
+	}
+	rest := make(Fields, len(fields)-n)
+	stacks := make([]Stacktrace, 0, n)
+	for _, k := range fields.sortedKeys() {
+		if stack, ok := fields[k].(Stacktrace); ok {
+			stacks = append(stacks, stack)
+		} else {
+			rest[k] = fields[k]
+		}
+	}
+	return rest, stacks
+}
+
+// appendStacktraces appends the stacks indented by a tab,
+// one line per line, after the line of an entry.
+func appendStacktraces(b []byte, stacks []Stacktrace) []byte {
+	for _, stack := range stacks {
+		for _, line := range strings.Split(string(stack), "\n") {
+			b = append(b, '\t')
+			b = append(b, line...)
+			b = append(b, '\n')
+		}
+	}
+	return b
+}
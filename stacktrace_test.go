@@ -0,0 +1,89 @@
+package golog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetStacktraceLevel(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	defer SetStacktraceLevel(DisabledLevel)
+	SetLevel(InfoLevel)
+
+	var out syncBuffer
+	l := newStdLogger(InfoLevel, &out, 0)
+
+	t.Run("disabled by default", func(t *testing.T) {
+		out.Reset()
+		l.Error("failed")
+		assert.Equal(t, "ERROR: failed\n", out.String())
+	})
+
+	t.Run("rendered as an indented block", func(t *testing.T) {
+		out.Reset()
+		SetStacktraceLevel(ErrorLevel)
+		l.WithFields(Fields{"code": 500}).Error("failed")
+		l.Warn("slow")
+		lines := strings.Split(out.String(), "\n")
+		require.True(t, len(lines) > 3)
+		assert.Equal(t, "ERROR: failed code=500", lines[0])
+		assert.Equal(t, "\tgithub.com/jayvib/golog.TestSetStacktraceLevel.func2", lines[1])
+		assert.Regexp(t, `^\t\t.*stacktrace_test\.go:\d+$`, lines[2])
+		assert.Equal(t, "WARNING: slow", lines[len(lines)-2])
+	})
+
+	t.Run("fatal entries", func(t *testing.T) {
+		out.Reset()
+		l.SetExitFunc(func(int) {})
+		defer l.SetExitFunc(nil)
+		l.Fatal("boom")
+		assert.Contains(t, out.String(), "INFO: boom\n\tgithub.com/jayvib/golog.TestSetStacktraceLevel.func3\n")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out.Reset()
+		l.SetFormatter(&JSONFormatter{})
+		defer l.SetFormatter(&TextFormatter{})
+		l.Error("failed")
+		assert.Contains(t, out.String(), `"stacktrace":"github.com/jayvib/golog.TestSetStacktraceLevel.func4\n\t`)
+	})
+}
+
+func TestLogger_AddStacktrace(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+
+	t.Run("golog", func(t *testing.T) {
+		var out syncBuffer
+		l := newStdLogger(InfoLevel, &out, 0).AddStacktrace(WarningLevel)
+		l.Info("hello")
+		l.Warn("slow")
+		assert.Contains(t, out.String(), "INFO: hello\nWARNING: slow\n\tgithub.com/jayvib/golog.TestLogger_AddStacktrace.func1\n")
+
+		out.Reset()
+		SetStacktraceLevel(DebugLevel)
+		defer SetStacktraceLevel(DisabledLevel)
+		l.WithFields(Fields{"k": "v"}).AddStacktrace(DisabledLevel).Error("failed")
+		assert.Equal(t, "ERROR: failed k=v\n", out.String())
+	})
+
+	t.Run("logrus", func(t *testing.T) {
+		var out syncBuffer
+		l := NewLogrusLogger(InfoLevel)
+		l.SetOutput(&out)
+		l.SetFormatter(&TextFormatter{})
+		l.AddStacktrace(ErrorLevel).Errorf("failed %d", 1)
+		l.Error("plain")
+		assert.Contains(t, out.String(), "ERROR: failed 1\n\tgithub.com/jayvib/golog.TestLogger_AddStacktrace.func2\n")
+		assert.True(t, strings.HasSuffix(out.String(), "ERROR: plain\n"))
+
+		out.Reset()
+		l.AddStacktrace(ErrorLevel).Print("hello")
+		assert.Equal(t, "INFO: hello\n", out.String())
+		l.AddStacktrace(InfoLevel).Print("hello")
+		assert.Contains(t, out.String(), "INFO: hello\n\tgithub.com/jayvib/golog.TestLogger_AddStacktrace.func2\n")
+	})
+}
@@ -0,0 +1,100 @@
+package golog
+
+import (
+	"log"
+	"runtime"
+	"strings"
+)
+
+// NewStdlibAdapter returns a standard library logger whose writes are
+// routed through the package level logger of lvl, so libraries only
+// accepting a *log.Logger respect the golog level, pipeline and
+// outputs:
+//
+//	srv := &http.Server{ErrorLog: golog.NewStdlibAdapter(golog.ErrorLevel)}
+//
+// The returned logger has no prefix and no flags; the time and the
+// call site are added by the golog formatter.
+func NewStdlibAdapter(lvl Level) *log.Logger {
+	return log.New(stdlibWriter{level: lvl}, "", 0)
+}
+
+// CaptureStdlog redirects the output of the standard library global
+// logger through golog at lvl, so the third-party packages calling
+// log.Printf land in the golog stream. When parsePrefix is true, a
+// leading level prefix like "[ERROR]" or "warn:" selects the level of
+// the message and is removed. The returned function restores the
+// previous output, flags and prefix of the global logger.
+func CaptureStdlog(lvl Level, parsePrefix bool) (restore func()) {
+	w, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+	log.SetOutput(stdlibWriter{level: lvl, parsePrefix: parsePrefix})
+	log.SetFlags(0)
+	log.SetPrefix("")
+	return func() {
+		log.SetOutput(w)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}
+}
+
+// stdlibWriter emits every write of a log.Logger as an entry.
+type stdlibWriter struct {
+	level       Level
+	parsePrefix bool
+}
+
+func (w stdlibWriter) Write(p []byte) (int, error) {
+	lvl, msg := w.level, string(p)
+	if w.parsePrefix {
+		lvl, msg = parseLevelPrefix(msg, lvl)
+	}
+	Emit(&Entry{
+		Level:   lvl,
+		Message: msg,
+		Caller:  stdlibCaller(),
+	})
+	return len(p), nil
+}
+
+// parseLevelPrefix returns the level named by the "[LEVEL]" or
+// "LEVEL:" prefix of msg and msg without the prefix. It returns
+// lvl and msg when msg has no such prefix.
+func parseLevelPrefix(msg string, lvl Level) (Level, string) {
+	var name, rest string
+	switch {
+	case strings.HasPrefix(msg, "["):
+		i := strings.IndexByte(msg, ']')
+		if i < 0 {
+			return lvl, msg
+		}
+		name, rest = msg[1:i], msg[i+1:]
+	default:
+		i := strings.IndexByte(msg, ':')
+		if i < 0 || strings.ContainsAny(msg[:i], " \t") {
+			return lvl, msg
+		}
+		name, rest = msg[:i], msg[i+1:]
+	}
+	parsed, err := ParseLevel(name)
+	if err != nil || parsed == DisabledLevel {
+		return lvl, msg
+	}
+	return parsed, strings.TrimLeft(rest, " ")
+}
+
+// stdlibCaller returns the first frame above the log
+// package calling the Write method of stdlibWriter.
+func stdlibCaller() *runtime.Frame {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "log.") {
+			return &frame
+		}
+		if !more {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package golog
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStdlibAdapter(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(WarningLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+	ErrorLogger.SetFormatter(&TextFormatter{Flags: 0})
+	defer ErrorLogger.SetFormatter(&TextFormatter{Flags: defaultFlags(ErrorLevel)})
+
+	NewStdlibAdapter(InfoLevel).Print("filtered")
+	assert.Empty(t, out.String())
+
+	NewStdlibAdapter(ErrorLevel).Printf("http: TLS handshake error from %s", "10.0.0.1")
+	assert.Equal(t, "ERROR: http: TLS handshake error from 10.0.0.1\n", out.String())
+
+	t.Run("reports the caller of the log package", func(t *testing.T) {
+		out.Reset()
+		ErrorLogger.SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+		NewStdlibAdapter(ErrorLevel).Println("failed")
+		assert.Contains(t, out.String(), "stdlib_test.go:")
+	})
+}
+
+func TestCaptureStdlog(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+	SetFormatter(&TextFormatter{Flags: 0})
+	defer func() {
+		for _, l := range packageLoggers() {
+			l.SetFormatter(&TextFormatter{Flags: defaultFlags(l.level)})
+		}
+	}()
+
+	var previous bytes.Buffer
+	log.SetOutput(&previous)
+	log.SetFlags(log.Lshortfile)
+	defer log.SetOutput(os.Stderr)
+	defer log.SetFlags(log.LstdFlags)
+
+	restore := CaptureStdlog(InfoLevel, true)
+	log.Print("listening on :8080")
+	log.Print("[ERROR] connection refused")
+	log.Print("warn: retrying")
+	log.Print("[DEBUG] hidden")
+	log.Print("[CUSTOM] unknown prefix")
+	restore()
+	log.Print("restored")
+
+	assert.Equal(t, "INFO: listening on :8080\n"+
+		"ERROR: connection refused\n"+
+		"WARNING: retrying\n"+
+		"INFO: [CUSTOM] unknown prefix\n", out.String())
+	assert.Contains(t, previous.String(), "stdlib_test.go")
+	assert.Contains(t, previous.String(), "restored")
+}
+
+func TestParseLevelPrefix(t *testing.T) {
+	cases := []struct {
+		in   string
+		lvl  Level
+		want string
+	}{
+		{"[ERROR] failed", ErrorLevel, "failed"},
+		{"[Warn]failed", WarningLevel, "failed"},
+		{"trace: entering", TraceLevel, "entering"},
+		{"no prefix", InfoLevel, "no prefix"},
+		{"time: 10s elapsed", InfoLevel, "time: 10s elapsed"},
+		{"go away: now", InfoLevel, "go away: now"},
+		{"[disabled] x", InfoLevel, "[disabled] x"},
+		{"[unterminated", InfoLevel, "[unterminated"},
+	}
+	for _, c := range cases {
+		lvl, msg := parseLevelPrefix(c.in, InfoLevel)
+		assert.Equal(t, c.lvl, lvl, c.in)
+		assert.Equal(t, c.want, msg, c.in)
+	}
+}
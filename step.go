@@ -0,0 +1,154 @@
+package golog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// spinnerInterval is the time between two frames of a spinner.
+const spinnerInterval = 100 * time.Millisecond
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// isTerminal reports whether w is a terminal.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Step reports the progress of a step of a command line tool. On a
+// terminal, the user output shows a spinner followed by the title
+// and the last status. Elsewhere, e.g. in CI, the step is logged as
+// timestamped lines with InfoLogger and ErrorLogger instead, so the
+// tools use the same code for both. The spinner is not shown when
+// InfoLogger is disabled, e.g. in quiet mode, and the failure of
+// the step is then only logged:
+//
+//	s := golog.StartStep("Pulling image")
+//	s.Update("layer 1/3")
+//	if err := pull(); err != nil {
+//		s.Fail(err)
+//		return err
+//	}
+//	s.Done()
+type Step struct {
+	title string
+	start time.Time
+	w     io.Writer // nil when the step is logged
+
+	mu       sync.Mutex
+	status   string
+	finished bool
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+// StartStep starts the step title.
+func StartStep(title string) *Step {
+	userMu.Lock()
+	w := userOutput
+	userMu.Unlock()
+
+	s := &Step{title: title, start: time.Now()}
+	if !isTerminal(w) || !InfoLogger.isPrint() {
+		if InfoLogger.isPrint() {
+			InfoLogger.Output(stdCallDepth, title+"...")
+		}
+		return s
+	}
+	s.w = w
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+	go s.spin()
+	return s
+}
+
+func (s *Step) spin() {
+	defer close(s.stopped)
+	t := time.NewTicker(spinnerInterval)
+	defer t.Stop()
+	for i := 0; ; i++ {
+		s.mu.Lock()
+		s.draw(spinnerFrames[i%len(spinnerFrames)])
+		s.mu.Unlock()
+		select {
+		case <-t.C:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// draw redraws the line of the step behind symbol.
+func (s *Step) draw(symbol string) {
+	line := symbol + " " + s.title
+	if s.status != "" {
+		line += ": " + s.status
+	}
+	fmt.Fprintf(s.w, "\r\x1b[K%s", line)
+}
+
+// Update sets the status shown after the title.
+func (s *Step) Update(status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.finished {
+		return
+	}
+	s.status = status
+	if s.w == nil && InfoLogger.isPrint() {
+		InfoLogger.Output(stdCallDepth, s.title+": "+status)
+	}
+}
+
+// Done finishes the step successfully.
+func (s *Step) Done() {
+	s.finish(nil)
+}
+
+// Fail finishes the step with err.
+func (s *Step) Fail(err error) {
+	s.finish(err)
+}
+
+func (s *Step) finish(err error) {
+	s.mu.Lock()
+	if s.finished {
+		s.mu.Unlock()
+		return
+	}
+	s.finished = true
+	s.mu.Unlock()
+	elapsed := time.Since(s.start).Round(time.Millisecond)
+
+	if s.w == nil {
+		// The call site is the caller of Done or Fail.
+		if err == nil && InfoLogger.isPrint() {
+			InfoLogger.Output(stdCallDepth+1, fmt.Sprintf("%s done in %v", s.title, elapsed))
+		}
+		if err != nil && ErrorLogger.isPrint() {
+			ErrorLogger.Output(stdCallDepth+1, fmt.Sprintf("%s failed after %v: %v", s.title, elapsed, err))
+		}
+		return
+	}
+
+	close(s.stop)
+	<-s.stopped
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.status = err.Error()
+		s.draw("✗")
+	} else {
+		s.status = elapsed.String()
+		s.draw("✓")
+	}
+	fmt.Fprintln(s.w)
+}
@@ -0,0 +1,88 @@
+package golog
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStep(t *testing.T) {
+	defer SetUserOutput(os.Stderr)
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+
+	t.Run("logs when the output is not a terminal", func(t *testing.T) {
+		out := &syncBuffer{}
+		SetOutput(out)
+		SetUserOutput(out)
+		defer InfoLogger.SetFormatter(&TextFormatter{Flags: defaultFlags(InfoLevel)})
+		InfoLogger.SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+		ErrorLogger.SetFormatter(&TextFormatter{Flags: log.Lshortfile})
+		defer ErrorLogger.SetFormatter(&TextFormatter{Flags: defaultFlags(ErrorLevel)})
+
+		s := StartStep("Pulling image")
+		s.Update("layer 1/3")
+		s.Fail(errors.New("connection reset"))
+		s.Done()
+
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		if assert.Len(t, lines, 3) {
+			assert.Regexp(t, `^INFO: step_test.go:\d+: Pulling image\.\.\.$`, lines[0])
+			assert.Regexp(t, `^INFO: step_test.go:\d+: Pulling image: layer 1/3$`, lines[1])
+			assert.Regexp(t, `^ERROR: step_test.go:\d+: Pulling image failed after \S+: connection reset$`, lines[2])
+		}
+	})
+	t.Run("spins on a terminal", func(t *testing.T) {
+		defer func(fn func(io.Writer) bool) { isTerminal = fn }(isTerminal)
+		isTerminal = func(io.Writer) bool { return true }
+		logs, term := &syncBuffer{}, &syncBuffer{}
+		SetOutput(logs)
+		SetUserOutput(term)
+
+		s := StartStep("Building")
+		s.Update("compiling")
+		assert.Eventually(t, func() bool {
+			return strings.Contains(term.String(), "Building: compiling")
+		}, time.Second, 10*time.Millisecond)
+		s.Done()
+
+		assert.Empty(t, logs.String())
+		assert.Contains(t, term.String(), "\r\x1b[K⠋ Building")
+		assert.Regexp(t, "\r\x1b\\[K✓ Building: \\S+\n$", term.String())
+	})
+	t.Run("no spinner in quiet mode", func(t *testing.T) {
+		defer func(fn func(io.Writer) bool) { isTerminal = fn }(isTerminal)
+		isTerminal = func(io.Writer) bool { return true }
+		defer SetLevel(InfoLevel)
+		SetLevel(ErrorLevel)
+		logs, term := &syncBuffer{}, &syncBuffer{}
+		SetOutput(logs)
+		SetUserOutput(term)
+
+		s := StartStep("Building")
+		s.Update("compiling")
+		s.Fail(errors.New("syntax error"))
+
+		assert.Empty(t, term.String())
+		assert.Contains(t, logs.String(), "Building failed after")
+		assert.NotContains(t, logs.String(), "compiling")
+	})
+}
+
+func TestIsTerminal(t *testing.T) {
+	assert.False(t, isTerminal(&syncBuffer{}))
+	f, err := ioutil.TempFile("", "golog")
+	if assert.NoError(t, err) {
+		defer os.Remove(f.Name())
+		defer f.Close()
+		assert.False(t, isTerminal(f))
+	}
+}
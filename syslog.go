@@ -0,0 +1,248 @@
+package golog
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SyslogFacility is the facility of the syslog messages.
+type SyslogFacility int
+
+// The syslog facilities of RFC 5424 used by applications.
+const (
+	FacilityUser   SyslogFacility = 1
+	FacilityDaemon SyslogFacility = 3
+	FacilityLocal0 SyslogFacility = 16
+	FacilityLocal1 SyslogFacility = 17
+	FacilityLocal2 SyslogFacility = 18
+	FacilityLocal3 SyslogFacility = 19
+	FacilityLocal4 SyslogFacility = 20
+	FacilityLocal5 SyslogFacility = 21
+	FacilityLocal6 SyslogFacility = 22
+	FacilityLocal7 SyslogFacility = 23
+)
+
+// syslogSDID is the identifier of the structured data element
+// holding the fields, under the example private enterprise
+// number of RFC 5612.
+const syslogSDID = "fields@32473"
+
+// localSyslogPaths are the usual sockets of the local syslog daemon.
+var localSyslogPaths = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogSeverity returns the syslog severity of lvl: debug (7) for
+// DebugLevel and TraceLevel, informational (6), warning (4) and
+// error (3).
+func SyslogSeverity(lvl Level) int {
+	switch lvl {
+	case InfoLevel:
+		return 6
+	case WarningLevel:
+		return 4
+	case ErrorLevel:
+		return 3
+	}
+	return 7
+}
+
+// SyslogFormatter formats an entry as an RFC 5424 syslog message.
+// The fields are written as the parameters of a structured data
+// element.
+type SyslogFormatter struct {
+	// Facility is the facility of the messages.
+	// Defaults to FacilityUser.
+	Facility SyslogFacility
+	// AppName identifies the application.
+	// Defaults to the name of the executable.
+	AppName string
+	// Hostname identifies the machine.
+	// Defaults to os.Hostname.
+	Hostname string
+}
+
+// Format implements the Formatter interface.
+func (f *SyslogFormatter) Format(e *Entry) ([]byte, error) {
+	return f.appendFormat(make([]byte, 0, 128+len(e.Message)), e)
+}
+
+// appendFormat appends the formatted entry e to b.
+func (f *SyslogFormatter) appendFormat(b []byte, e *Entry) ([]byte, error) {
+	facility := f.Facility
+	if facility == 0 {
+		facility = FacilityUser
+	}
+	b = append(b, '<')
+	b = strconv.AppendInt(b, int64(int(facility)*8+SyslogSeverity(e.Level)), 10)
+	b = append(b, ">1 "...)
+	b = appendTime(b, e.Time, layoutRFC3339Micro)
+	b = append(b, ' ')
+	b = appendSyslogName(b, f.Hostname, syslogHostname, 255)
+	b = append(b, ' ')
+	b = appendSyslogName(b, f.AppName, syslogAppName, 48)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(os.Getpid()), 10)
+	b = append(b, " - "...)
+	if len(e.Fields) == 0 {
+		b = append(b, '-')
+	} else {
+		b = append(b, "["+syslogSDID...)
+		for _, k := range e.Fields.sortedKeys() {
+			b = append(b, ' ')
+			b = appendSyslogName(b, k, nil, 32)
+			b = append(b, `="`...)
+			b = appendSyslogParam(b, fmt.Sprint(e.Fields[k]))
+			b = append(b, '"')
+		}
+		b = append(b, ']')
+	}
+	if e.Message != "" {
+		b = append(b, ' ')
+		b = append(b, e.Message...)
+	}
+	return append(b, '\n'), nil
+}
+
+func syslogHostname() string {
+	h, _ := os.Hostname()
+	return h
+}
+
+func syslogAppName() string {
+	if len(os.Args) == 0 {
+		return ""
+	}
+	name := os.Args[0]
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// appendSyslogName appends s, or the result of def when s is empty,
+// as a header field or parameter name of at most max printable
+// ASCII characters. The invalid characters are replaced by '_'
+// and an empty name by the nil value '-'.
+func appendSyslogName(b []byte, s string, def func() string, max int) []byte {
+	if s == "" && def != nil {
+		s = def()
+	}
+	if s == "" {
+		return append(b, '-')
+	}
+	if len(s) > max {
+		s = s[:max]
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c > '~' || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+// appendSyslogParam appends the parameter value s,
+// escaping '"', '\' and ']'.
+func appendSyslogParam(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\\', ']':
+			b = append(b, '\\')
+		}
+		b = append(b, s[i])
+	}
+	return b
+}
+
+// SyslogConfig configures the destination of the syslog messages.
+type SyslogConfig struct {
+	// Network is "udp", "tcp" or "unix". When empty, the messages
+	// are sent to the local syslog daemon.
+	Network string
+	// Addr is the address of the syslog server.
+	Addr string
+	// TLSConfig enables TLS on the "tcp" network, see TLSConfig.Build.
+	TLSConfig *tls.Config
+}
+
+// NewSyslogWriter returns a SocketWriter sending every message to
+// the syslog server of c: one datagram per message on the datagram
+// networks, and octet-counted frames, as specified by RFC 6587, on
+// the stream networks. The messages should be formatted with the
+// SyslogFormatter.
+func NewSyslogWriter(c SyslogConfig) *SocketWriter {
+	var stream bool
+	var dial func() (io.WriteCloser, error)
+	switch {
+	case c.Network == "":
+		dial = dialLocalSyslog
+	case c.TLSConfig != nil:
+		stream = true
+		dial = func() (io.WriteCloser, error) {
+			return tls.Dial(c.Network, c.Addr, c.TLSConfig)
+		}
+	default:
+		stream = c.Network == "tcp" || c.Network == "tcp4" || c.Network == "tcp6" || c.Network == "unix"
+		dial = func() (io.WriteCloser, error) {
+			return net.Dial(c.Network, c.Addr)
+		}
+	}
+	w := newSocketWriter(dial)
+	w.frame = writeSyslogDatagram
+	if stream {
+		w.frame = writeSyslogOctetCounted
+	}
+	return w
+}
+
+func dialLocalSyslog() (io.WriteCloser, error) {
+	for _, path := range localSyslogPaths {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				return conn, nil
+			}
+		}
+	}
+	return nil, errors.New("golog: syslog: no local syslog daemon")
+}
+
+func writeSyslogDatagram(w io.Writer, p []byte) error {
+	_, err := w.Write(trimNewline(p))
+	return err
+}
+
+func writeSyslogOctetCounted(w io.Writer, p []byte) error {
+	p = trimNewline(p)
+	b := make([]byte, 0, len(p)+8)
+	b = strconv.AppendInt(b, int64(len(p)), 10)
+	b = append(b, ' ')
+	b = append(b, p...)
+	_, err := w.Write(b)
+	return err
+}
+
+func trimNewline(p []byte) []byte {
+	if n := len(p); n > 0 && p[n-1] == '\n' {
+		return p[:n-1]
+	}
+	return p
+}
+
+// NewSyslogLogger returns a logger of level sending its entries,
+// formatted by f, to the syslog server of c. A nil f uses a
+// SyslogFormatter with the default facility and names.
+func NewSyslogLogger(level Level, c SyslogConfig, f *SyslogFormatter) FieldLogger {
+	l := newStdLogger(level, NewSyslogWriter(c), 0)
+	if f == nil {
+		f = &SyslogFormatter{}
+	}
+	l.SetFormatter(f)
+	return l
+}
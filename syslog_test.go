@@ -0,0 +1,113 @@
+package golog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyslogSeverity(t *testing.T) {
+	assert.Equal(t, 7, SyslogSeverity(DebugLevel))
+	assert.Equal(t, 7, SyslogSeverity(TraceLevel))
+	assert.Equal(t, 6, SyslogSeverity(InfoLevel))
+	assert.Equal(t, 4, SyslogSeverity(WarningLevel))
+	assert.Equal(t, 3, SyslogSeverity(ErrorLevel))
+}
+
+func TestSyslogFormatter(t *testing.T) {
+	ts := time.Date(2020, 5, 17, 10, 30, 0, 123456000, time.UTC)
+	pid := os.Getpid()
+
+	t.Run("header and message", func(t *testing.T) {
+		f := &SyslogFormatter{Facility: FacilityLocal0, AppName: "app", Hostname: "host"}
+		b, err := f.Format(&Entry{Time: ts, Level: WarningLevel, Message: "disk almost full"})
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("<132>1 2020-05-17T10:30:00.123456Z host app %d - - disk almost full\n", pid), string(b))
+	})
+	t.Run("fields are structured data", func(t *testing.T) {
+		f := &SyslogFormatter{AppName: "app", Hostname: "host"}
+		b, err := f.Format(&Entry{Time: ts, Level: ErrorLevel, Message: "failed", Fields: Fields{
+			"path":  `C:\tmp "x"]`,
+			"a key": 1,
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf(`<11>1 2020-05-17T10:30:00.123456Z host app %d - [fields@32473 a_key="1" path="C:\\tmp \"x\"\]"] failed`+"\n", pid), string(b))
+	})
+	t.Run("invalid names are sanitized", func(t *testing.T) {
+		f := &SyslogFormatter{AppName: "my app", Hostname: "host"}
+		b, err := f.Format(&Entry{Time: ts, Level: InfoLevel, Message: "hi"})
+		require.NoError(t, err)
+		assert.Contains(t, string(b), " host my_app ")
+	})
+}
+
+func TestSyslogWriter(t *testing.T) {
+	f := &SyslogFormatter{AppName: "app", Hostname: "host"}
+
+	t.Run("udp sends one datagram per message", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+
+		SetLevel(InfoLevel)
+		l := NewSyslogLogger(InfoLevel, SyslogConfig{Network: "udp", Addr: conn.LocalAddr().String()}, f)
+		l.Print("hello")
+
+		buf := make([]byte, 1024)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+		assert.Regexp(t, `^<14>1 \S+ host app \d+ - - hello$`, string(buf[:n]))
+	})
+	t.Run("tcp frames the messages with their length", func(t *testing.T) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer ln.Close()
+		received := make(chan []string, 1)
+		go func() {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			r := bufio.NewReader(conn)
+			var msgs []string
+			for len(msgs) < 2 {
+				var n int
+				if _, err := fmt.Fscanf(r, "%d ", &n); err != nil {
+					break
+				}
+				p := make([]byte, n)
+				if _, err := io.ReadFull(r, p); err != nil {
+					break
+				}
+				msgs = append(msgs, string(p))
+			}
+			received <- msgs
+		}()
+
+		SetLevel(InfoLevel)
+		w := NewSyslogWriter(SyslogConfig{Network: "tcp", Addr: ln.Addr().String()})
+		defer w.Close()
+		l := newStdLogger(ErrorLevel, w, 0)
+		l.SetFormatter(f)
+		l.Print("first")
+		l.Print("second")
+
+		select {
+		case msgs := <-received:
+			require.Len(t, msgs, 2)
+			assert.Regexp(t, `^<11>1 \S+ host app \d+ - - first$`, msgs[0])
+			assert.Regexp(t, `^<11>1 \S+ host app \d+ - - second$`, msgs[1])
+		case <-time.After(time.Second):
+			t.Fatal("messages not received")
+		}
+	})
+}
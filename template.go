@@ -0,0 +1,138 @@
+package golog
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// TemplateFormatter formats an entry with a Go template, so entries
+// can be rendered the way a runbook expects. The template is executed
+// with the *Entry, and can use the following functions besides the
+// ones given to NewTemplateFormatter:
+//
+//	level     the level token of an entry, e.g. INFO or WARN
+//	colorize  colors a value by a level or a color name: red,
+//	          yellow, cyan or dim
+//	pad       pads a value with spaces to a width, on the right or,
+//	          for a negative width, on the left
+//	truncate  truncates a value to a number of characters
+//	date      formats a time with a layout
+//	fields    the fields in text form
+//	caller    the file base name and line of a caller
+//
+// For example:
+//
+//	{{date "15:04:05" .Time}} {{colorize .Level (level .Level | pad 5)}} {{.Message | truncate 60}} {{fields .Fields}}
+//
+// A newline is appended to the output when missing.
+type TemplateFormatter struct {
+	// DisableColors makes colorize return the values uncolored.
+	DisableColors bool
+
+	tmpl *template.Template
+}
+
+// NewTemplateFormatter returns a TemplateFormatter executing text.
+// The functions of funcs are added to the built-in ones, and replace
+// them when they have the same name.
+func NewTemplateFormatter(text string, funcs template.FuncMap) (*TemplateFormatter, error) {
+	f := &TemplateFormatter{}
+	tmpl, err := template.New("entry").
+		Funcs(f.funcs()).
+		Funcs(funcs).
+		Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("golog: template formatter: %v", err)
+	}
+	f.tmpl = tmpl
+	return f, nil
+}
+
+// Format implements the Formatter interface.
+func (f *TemplateFormatter) Format(e *Entry) ([]byte, error) {
+	var b bytes.Buffer
+	if err := f.tmpl.Execute(&b, e); err != nil {
+		return nil, err
+	}
+	if b.Len() == 0 || b.Bytes()[b.Len()-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
+
+// ansiColors are the colors known by colorize.
+var ansiColors = map[string]string{
+	"red":    ansiRed,
+	"yellow": ansiYellow,
+	"cyan":   ansiCyan,
+	"dim":    ansiDim,
+}
+
+// funcs returns the built-in functions of the templates of f.
+func (f *TemplateFormatter) funcs() template.FuncMap {
+	return template.FuncMap{
+		"level": func(lvl Level) string {
+			token, _ := consoleLevel(lvl)
+			return token
+		},
+		"colorize": func(by interface{}, v interface{}) (string, error) {
+			var color string
+			switch by := by.(type) {
+			case Level:
+				_, color = consoleLevel(by)
+			case string:
+				c, ok := ansiColors[by]
+				if !ok {
+					return "", fmt.Errorf("unknown color %q", by)
+				}
+				color = c
+			default:
+				return "", fmt.Errorf("cannot colorize by %T", by)
+			}
+			s := fmt.Sprint(v)
+			if f.DisableColors || color == "" {
+				return s, nil
+			}
+			return color + s + ansiReset, nil
+		},
+		"pad": func(width int, v interface{}) string {
+			s := fmt.Sprint(v)
+			left := width < 0
+			if left {
+				width = -width
+			}
+			n := width - utf8.RuneCountInString(s)
+			if n <= 0 {
+				return s
+			}
+			if left {
+				return strings.Repeat(" ", n) + s
+			}
+			return s + strings.Repeat(" ", n)
+		},
+		"truncate": func(n int, v interface{}) string {
+			s := fmt.Sprint(v)
+			if n < 0 || utf8.RuneCountInString(s) <= n {
+				return s
+			}
+			return string([]rune(s)[:n])
+		},
+		"date": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+		"fields": func(fields Fields) string {
+			return fields.String()
+		},
+		"caller": func(frame *runtime.Frame) string {
+			if frame == nil {
+				return "???:0"
+			}
+			return shortCaller(frame)
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package golog
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateFormatter(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 15, 4, 5, 0, time.UTC)
+	e := &Entry{Time: ts, Level: WarningLevel, Message: "disk almost full", Fields: Fields{"used": 93}}
+
+	t.Run("built-in functions", func(t *testing.T) {
+		f, err := NewTemplateFormatter(`{{date "15:04:05" .Time}} {{colorize .Level (level .Level | pad 5)}} {{.Message | truncate 9}} {{fields .Fields}}`, nil)
+		require.NoError(t, err)
+		b, err := f.Format(e)
+		require.NoError(t, err)
+		assert.Equal(t, "15:04:05 \x1b[33mWARN \x1b[0m disk almo used=93\n", string(b))
+
+		f.DisableColors = true
+		b, _ = f.Format(e)
+		assert.Equal(t, "15:04:05 WARN  disk almo used=93\n", string(b))
+	})
+
+	t.Run("padding on the left and named colors", func(t *testing.T) {
+		f, err := NewTemplateFormatter(`[{{pad -6 .Fields.used}}] {{colorize "red" .Message}}`+"\n", nil)
+		require.NoError(t, err)
+		b, _ := f.Format(e)
+		assert.Equal(t, "[    93] \x1b[31mdisk almost full\x1b[0m\n", string(b))
+	})
+
+	t.Run("caller", func(t *testing.T) {
+		f, _ := NewTemplateFormatter(`{{caller .Caller}}`, nil)
+		b, _ := f.Format(e)
+		assert.Equal(t, "???:0\n", string(b))
+	})
+
+	t.Run("user functions", func(t *testing.T) {
+		f, err := NewTemplateFormatter(`{{upper .Message}} {{level .Level}}`, template.FuncMap{
+			"upper": strings.ToUpper,
+			"level": func(lvl Level) string { return lvl.name() },
+		})
+		require.NoError(t, err)
+		b, _ := f.Format(e)
+		assert.Equal(t, "DISK ALMOST FULL warning\n", string(b))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := NewTemplateFormatter(`{{.Message`, nil)
+		assert.Error(t, err)
+
+		f, err := NewTemplateFormatter(`{{colorize "pink" .Message}}`, nil)
+		require.NoError(t, err)
+		_, err = f.Format(e)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,106 @@
+package golog
+
+import "time"
+
+// Layouts of the timestamps formatted without allocating.
+const (
+	layoutRFC3339Milli = "2006-01-02T15:04:05.000Z07:00"
+	layoutRFC3339Micro = "2006-01-02T15:04:05.000000Z07:00"
+	layoutClockMilli   = "15:04:05.000"
+	layoutClock        = "15:04:05"
+)
+
+// isFastTimeLayout reports whether appendTime writes
+// the timestamps of layout digit by digit.
+func isFastTimeLayout(layout string) bool {
+	switch layout {
+	case time.RFC3339, time.RFC3339Nano, layoutRFC3339Milli, layoutRFC3339Micro,
+		layoutClockMilli, layoutClock:
+		return true
+	}
+	return false
+}
+
+// appendTime appends t formatted with layout. The common layouts,
+// time.RFC3339 and time.RFC3339Nano, their millisecond and microsecond
+// variants and the clock with or without milliseconds, are written
+// digit by digit, as time.Time.Format shows up in the profiles at
+// high rates. The other layouts are formatted by time.Time.AppendFormat.
+func appendTime(b []byte, t time.Time, layout string) []byte {
+	if !isFastTimeLayout(layout) {
+		return t.AppendFormat(b, layout)
+	}
+	switch layout {
+	case layoutClockMilli:
+		b = appendClock(b, t)
+		b = append(b, '.')
+		return appendInt(b, t.Nanosecond()/1e6, 3)
+	case layoutClock:
+		return appendClock(b, t)
+	}
+	if year := t.Year(); year < 0 || year > 9999 {
+		return t.AppendFormat(b, layout)
+	}
+	year, month, day := t.Date()
+	b = appendInt(b, year, 4)
+	b = append(b, '-')
+	b = appendInt(b, int(month), 2)
+	b = append(b, '-')
+	b = appendInt(b, day, 2)
+	b = append(b, 'T')
+	b = appendClock(b, t)
+	switch layout {
+	case time.RFC3339Nano:
+		b = appendTrimmedNanos(b, t.Nanosecond())
+	case layoutRFC3339Milli:
+		b = append(b, '.')
+		b = appendInt(b, t.Nanosecond()/1e6, 3)
+	case layoutRFC3339Micro:
+		b = append(b, '.')
+		b = appendInt(b, t.Nanosecond()/1e3, 6)
+	}
+	return appendZone(b, t)
+}
+
+// appendClock appends the hh:mm:ss clock of t.
+func appendClock(b []byte, t time.Time) []byte {
+	hour, min, sec := t.Clock()
+	b = appendInt(b, hour, 2)
+	b = append(b, ':')
+	b = appendInt(b, min, 2)
+	b = append(b, ':')
+	return appendInt(b, sec, 2)
+}
+
+// appendTrimmedNanos appends the fraction of second ns without
+// its trailing zeros, or nothing when ns is zero.
+func appendTrimmedNanos(b []byte, ns int) []byte {
+	if ns == 0 {
+		return b
+	}
+	digits := 9
+	for ns%10 == 0 {
+		ns /= 10
+		digits--
+	}
+	b = append(b, '.')
+	return appendInt(b, ns, digits)
+}
+
+// appendZone appends the offset of the zone of t as Z or ±hh:mm.
+func appendZone(b []byte, t time.Time) []byte {
+	_, offset := t.Zone()
+	if offset == 0 {
+		return append(b, 'Z')
+	}
+	offset /= 60
+	if offset < 0 {
+		b = append(b, '-')
+		offset = -offset
+	} else {
+		b = append(b, '+')
+	}
+	b = appendInt(b, offset/60, 2)
+	b = append(b, ':')
+	return appendInt(b, offset%60, 2)
+}
@@ -0,0 +1,55 @@
+package golog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendTime(t *testing.T) {
+	zones := []*time.Location{
+		time.UTC,
+		time.FixedZone("CET", 3600),
+		time.FixedZone("NST", -(3*3600 + 30*60)),
+	}
+	times := []time.Time{
+		time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		time.Date(2020, 12, 31, 23, 59, 59, 999999999, time.UTC),
+		time.Date(1999, 6, 7, 8, 9, 10, 120000000, time.UTC),
+		time.Date(1, 1, 1, 0, 0, 0, 1000, time.UTC),
+		time.Date(12345, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	layouts := []string{
+		time.RFC3339, time.RFC3339Nano, layoutRFC3339Milli, layoutRFC3339Micro,
+		layoutClockMilli, layoutClock, time.Kitchen,
+	}
+	for _, zone := range zones {
+		for _, ts := range times {
+			ts = ts.In(zone)
+			for _, layout := range layouts {
+				assert.Equal(t, ts.Format(layout), string(appendTime(nil, ts, layout)), "%v %q", ts, layout)
+			}
+		}
+	}
+}
+
+func TestAppendTime_Allocs(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 678900000, time.FixedZone("CET", 3600))
+	buf := make([]byte, 0, 64)
+	for _, layout := range []string{time.RFC3339Nano, layoutRFC3339Milli, layoutClockMilli} {
+		allocs := testing.AllocsPerRun(100, func() {
+			buf = appendTime(buf[:0], ts, layout)
+		})
+		assert.Zero(t, allocs, layout)
+	}
+}
+
+func BenchmarkJSONFormatter(b *testing.B) {
+	f := &JSONFormatter{}
+	e := &Entry{Time: time.Now(), Level: InfoLevel, Message: "hello", Fields: Fields{"user": 42}}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		f.Format(e)
+	}
+}
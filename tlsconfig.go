@@ -0,0 +1,101 @@
+package golog
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+)
+
+// TLSConfig is the TLS configuration of the network sinks, e.g. in
+// YAML:
+//
+//	ca_file: /etc/pki/ca.pem
+//	cert_file: /etc/pki/client.pem
+//	key_file: /etc/pki/client-key.pem
+//	server_name: logs.internal
+//
+// Its Build method returns the *tls.Config taken by NewTCPWriter,
+// SyslogConfig and NewRemoteSessionWriter. Setting both CertFile and
+// KeyFile enables the client authentication of mutual TLS.
+type TLSConfig struct {
+	// CAFile is the PEM file of the certificate authorities verifying
+	// the server. Defaults to the certificate pool of the system.
+	CAFile string `json:"ca_file" yaml:"ca_file"`
+	// CertFile and KeyFile are the PEM files of the client
+	// certificate and of its private key.
+	CertFile string `json:"cert_file" yaml:"cert_file"`
+	KeyFile  string `json:"key_file" yaml:"key_file"`
+	// ServerName is the name verified in the server certificate.
+	// Defaults to the host of the address of the sink.
+	ServerName string `json:"server_name" yaml:"server_name"`
+	// MinVersion is the minimum TLS version, "1.0", "1.1", "1.2" or
+	// "1.3". Defaults to "1.2".
+	MinVersion string `json:"min_version" yaml:"min_version"`
+	// InsecureSkipVerify disables the verification of the server
+	// certificate. It is meant for the tests only.
+	InsecureSkipVerify bool `json:"insecure_skip_verify" yaml:"insecure_skip_verify"`
+}
+
+// tlsVersions are the TLS versions of TLSConfig.MinVersion.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// Build loads the files of c and returns the resulting *tls.Config.
+func (c TLSConfig) Build() (*tls.Config, error) {
+	cfg := &tls.Config{
+		ServerName:         c.ServerName,
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.MinVersion != "" {
+		v, ok := tlsVersions[c.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("golog: tls: unknown min version %q", c.MinVersion)
+		}
+		cfg.MinVersion = v
+	}
+	if c.CAFile != "" {
+		pem, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("golog: tls: %v", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("golog: tls: no certificate in %s", c.CAFile)
+		}
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("golog: tls: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// NewTCPWriter returns a SocketWriter sending every entry as a
+// length-prefixed frame to the TCP address addr, e.g. to a Forwarder
+// serving a TCP listener. tlsConfig, when not nil, secures the
+// connection.
+func NewTCPWriter(addr string, tlsConfig *tls.Config) *SocketWriter {
+	return newSocketWriter(func() (io.WriteCloser, error) {
+		return dialNetwork("tcp", addr, tlsConfig)
+	})
+}
+
+// dialNetwork connects to addr on network, over TLS
+// when tlsConfig is not nil.
+func dialNetwork(network, addr string, tlsConfig *tls.Config) (net.Conn, error) {
+	if tlsConfig != nil {
+		return tls.Dial(network, addr, tlsConfig)
+	}
+	return net.Dial(network, addr)
+}
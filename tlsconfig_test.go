@@ -0,0 +1,184 @@
+package golog
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPKI is a certificate authority with the certificates
+// it issued, written as PEM files in dir.
+type testPKI struct {
+	dir  string
+	ca   *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	dir, err := ioutil.TempDir("", "golog")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	p := &testPKI{dir: dir, ca: ca, key: key, pool: x509.NewCertPool()}
+	p.pool.AddCert(ca)
+	p.write(t, "ca.pem", "CERTIFICATE", der)
+	return p
+}
+
+// issue issues a certificate for name, written as name.pem
+// and name-key.pem.
+func (p *testPKI) issue(t *testing.T, name string, usage x509.ExtKeyUsage) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, p.ca, &key.PublicKey, p.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	p.write(t, name+".pem", "CERTIFICATE", der)
+	p.write(t, name+"-key.pem", "EC PRIVATE KEY", keyDER)
+	cert, err := tls.LoadX509KeyPair(p.path(name+".pem"), p.path(name+"-key.pem"))
+	require.NoError(t, err)
+	return cert
+}
+
+func (p *testPKI) path(name string) string {
+	return filepath.Join(p.dir, name)
+}
+
+func (p *testPKI) write(t *testing.T, name, typ string, der []byte) {
+	b := pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+	require.NoError(t, ioutil.WriteFile(p.path(name), b, 0600))
+}
+
+func TestTLSConfig_Build(t *testing.T) {
+	pki := newTestPKI(t)
+	pki.issue(t, "client", x509.ExtKeyUsageClientAuth)
+
+	cfg, err := TLSConfig{}.Build()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+	assert.Nil(t, cfg.RootCAs)
+	assert.Empty(t, cfg.Certificates)
+
+	cfg, err = TLSConfig{
+		CAFile:     pki.path("ca.pem"),
+		CertFile:   pki.path("client.pem"),
+		KeyFile:    pki.path("client-key.pem"),
+		ServerName: "logs.internal",
+		MinVersion: "1.3",
+	}.Build()
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), cfg.MinVersion)
+	assert.Equal(t, "logs.internal", cfg.ServerName)
+	assert.NotNil(t, cfg.RootCAs)
+	assert.Len(t, cfg.Certificates, 1)
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := TLSConfig{MinVersion: "1.4"}.Build()
+		assert.EqualError(t, err, `golog: tls: unknown min version "1.4"`)
+		_, err = TLSConfig{CAFile: pki.path("client-key.pem")}.Build()
+		assert.EqualError(t, err, "golog: tls: no certificate in "+pki.path("client-key.pem"))
+		_, err = TLSConfig{CAFile: pki.path("missing.pem")}.Build()
+		assert.Error(t, err)
+		_, err = TLSConfig{CertFile: pki.path("client.pem")}.Build()
+		assert.Error(t, err)
+	})
+}
+
+func TestNewTCPWriter_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	server := pki.issue(t, "logs.internal", x509.ExtKeyUsageServerAuth)
+	pki.issue(t, "client", x509.ExtKeyUsageClientAuth)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{server},
+		ClientCAs:    pki.pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	require.NoError(t, err)
+	var sink syncBuffer
+	f := NewForwarder(&sink)
+	go f.Serve(ln)
+	defer f.Close()
+
+	dial := func(c TLSConfig) (*SocketWriter, error) {
+		c.ServerName = "logs.internal"
+		cfg, err := c.Build()
+		if err != nil {
+			return nil, err
+		}
+		return NewTCPWriter(ln.Addr().String(), cfg), nil
+	}
+
+	w, err := dial(TLSConfig{
+		CAFile:   pki.path("ca.pem"),
+		CertFile: pki.path("client.pem"),
+		KeyFile:  pki.path("client-key.pem"),
+	})
+	require.NoError(t, err)
+	defer w.Close()
+	_, err = w.Write([]byte("INFO: secured\n"))
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return sink.String() == "INFO: secured\n" }, time.Second, 10*time.Millisecond)
+
+	t.Run("unknown server", func(t *testing.T) {
+		w, err := dial(TLSConfig{})
+		require.NoError(t, err)
+		defer w.Close()
+		_, err = w.Write([]byte("INFO: lost\n"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "certificate signed by unknown authority")
+	})
+
+	t.Run("plain TCP", func(t *testing.T) {
+		plain, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		var sink syncBuffer
+		f := NewForwarder(&sink)
+		go f.Serve(plain)
+		defer f.Close()
+		w := NewTCPWriter(plain.Addr().String(), nil)
+		defer w.Close()
+		_, err = w.Write([]byte("INFO: plain\n"))
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool { return sink.String() == "INFO: plain\n" }, time.Second, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,51 @@
+package golog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	// userMu protects userOutput and serializes the user messages.
+	userMu sync.Mutex
+	// userOutput receives the user-facing messages.
+	userOutput io.Writer = os.Stderr
+)
+
+// SetUserOutput sets the destination of the user-facing messages
+// written by UserInfo and UserInfof. It defaults to os.Stderr.
+func SetUserOutput(w io.Writer) {
+	userMu.Lock()
+	defer userMu.Unlock()
+	userOutput = w
+}
+
+// UserInfo writes a user-facing message, formatted as fmt.Sprint,
+// as plain text to the user output. Unlike Info, it bypasses the
+// formatters, hooks and outputs of the loggers, so the messages
+// shown to the users of a CLI don't mix with the diagnostics. It
+// is silent when the InfoLevel is disabled, e.g. in quiet mode.
+func UserInfo(v ...interface{}) {
+	writeUser(fmt.Sprint(v...))
+}
+
+// UserInfof is like UserInfo but formats the message
+// as fmt.Sprintf.
+func UserInfof(format string, v ...interface{}) {
+	writeUser(fmt.Sprintf(format, v...))
+}
+
+func writeUser(msg string) {
+	if !Enabled(InfoLevel) {
+		return
+	}
+	if !strings.HasSuffix(msg, "\n") {
+		msg += "\n"
+	}
+	userMu.Lock()
+	defer userMu.Unlock()
+	io.WriteString(userOutput, msg)
+}
@@ -0,0 +1,31 @@
+package golog
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserInfo(t *testing.T) {
+	defer SetUserOutput(os.Stderr)
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	user, diag := &syncBuffer{}, &syncBuffer{}
+	SetUserOutput(user)
+	SetOutput(diag)
+
+	UserInfo("Pulling image…")
+	UserInfof("Pulled %d layers\n", 3)
+	Info("layer cache hit")
+	assert.Equal(t, "Pulling image…\nPulled 3 layers\n", user.String())
+	assert.NotContains(t, diag.String(), "Pulling")
+	assert.Contains(t, diag.String(), "layer cache hit")
+
+	t.Run("silent in quiet mode", func(t *testing.T) {
+		SetLevel(ErrorLevel)
+		UserInfo("hidden")
+		assert.NotContains(t, user.String(), "hidden")
+	})
+}
@@ -0,0 +1,123 @@
+package golog
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// verboseCapture enables all the levels for the entries
+// whose field key holds value, until its deadline.
+type verboseCapture struct {
+	id    uint64
+	key   string
+	value string
+	until time.Time
+}
+
+// VerboseCapture describes an active verbose capture.
+type VerboseCapture struct {
+	Key   string    `json:"key"`
+	Value string    `json:"value"`
+	Until time.Time `json:"until"`
+}
+
+var captureID uint64
+
+// CaptureVerbose enables all the levels, down to Debug, for the entries
+// of the loggers whose field key holds value, for the duration d. The
+// other entries keep the global level. It is meant for "debug this one
+// customer" workflows:
+//
+//	cancel := golog.CaptureVerbose("user_id", 123, 15*time.Minute)
+//	defer cancel()
+func CaptureVerbose(key string, value interface{}, d time.Duration) (cancel func()) {
+	c := verboseCapture{
+		id:    atomic.AddUint64(&captureID, 1),
+		key:   key,
+		value: fmt.Sprint(value),
+		until: now().Add(d),
+	}
+	updateState(func(s *globalState) {
+		s.captures = append(activeCaptures(s.captures, now()), c)
+	})
+	return func() {
+		updateState(func(s *globalState) {
+			kept := make([]verboseCapture, 0, len(s.captures))
+			for _, other := range s.captures {
+				if other.id != c.id {
+					kept = append(kept, other)
+				}
+			}
+			s.captures = kept
+		})
+	}
+}
+
+// VerboseCaptures returns the active verbose captures.
+func VerboseCaptures() []VerboseCapture {
+	active := activeCaptures(getState().captures, now())
+	out := make([]VerboseCapture, len(active))
+	for i, c := range active {
+		out[i] = VerboseCapture{Key: c.key, Value: c.value, Until: c.until}
+	}
+	return out
+}
+
+// activeCaptures returns a copy of the captures
+// that are not expired at t.
+func activeCaptures(captures []verboseCapture, t time.Time) []verboseCapture {
+	active := make([]verboseCapture, 0, len(captures)+1)
+	for _, c := range captures {
+		if t.Before(c.until) {
+			active = append(active, c)
+		}
+	}
+	return active
+}
+
+// isCaptured reports whether the fields match
+// one of the active captures of the state.
+func (s *globalState) isCaptured(fields Fields) bool {
+	if len(s.captures) == 0 || len(fields) == 0 {
+		return false
+	}
+	t := now()
+	for _, c := range s.captures {
+		if v, ok := fields[c.key]; ok && t.Before(c.until) && fmt.Sprint(v) == c.value {
+			return true
+		}
+	}
+	return false
+}
+
+// VerboseCaptureHandler returns an http.Handler to manage the
+// verbose captures at runtime. GET lists the active captures as
+// JSON and POST starts one from the key, value and duration
+// query parameters, e.g.
+//
+//	POST /debug/capture?key=user_id&value=123&duration=15m
+func VerboseCaptureHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			q := r.URL.Query()
+			key, value := q.Get("key"), q.Get("value")
+			d, err := time.ParseDuration(q.Get("duration"))
+			if key == "" || value == "" || err != nil || d <= 0 {
+				http.Error(w, "key, value and a positive duration are required", http.StatusBadRequest)
+				return
+			}
+			CaptureVerbose(key, value, d)
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(VerboseCaptures())
+	})
+}
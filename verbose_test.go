@@ -0,0 +1,88 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureVerbose(t *testing.T) {
+	defer func() { now = time.Now }()
+	defer SetLevel(InfoLevel)
+	clock := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	SetLevel(InfoLevel)
+
+	var out bytes.Buffer
+	l := newStdLogger(DebugLevel, &out, 0)
+
+	cancel := CaptureVerbose("user_id", 123, time.Minute)
+	l.WithFields(Fields{"user_id": 123}).Print("captured")
+	l.WithFields(Fields{"user_id": 456}).Print("other user")
+	l.Print("no user")
+	assert.Equal(t, "DEBUG: captured user_id=123\n", out.String())
+	assert.Len(t, VerboseCaptures(), 1)
+
+	t.Run("fields of the context", func(t *testing.T) {
+		out.Reset()
+		ctx := WithContext(context.Background(), l)
+		FromContext(ContextWithFields(ctx, Fields{"user_id": 123})).Debug("from the context")
+		FromContext(ContextWithFields(ctx, Fields{"user_id": 456})).Debug("other user")
+
+		lr := NewLogrusLogger(DebugLevel)
+		lr.SetOutput(&out)
+		lr.SetFormatter(&TextFormatter{})
+		ctx = WithContext(context.Background(), lr)
+		FromContext(ContextWithFields(ctx, Fields{"user_id": 123})).Debug("logrus")
+
+		SetGlobalFields(Fields{"user_id": 123})
+		defer SetGlobalFields(nil)
+		l.Print("global")
+		assert.Equal(t, "DEBUG: from the context user_id=123\nDEBUG: logrus user_id=123\nDEBUG: global user_id=123\n", out.String())
+	})
+	t.Run("expires after the duration", func(t *testing.T) {
+		out.Reset()
+		clock = clock.Add(time.Minute)
+		l.WithFields(Fields{"user_id": 123}).Print("expired")
+		assert.Empty(t, out.String())
+		assert.Empty(t, VerboseCaptures())
+	})
+	t.Run("cancel", func(t *testing.T) {
+		cancel()
+		assert.Empty(t, getState().captures)
+	})
+}
+
+func TestVerboseCaptureHandler(t *testing.T) {
+	defer func() {
+		updateState(func(s *globalState) { s.captures = nil })
+	}()
+	h := VerboseCaptureHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/?key=request_id&value=abc&duration=15m", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var got []VerboseCapture
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	require.Len(t, got, 1)
+	assert.Equal(t, "request_id", got[0].Key)
+	assert.Equal(t, "abc", got[0].Value)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/?key=request_id", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -0,0 +1,101 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// maxLineSize is the longest line buffered by a lineWriter. Longer
+// lines are split into several entries.
+const maxLineSize = 64 << 10
+
+// lineWriter is an io.WriteCloser calling emit with every line
+// written to it, without the line terminator.
+type lineWriter struct {
+	emit func(line string)
+
+	mu     sync.Mutex
+	buf    []byte
+	closed bool
+}
+
+func newLineWriter(emit func(line string)) *lineWriter {
+	return &lineWriter{emit: emit}
+}
+
+// Write emits the complete lines of p and buffers the rest
+// until the next newline or Close.
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.emitLine(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	for len(w.buf) >= maxLineSize {
+		w.emitLine(w.buf[:maxLineSize])
+		w.buf = w.buf[maxLineSize:]
+	}
+	if len(w.buf) == 0 {
+		w.buf = nil
+	}
+	return len(p), nil
+}
+
+// Close emits the pending incomplete line.
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.buf) > 0 {
+		w.emitLine(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *lineWriter) emitLine(line []byte) {
+	w.emit(strings.TrimSuffix(string(line), "\r"))
+}
+
+// Writer returns a writer emitting every line written to it as an
+// entry of l, e.g. to log the output of an exec.Cmd. The call site
+// of the entries is not reported. Close emits the last line when it
+// doesn't end with a newline.
+func (l *stdLogger) Writer() io.WriteCloser {
+	return newLineWriter(func(line string) {
+		if l.isPrint() {
+			l.write(0, l.level, line, "", writeDefault, nil)
+		}
+	})
+}
+
+// Writer returns a writer emitting every line written
+// to it as an entry of l.
+func (l *Logrus) Writer() io.WriteCloser {
+	return newLineWriter(func(line string) {
+		l.Print(line)
+	})
+}
+
+// WriterLevel returns a writer emitting every line written to it
+// with the package level logger of lvl. See Logger.Writer.
+func WriterLevel(lvl Level) io.WriteCloser {
+	if l := packageLogger(lvl); l != nil {
+		return l.Writer()
+	}
+	return newLineWriter(func(string) {})
+}
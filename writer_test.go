@@ -0,0 +1,72 @@
+package golog
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	w := newLineWriter(func(line string) { lines = append(lines, line) })
+
+	fmt.Fprint(w, "first\nsec")
+	fmt.Fprint(w, "ond\r\nthird")
+	assert.Equal(t, []string{"first", "second"}, lines)
+	assert.NoError(t, w.Close())
+	assert.Equal(t, []string{"first", "second", "third"}, lines)
+	_, err := w.Write([]byte("late\n"))
+	assert.Equal(t, ErrWriterClosed, err)
+
+	t.Run("long lines are split", func(t *testing.T) {
+		lines = nil
+		w := newLineWriter(func(line string) { lines = append(lines, line) })
+		fmt.Fprint(w, strings.Repeat("x", maxLineSize+10))
+		w.Close()
+		if assert.Len(t, lines, 2) {
+			assert.Len(t, lines[0], maxLineSize)
+			assert.Len(t, lines[1], 10)
+		}
+	})
+}
+
+func TestStdLogger_Writer(t *testing.T) {
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	l := newStdLogger(WarningLevel, out, log.Lshortfile)
+	w := l.WithFields(Fields{"cmd": "make"}).Writer()
+	fmt.Fprint(w, "compiling\nlinking\n")
+	w.Close()
+	assert.Equal(t, "WARNING: ???:0: compiling cmd=make\nWARNING: ???:0: linking cmd=make\n", out.String())
+}
+
+func TestWriterLevel(t *testing.T) {
+	defer SetOutput(os.Stdout)
+	defer SetLevel(InfoLevel)
+	SetLevel(InfoLevel)
+	out := &syncBuffer{}
+	SetOutput(out)
+
+	fmt.Fprintln(WriterLevel(DebugLevel), "filtered")
+	fmt.Fprintln(WriterLevel(DisabledLevel), "discarded")
+	assert.Empty(t, out.String())
+
+	w := WriterLevel(ErrorLevel)
+	fmt.Fprintln(w, "proxy error")
+	assert.Contains(t, out.String(), "ERROR: ")
+	assert.Contains(t, out.String(), "proxy error\n")
+
+	t.Run("logrus", func(t *testing.T) {
+		out.Reset()
+		l := NewLogrusLogger(InfoLevel)
+		l.SetOutput(out)
+		l.SetFormatter(&TextFormatter{})
+		fmt.Fprintln(l.Writer(), "from logrus")
+		assert.Equal(t, "INFO: from logrus\n", out.String())
+	})
+}